@@ -2,15 +2,19 @@ package square
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/layout"
 	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
 	"github.com/celestiaorg/go-square/v2/share"
 	"github.com/celestiaorg/go-square/v2/tx"
 	"golang.org/x/exp/constraints"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -20,100 +24,367 @@ type Builder struct {
 	// currentSize is an overestimate for the number of shares used by this builder.
 	currentSize int
 
-	// here we keep track of the pending data to go in a square
+	// here we keep track of the pending data to go in a square. Blobs are kept
+	// in priority order but are sorted by namespace in place by Export, use
+	// BlobElements for a stable view.
 	Txs   [][]byte
+	Isrs  [][]byte
 	Pfbs  []*v1.IndexWrapper
 	Blobs []*Element
 
 	// for compact shares we use a counter to track the amount of shares needed
 	TxCounter  *share.CompactShareCounter
+	IsrCounter *share.CompactShareCounter
 	PfbCounter *share.CompactShareCounter
 
 	done                 bool
 	subtreeRootThreshold int
+	version              SquareVersion
+	namespaces           ReservedNamespaces
+
+	// optional limits on the blobs in the square along with the current
+	// total size of the blob data
+	maxBlobCount int
+	maxBlobBytes int
+	blobBytes    int
+
+	// namespacePolicy, if set, decides which blob namespaces are allowed
+	namespacePolicy NamespacePolicy
+	// namespaceOpts are used when decoding the blobs of raw blob txs
+	namespaceOpts []share.NamespaceOption
+	// contiguousPFBBlobs requires the blobs of each PFB to be placed next to
+	// each other
+	contiguousPFBBlobs bool
+	// shareArena backs all shares of the exported square with a single buffer
+	shareArena bool
+	// exportProgress, if set, is called periodically during Export
+	exportProgress ExportProgressFunc
+	// appends records every append that can be undone by RevertLastN
+	appends []appendRecord
 }
 
+// NewBuilder returns a builder using the DefaultSquareVersion rules that is
+// initialized with the exact list of ordered transactions.
 func NewBuilder(maxSquareSize int, subtreeRootThreshold int, txs ...[]byte) (*Builder, error) {
+	return NewBuilderWithVersion(DefaultSquareVersion, maxSquareSize, subtreeRootThreshold, txs...)
+}
+
+// NewBuilderWithVersion behaves like NewBuilder but places blobs according to
+// the rules of the provided square version.
+func NewBuilderWithVersion(version SquareVersion, maxSquareSize int, subtreeRootThreshold int, txs ...[]byte) (*Builder, error) {
+	builder, err := NewBuilderWithOptions(maxSquareSize, subtreeRootThreshold, WithSquareVersion(version))
+	if err != nil {
+		return nil, err
+	}
+	if rejection := builder.appendOrderedTxs(txs); rejection != nil {
+		return nil, rejection.Err
+	}
+	return builder, nil
+}
+
+// NewBuilderWithOptions returns an empty builder configured with the provided
+// options.
+func NewBuilderWithOptions(maxSquareSize int, subtreeRootThreshold int, opts ...BuilderOption) (*Builder, error) {
 	if maxSquareSize <= 0 {
-		return nil, errors.New("max square size must be strictly positive")
+		return nil, fmt.Errorf("%w: max square size must be strictly positive", ErrInvalidMaxSquareSize)
 	}
 	if !IsPowerOfTwo(maxSquareSize) {
-		return nil, errors.New("max square size must be a power of two")
+		return nil, fmt.Errorf("%w: max square size must be a power of two", ErrInvalidMaxSquareSize)
 	}
 	builder := &Builder{
 		maxSquareSize:        maxSquareSize,
 		subtreeRootThreshold: subtreeRootThreshold,
+		version:              DefaultSquareVersion,
+		namespaces:           DefaultReservedNamespaces(),
 		Blobs:                make([]*Element, 0),
 		Pfbs:                 make([]*v1.IndexWrapper, 0),
 		Txs:                  make([][]byte, 0),
 		TxCounter:            share.NewCompactShareCounter(),
+		IsrCounter:           share.NewCompactShareCounter(),
 		PfbCounter:           share.NewCompactShareCounter(),
 	}
+	for _, opt := range opts {
+		opt(builder)
+	}
+	if err := builder.version.Validate(); err != nil {
+		return nil, err
+	}
+	if err := builder.namespaces.Validate(); err != nil {
+		return nil, err
+	}
+	return builder, nil
+}
+
+// appendOrderedTxs appends the exact list of ordered transactions to the
+// builder. It returns the rejection of the first transaction that could not be
+// appended or nil if all transactions were appended.
+func (b *Builder) appendOrderedTxs(txs [][]byte) *TxRejection {
 	seenFirstBlobTx := false
 	for idx, txBytes := range txs {
-		blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
+		blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes, b.namespaceOpts...)
 		if err != nil && isBlobTx {
-			return nil, fmt.Errorf("unmarshalling blob tx at index %d: %w", idx, err)
+			return &TxRejection{Index: idx, Reason: RejectionInvalidBlobTx, Err: &TxError{Index: idx, Err: fmt.Errorf("%w: %w", ErrInvalidBlobTx, err)}}
 		}
 		if isBlobTx {
 			seenFirstBlobTx = true
-			if !builder.AppendBlobTx(blobTx) {
-				return nil, fmt.Errorf("not enough space to append blob tx at index %d", idx)
+			if err := b.TryAppendBlobTx(blobTx); err != nil {
+				reason := RejectionSquareFull
+				switch {
+				case errors.Is(err, ErrBlobCountExceeded) || errors.Is(err, ErrBlobBytesExceeded):
+					reason = RejectionBlobLimitExceeded
+				case errors.Is(err, ErrNamespaceDenied):
+					reason = RejectionNamespaceDenied
+				}
+				return &TxRejection{Index: idx, Reason: reason, Err: &TxError{Index: idx, Err: err}}
 			}
 		} else {
 			if seenFirstBlobTx {
-				return nil, fmt.Errorf("normal tx at index %d can not be appended after blob tx", idx)
+				return &TxRejection{Index: idx, Reason: RejectionTxAfterBlobTx, Err: &TxError{Index: idx, Err: ErrTxAfterBlobTx}}
 			}
-			if !builder.AppendTx(txBytes) {
-				return nil, fmt.Errorf("not enough space to append tx at index %d", idx)
+			if err := b.TryAppendTx(txBytes); err != nil {
+				return &TxRejection{Index: idx, Reason: RejectionSquareFull, Err: &TxError{Index: idx, Err: err}}
 			}
 		}
 	}
-	return builder, nil
+	return nil
 }
 
 // AppendTx attempts to allocate the transaction to the square. It returns false if there is not
 // enough space in the square to fit the transaction.
 func (b *Builder) AppendTx(tx []byte) bool {
+	return b.TryAppendTx(tx) == nil
+}
+
+// TryAppendTx behaves like AppendTx but returns an *InsufficientSpaceError if
+// there is not enough space in the square to fit the transaction.
+func (b *Builder) TryAppendTx(tx []byte) error {
+	counter := *b.TxCounter
 	lenChange := b.TxCounter.Add(len(tx))
 	if b.canFit(lenChange) {
+		b.appends = append(b.appends, appendRecord{kind: appendTx, sizeDelta: lenChange, elements: 1, counter: counter})
 		b.Txs = append(b.Txs, tx)
 		b.currentSize += lenChange
 		b.done = false
-		return true
+		return nil
 	}
 	b.TxCounter.Revert()
-	return false
+	return b.insufficientSpace(lenChange)
+}
+
+// SetIntermediateStateRoots sets the intermediate state roots that are written
+// to the IntermediateStateRootsNamespace, between the transactions and the
+// PFBs, replacing any previously set roots. It returns false if there is not
+// enough space in the square to fit the roots.
+func (b *Builder) SetIntermediateStateRoots(roots [][]byte) bool {
+	isrCounter := share.NewCompactShareCounter()
+	for _, root := range roots {
+		isrCounter.Add(len(root))
+	}
+	lenChange := isrCounter.Size() - b.IsrCounter.Size()
+	if !b.canFit(lenChange) {
+		return false
+	}
+	b.Isrs = roots
+	b.IsrCounter = isrCounter
+	b.currentSize += lenChange
+	b.done = false
+	b.appends = b.appends[:0]
+	return true
 }
 
 // AppendBlobTx attempts to allocate the blob transaction to the square. It returns false if there is not
-// enough space in the square to fit the transaction.
+// enough space in the square to fit the transaction, if it would exceed the configured blob limits or
+// if the namespace policy denies one of its blobs.
 func (b *Builder) AppendBlobTx(blobTx *tx.BlobTx) bool {
-	iw := tx.NewIndexWrapper(blobTx.Tx, worstCaseShareIndexes(len(blobTx.Blobs))...)
-	size := proto.Size(iw)
-	pfbShareDiff := b.PfbCounter.Add(size)
+	return b.TryAppendBlobTx(blobTx) == nil
+}
+
+// TryAppendBlobTx behaves like AppendBlobTx but returns an error describing
+// why the blob transaction could not be added. The error is
+// ErrBlobCountExceeded or ErrBlobBytesExceeded if a configured limit would be
+// exceeded and a *NamespaceDeniedError if the namespace policy denies a blob.
+func (b *Builder) TryAppendBlobTx(blobTx *tx.BlobTx) error {
+	if err := b.checkNamespacePolicy(blobTx); err != nil {
+		return err
+	}
+	blobBytes := b.blobBytes + blobTx.TotalBlobSize()
+	if err := b.checkBlobLimits(len(b.Blobs)+len(blobTx.Blobs), blobBytes); err != nil {
+		return err
+	}
+
+	iw := tx.NewIndexWrapperWithHeight(blobTx.Tx, blobTx.HeightHint, tx.WorstCaseShareIndexes(len(blobTx.Blobs))...)
+	counter := *b.PfbCounter
+	pfbShareDiff := b.PfbCounter.Add(tx.WorstCaseIndexWrapperSizeWithHeight(len(blobTx.Tx), len(blobTx.Blobs), blobTx.HeightHint))
 
 	// create a new blob element for each blob and track the worst-case share count
 	blobElements := make([]*Element, len(blobTx.Blobs))
 	maxBlobShareCount := 0
 	for idx, blob := range blobTx.Blobs {
-		blobElements[idx] = newElement(blob, len(b.Pfbs), idx, b.subtreeRootThreshold)
+		blobElements[idx] = b.newElement(blob, len(b.Pfbs), idx)
 		maxBlobShareCount += blobElements[idx].maxShareOffset()
 	}
 
 	if b.canFit(pfbShareDiff + maxBlobShareCount) {
+		b.appends = append(b.appends, appendRecord{
+			kind:      appendBlobTx,
+			sizeDelta: pfbShareDiff + maxBlobShareCount,
+			elements:  len(blobElements),
+			blobBytes: blobBytes - b.blobBytes,
+			counter:   counter,
+		})
 		b.Blobs = append(b.Blobs, blobElements...)
 		b.Pfbs = append(b.Pfbs, iw)
 		b.currentSize += (pfbShareDiff + maxBlobShareCount)
+		b.blobBytes = blobBytes
 		b.done = false
-		return true
+		return nil
 	}
 	b.PfbCounter.Revert()
-	return false
+	return b.insufficientSpace(pfbShareDiff + maxBlobShareCount)
+}
+
+// InsertBlobTx attempts to allocate the blob transaction to the square at the
+// given position amongst the PFBs, shifting all subsequent PFBs by one. A
+// position equal to NumPFBs is equivalent to AppendBlobTx. It returns false if
+// the position is out of range or there is not enough space in the square to
+// fit the transaction.
+func (b *Builder) InsertBlobTx(position int, blobTx *tx.BlobTx) bool {
+	if position < 0 || position > len(b.Pfbs) {
+		return false
+	}
+	if position == len(b.Pfbs) {
+		return b.AppendBlobTx(blobTx)
+	}
+	if b.checkBlobLimits(len(b.Blobs)+len(blobTx.Blobs), b.blobBytes+blobTx.TotalBlobSize()) != nil {
+		return false
+	}
+	if b.checkNamespacePolicy(blobTx) != nil {
+		return false
+	}
+
+	iw := tx.NewIndexWrapperWithHeight(blobTx.Tx, blobTx.HeightHint, tx.WorstCaseShareIndexes(len(blobTx.Blobs))...)
+	pfbs := make([]*v1.IndexWrapper, 0, len(b.Pfbs)+1)
+	pfbs = append(pfbs, b.Pfbs[:position]...)
+	pfbs = append(pfbs, iw)
+	pfbs = append(pfbs, b.Pfbs[position:]...)
+
+	blobs := make([]*Element, 0, len(b.Blobs)+len(blobTx.Blobs))
+	for _, element := range b.Blobs {
+		shifted := *element
+		if shifted.PfbIndex >= position {
+			shifted.PfbIndex++
+		}
+		blobs = append(blobs, &shifted)
+	}
+	for idx, blob := range blobTx.Blobs {
+		blobs = append(blobs, b.newElement(blob, position, idx))
+	}
+
+	pfbCounter, size := b.recomputeSize(pfbs, blobs)
+	if size > b.maxSquareSize*b.maxSquareSize {
+		return false
+	}
+	b.setPfbs(pfbs, blobs, pfbCounter, size)
+	return true
+}
+
+// RemoveBlobTx removes the PFB at pfbIndex, along with its blobs, from the
+// builder and shifts all subsequent PFBs by one. Note that pfbIndex is the
+// index amongst the PFBs and not amongst all transactions.
+func (b *Builder) RemoveBlobTx(pfbIndex int) error {
+	if pfbIndex < 0 || pfbIndex >= len(b.Pfbs) {
+		return fmt.Errorf("%w: pfbIndex %d", ErrIndexOutOfRange, pfbIndex)
+	}
+
+	pfbs := make([]*v1.IndexWrapper, 0, len(b.Pfbs)-1)
+	pfbs = append(pfbs, b.Pfbs[:pfbIndex]...)
+	pfbs = append(pfbs, b.Pfbs[pfbIndex+1:]...)
+
+	blobs := make([]*Element, 0, len(b.Blobs))
+	for _, element := range b.Blobs {
+		if element.PfbIndex == pfbIndex {
+			continue
+		}
+		shifted := *element
+		if shifted.PfbIndex > pfbIndex {
+			shifted.PfbIndex--
+		}
+		blobs = append(blobs, &shifted)
+	}
+
+	pfbCounter, size := b.recomputeSize(pfbs, blobs)
+	b.setPfbs(pfbs, blobs, pfbCounter, size)
+	return nil
 }
 
-// Export constructs the square.
+// recomputeSize recounts the PFB shares from scratch using the worst-case
+// share indexes of each PFB and returns the new PFB counter along with the
+// resulting size of the square.
+func (b *Builder) recomputeSize(pfbs []*v1.IndexWrapper, blobs []*Element) (*share.CompactShareCounter, int) {
+	pfbCounter := share.NewCompactShareCounter()
+	for _, iw := range pfbs {
+		pfbCounter.Add(tx.WorstCaseIndexWrapperSizeWithHeight(len(iw.Tx), len(iw.ShareIndexes), iw.HeightHint))
+	}
+	size := b.TxCounter.Size() + b.IsrCounter.Size() + pfbCounter.Size()
+	for _, element := range blobs {
+		size += element.maxShareOffset()
+	}
+	return pfbCounter, size
+}
+
+func (b *Builder) setPfbs(pfbs []*v1.IndexWrapper, blobs []*Element, pfbCounter *share.CompactShareCounter, size int) {
+	// restore the priority order of the blobs which may have been sorted by
+	// namespace in a previous call to Export
+	sort.SliceStable(blobs, func(i, j int) bool {
+		if blobs[i].PfbIndex != blobs[j].PfbIndex {
+			return blobs[i].PfbIndex < blobs[j].PfbIndex
+		}
+		return blobs[i].BlobIndex < blobs[j].BlobIndex
+	})
+	b.Pfbs = pfbs
+	b.Blobs = blobs
+	b.blobBytes = 0
+	for _, element := range blobs {
+		b.blobBytes += element.Blob.DataLen()
+	}
+	b.PfbCounter = pfbCounter
+	b.currentSize = size
+	b.done = false
+	b.appends = b.appends[:0]
+}
+
+// Export constructs the square. Export sorts the blobs by namespace and
+// records the share index of each blob in the PFB paying for it. More
+// transactions may be appended after Export, in which case the next call to
+// Export recomputes the layout.
 func (b *Builder) Export() (Square, error) {
+	return b.ExportCtx(context.Background())
+}
+
+// ExportCopy behaves like Export but computes the layout over a copy of the
+// builder's state, leaving the builder untouched: the blobs keep their
+// priority order and the share indexes of the PFBs are not updated.
+func (b *Builder) ExportCopy() (Square, error) {
+	snapshot := *b
+	snapshot.Blobs = slices.Clone(b.Blobs)
+	snapshot.Pfbs = make([]*v1.IndexWrapper, len(b.Pfbs))
+	for i, iw := range b.Pfbs {
+		snapshot.Pfbs[i] = proto.Clone(iw).(*v1.IndexWrapper)
+	}
+	return snapshot.Export()
+}
+
+// exportCtxCheckInterval is the number of transactions or blobs ExportCtx
+// writes between checks of whether the context is done.
+const exportCtxCheckInterval = 64
+
+// ExportCtx behaves like Export but periodically checks whether the context
+// is done, in which case it aborts and returns the context's error. The
+// builder can still be exported after an aborted export.
+func (b *Builder) ExportCtx(ctx context.Context) (Square, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	// if there are no transactions, return an empty square
 	if b.IsEmpty() {
 		return EmptySquare(), nil
@@ -133,26 +404,85 @@ func (b *Builder) Export() (Square, error) {
 		return bytes.Compare(ns1, ns2) < 0
 	})
 
+	if b.contiguousPFBBlobs {
+		if err := checkPFBBlobsContiguous(b.Blobs); err != nil {
+			return nil, err
+		}
+	}
+
+	// the blobs start at the first index permitted by the blob share
+	// commitment rules after the worst-case number of shares reserved for
+	// the compact shares
+	pfbStart := b.TxCounter.Size() + b.IsrCounter.Size()
+	reservedEnd := pfbStart + b.PfbCounter.Size()
+	nonReservedStart := reservedEnd
+	if len(b.Blobs) > 0 {
+		nonReservedStart = b.version.nextShareIndex(reservedEnd, b.Blobs[0].NumShares, b.subtreeRootThreshold)
+	}
+
+	// with a share arena, the splitters write the shares of each region of
+	// the square directly into the arena
+	totalShares := ss * ss
+	var arena []byte
+	if b.shareArena {
+		arena = make([]byte, totalShares*share.ShareSize)
+	}
+	txWriter := share.NewCompactShareSplitterWithBuffer(b.namespaces.Tx, share.ShareVersionZero, arenaRegion(arena, 0, b.TxCounter.Size()))
+	isrWriter := share.NewCompactShareSplitterWithBuffer(b.namespaces.IntermediateStateRoots, share.ShareVersionZero, arenaRegion(arena, b.TxCounter.Size(), pfbStart))
+	pfbWriter := share.NewCompactShareSplitterWithBuffer(b.namespaces.PayForBlob, share.ShareVersionZero, arenaRegion(arena, pfbStart, nonReservedStart))
+	blobWriter := share.NewSparseShareSplitterWithBuffer(arenaRegion(arena, nonReservedStart, totalShares))
+	blobWriter.SetPlacement(nonReservedStart, func(cursor, blobShareLen int) int {
+		return b.version.nextShareIndex(cursor, blobShareLen, b.subtreeRootThreshold)
+	})
+
+	// checkpoint reports the progress and checks whether the context is done
+	// every exportCtxCheckInterval transactions or blobs
+	checkpoint := func(i int) error {
+		if i%exportCtxCheckInterval != 0 {
+			return nil
+		}
+		if b.exportProgress != nil {
+			b.exportProgress(txWriter.Count()+isrWriter.Count()+blobWriter.Count()+pfbWriter.Count(), totalShares)
+		}
+		return ctx.Err()
+	}
+
 	// write all the regular transactions into compact shares
-	txWriter := share.NewCompactShareSplitter(share.TxNamespace, share.ShareVersionZero)
-	for _, tx := range b.Txs {
+	for i, tx := range b.Txs {
+		if err := checkpoint(i); err != nil {
+			return nil, err
+		}
 		if err := txWriter.WriteTx(tx); err != nil {
 			return nil, fmt.Errorf("writing tx into compact shares: %w", err)
 		}
 	}
 
+	// write the intermediate state roots into compact shares
+	for _, isr := range b.Isrs {
+		if err := isrWriter.WriteTx(isr); err != nil {
+			return nil, fmt.Errorf("writing intermediate state root into compact shares: %w", err)
+		}
+	}
+
+	// defensively check that the counters never underestimate the compact
+	// shares as the shares are written into the arena regions derived from them
+	if b.TxCounter.Size() < txWriter.Count() {
+		return nil, fmt.Errorf("txCounter.Size() < txWriter.Count(): %d < %d", b.TxCounter.Size(), txWriter.Count())
+	}
+	if b.IsrCounter.Size() < isrWriter.Count() {
+		return nil, fmt.Errorf("isrCounter.Size() < isrWriter.Count(): %d < %d", b.IsrCounter.Size(), isrWriter.Count())
+	}
+
 	// begin to iteratively add blobs to the sparse share splitter calculating the actual padding
-	nonReservedStart := b.TxCounter.Size() + b.PfbCounter.Size()
-	cursor := nonReservedStart
-	endOfLastBlob := nonReservedStart
-	blobWriter := share.NewSparseShareSplitter()
+	cursor := reservedEnd
+	endOfLastBlob := reservedEnd
 	for i, element := range b.Blobs {
+		if err := checkpoint(i); err != nil {
+			return nil, err
+		}
 		// NextShareIndex returned where the next blob should start so as to comply with the share commitment rules
 		// We fill out the remaining
-		cursor = inclusion.NextShareIndex(cursor, element.NumShares, b.subtreeRootThreshold)
-		if i == 0 {
-			nonReservedStart = cursor
-		}
+		cursor = b.version.nextShareIndex(cursor, element.NumShares, b.subtreeRootThreshold)
 
 		// defensively check that the actual padding never exceeds the max padding initially allocated for it
 		padding := cursor - endOfLastBlob
@@ -162,15 +492,9 @@ func (b *Builder) Export() (Square, error) {
 
 		// record the starting share index of the blob in the PFB that paid for it
 		b.Pfbs[element.PfbIndex].ShareIndexes[element.BlobIndex] = uint32(cursor)
-		// If this is not the first blob, we add padding by writing padded shares to the previous blob
-		// (which could be of a different namespace)
-		if i > 0 {
-			if err := blobWriter.WriteNamespacePaddingShares(padding); err != nil {
-				return nil, fmt.Errorf("writing padding into sparse shares: %w", err)
-			}
-		}
-		// Finally write the blob itself
-		if err := blobWriter.Write(element.Blob); err != nil {
+		// Write the blob at its index. If this is not the first blob, the writer pads the previous
+		// blob (which could be of a different namespace) up to the index
+		if err := blobWriter.WriteAt(element.Blob, cursor-nonReservedStart); err != nil {
 			return nil, fmt.Errorf("writing blob into sparse shares: %w", err)
 		}
 		// increment the cursor by the size of the blob
@@ -180,8 +504,10 @@ func (b *Builder) Export() (Square, error) {
 
 	// write all the pay for blob transactions into compact shares. We need to do this after allocating the blobs to their
 	// appropriate shares as the starting index of each blob needs to be included in the PFB transaction
-	pfbWriter := share.NewCompactShareSplitter(share.PayForBlobNamespace, share.ShareVersionZero)
-	for _, iw := range b.Pfbs {
+	for i, iw := range b.Pfbs {
+		if err := checkpoint(i); err != nil {
+			return nil, err
+		}
 		iwBytes, err := proto.Marshal(iw)
 		if err != nil {
 			return nil, fmt.Errorf("marshaling pay for blob tx: %w", err)
@@ -197,12 +523,19 @@ func (b *Builder) Export() (Square, error) {
 	}
 
 	// Write out the square
-	square, err := WriteSquare(txWriter, pfbWriter, blobWriter, nonReservedStart, ss)
+	square, err := writeSquare([]ReservedWriter{
+		{Namespace: b.namespaces.Tx, Writer: txWriter},
+		{Namespace: b.namespaces.IntermediateStateRoots, Writer: isrWriter},
+		{Namespace: b.namespaces.PayForBlob, Writer: pfbWriter},
+	}, blobWriter, nonReservedStart, ss, arena)
 	if err != nil {
 		return nil, fmt.Errorf("writing square: %w", err)
 	}
 
 	b.done = true
+	if b.exportProgress != nil {
+		b.exportProgress(totalShares, totalShares)
+	}
 
 	return square, nil
 }
@@ -211,14 +544,14 @@ func (b *Builder) Export() (Square, error) {
 // the index of the pfb in the tx set and the index of the blob within the PFB.
 func (b *Builder) FindBlobStartingIndex(pfbIndex, blobIndex int) (int, error) {
 	if pfbIndex < len(b.Txs) {
-		return 0, fmt.Errorf("pfbIndex %d does not match a pfb", pfbIndex)
+		return 0, fmt.Errorf("%w: pfbIndex %d", ErrNotPFB, pfbIndex)
 	}
 	pfbIndex -= len(b.Txs)
 	if pfbIndex >= len(b.Pfbs) {
-		return 0, fmt.Errorf("pfbIndex %d out of range", pfbIndex)
+		return 0, fmt.Errorf("%w: pfbIndex %d", ErrIndexOutOfRange, pfbIndex)
 	}
 	if blobIndex < 0 {
-		return 0, fmt.Errorf("blobIndex %d must not be negative", blobIndex)
+		return 0, fmt.Errorf("%w: blobIndex %d must not be negative", ErrIndexOutOfRange, blobIndex)
 	}
 
 	// The share indexes of each blob needs to be computed thus we need to ensure
@@ -231,7 +564,7 @@ func (b *Builder) FindBlobStartingIndex(pfbIndex, blobIndex int) (int, error) {
 	}
 
 	if blobIndex >= len(b.Pfbs[pfbIndex].ShareIndexes) {
-		return 0, fmt.Errorf("blobIndex %d out of range", blobIndex)
+		return 0, fmt.Errorf("%w: blobIndex %d", ErrIndexOutOfRange, blobIndex)
 	}
 
 	return int(b.Pfbs[pfbIndex].ShareIndexes[blobIndex]), nil
@@ -243,14 +576,14 @@ func (b *Builder) FindBlobStartingIndex(pfbIndex, blobIndex int) (int, error) {
 // numbers of blobs
 func (b *Builder) BlobShareLength(pfbIndex, blobIndex int) (int, error) {
 	if pfbIndex < len(b.Txs) {
-		return 0, fmt.Errorf("pfbIndex %d does not match a pfb", pfbIndex)
+		return 0, fmt.Errorf("%w: pfbIndex %d", ErrNotPFB, pfbIndex)
 	}
 	pfbIndex -= len(b.Txs)
 	if pfbIndex >= len(b.Pfbs) {
-		return 0, fmt.Errorf("pfbIndex %d out of range", pfbIndex)
+		return 0, fmt.Errorf("%w: pfbIndex %d", ErrIndexOutOfRange, pfbIndex)
 	}
 	if blobIndex < 0 {
-		return 0, fmt.Errorf("blobIndex %d must not be negative", blobIndex)
+		return 0, fmt.Errorf("%w: blobIndex %d must not be negative", ErrIndexOutOfRange, blobIndex)
 	}
 
 	for _, blob := range b.Blobs {
@@ -258,75 +591,222 @@ func (b *Builder) BlobShareLength(pfbIndex, blobIndex int) (int, error) {
 			return blob.NumShares, nil
 		}
 	}
-	return 0, fmt.Errorf("blob not found")
+	return 0, fmt.Errorf("%w: blob %d of pfbIndex %d not found", ErrIndexOutOfRange, blobIndex, pfbIndex+len(b.Txs))
 }
 
 // FindTxShareRange returns the range of shares occupied by the tx at txIndex.
 // The indexes are both inclusive.
 func (b *Builder) FindTxShareRange(txIndex int) (share.Range, error) {
-	// the square must be built before we can find the share range as we need to compute
-	// the wrapped indexes for the PFBs. NOTE: If a tx isn't a PFB, we could theoretically
-	// calculate the index without having to build the entire square.
-	if !b.done {
+	if txIndex < 0 {
+		return share.Range{}, fmt.Errorf("%w: txIndex %d must not be negative", ErrIndexOutOfRange, txIndex)
+	}
+
+	if txIndex >= len(b.Txs)+len(b.Pfbs) {
+		return share.Range{}, fmt.Errorf("%w: txIndex %d", ErrIndexOutOfRange, txIndex)
+	}
+
+	// the square must be built before we can find the share range of a PFB as
+	// we need to compute its wrapped indexes. Normal transactions precede the
+	// PFBs so their range doesn't depend on them.
+	if txIndex >= len(b.Txs) && !b.done {
 		_, err := b.Export()
 		if err != nil {
 			return share.Range{}, fmt.Errorf("building square: %w", err)
 		}
 	}
-	if txIndex < 0 {
-		return share.Range{}, fmt.Errorf("txIndex %d must not be negative", txIndex)
-	}
 
-	if txIndex >= len(b.Txs)+len(b.Pfbs) {
-		return share.Range{}, fmt.Errorf("txIndex %d out of range", txIndex)
+	// txSize returns the namespace and the number of bytes written to the
+	// compact shares of the tx at index i
+	txSize := func(i int) (share.Namespace, int) {
+		if i < len(b.Txs) {
+			return b.namespaces.Tx, len(b.Txs[i])
+		}
+		return b.namespaces.PayForBlob, proto.Size(b.Pfbs[i-len(b.Txs)])
 	}
 
-	txWriter := share.NewCompactShareCounter()
-	pfbWriter := share.NewCompactShareCounter()
+	counter := share.NewMultiCounter(b.namespaces.Tx, b.namespaces.IntermediateStateRoots, b.namespaces.PayForBlob)
+	for _, isr := range b.Isrs {
+		counter.Add(b.namespaces.IntermediateStateRoots, len(isr))
+	}
 	for i := 0; i < txIndex; i++ {
-		if i < len(b.Txs) {
-			_ = txWriter.Add(len(b.Txs[i]))
-		} else {
-			size := proto.Size(b.Pfbs[i-len(b.Txs)])
-			_ = pfbWriter.Add(size)
+		counter.Add(txSize(i))
+	}
+
+	ns, size := txSize(txIndex)
+	start := counter.StartOf(ns) + counter.Size(ns) - 1
+	// If the remainder is 0, it means the tx will begin with the next share
+	// so we need to increment the start index.
+	if counter.Remainder(ns) == 0 {
+		start++
+	}
+	counter.Add(ns, size)
+	end := counter.StartOf(ns) + counter.Size(ns)
+
+	return share.NewRange(start, end), nil
+}
+
+// FindFibreTxShareRange returns the range of shares occupied by the fibre
+// transaction at fibreTxIndex, the index amongst the fibre transactions in the
+// order they were appended. Fibre transactions are written to the transaction
+// namespace together with the system blob they pay for, so the range also
+// covers the system blob.
+func (b *Builder) FindFibreTxShareRange(fibreTxIndex int) (share.Range, error) {
+	if fibreTxIndex < 0 {
+		return share.Range{}, fmt.Errorf("%w: fibreTxIndex %d must not be negative", ErrIndexOutOfRange, fibreTxIndex)
+	}
+	txIndexes := b.fibreTxIndexes()
+	if fibreTxIndex >= len(txIndexes) {
+		return share.Range{}, fmt.Errorf("%w: fibreTxIndex %d", ErrIndexOutOfRange, fibreTxIndex)
+	}
+	return b.FindTxShareRange(txIndexes[fibreTxIndex])
+}
+
+// SystemBlobRange is the location of the system blob of a fibre transaction in
+// the compact shares of the transaction namespace.
+type SystemBlobRange struct {
+	// Bytes is the range of the protobuf encoded system blob in the data of
+	// the compact share sequence of the transaction namespace, i.e. in the
+	// concatenation of the length delimited transactions without the share
+	// headers.
+	Bytes share.Range
+	// Shares is the range of shares containing the encoded system blob. It is
+	// a sub-range of the range returned by FindFibreTxShareRange and uses the
+	// same convention.
+	Shares share.Range
+}
+
+// FindSystemBlobRange behaves like FindFibreTxShareRange but narrows the
+// result down to the system blob of the fibre transaction at fibreTxIndex.
+// Since the system blob is embedded in the fibre transaction, it is not
+// written as a separate blob and is located within the compact shares of the
+// transaction namespace.
+func (b *Builder) FindSystemBlobRange(fibreTxIndex int) (SystemBlobRange, error) {
+	if fibreTxIndex < 0 {
+		return SystemBlobRange{}, fmt.Errorf("%w: fibreTxIndex %d must not be negative", ErrIndexOutOfRange, fibreTxIndex)
+	}
+	txIndexes := b.fibreTxIndexes()
+	if fibreTxIndex >= len(txIndexes) {
+		return SystemBlobRange{}, fmt.Errorf("%w: fibreTxIndex %d", ErrIndexOutOfRange, fibreTxIndex)
+	}
+	txIndex := txIndexes[fibreTxIndex]
+	fibreTx := b.Txs[txIndex]
+	start, end, err := systemBlobOffsets(fibreTx)
+	if err != nil {
+		return SystemBlobRange{}, err
+	}
+
+	// the transactions are written as a single length delimited sequence
+	offset := protowire.SizeVarint(uint64(len(fibreTx)))
+	for _, txBytes := range b.Txs[:txIndex] {
+		offset += protowire.SizeVarint(uint64(len(txBytes))) + len(txBytes)
+	}
+	byteRange := share.NewRange(offset+start, offset+end)
+	// the transaction namespace is the first namespace of the square
+	shareRange := share.NewRange(compactShareIndex(byteRange.Start), compactShareIndex(byteRange.End-1)+1)
+	return SystemBlobRange{Bytes: byteRange, Shares: shareRange}, nil
+}
+
+// systemBlobOffsets returns the start and end offsets of the encoded system
+// blob within an encoded fibre transaction.
+func systemBlobOffsets(fibreTx []byte) (start, end int, err error) {
+	const systemBlobField = 2 // see v1.FibreTx
+	pos := 0
+	for pos < len(fibreTx) {
+		num, typ, n := protowire.ConsumeTag(fibreTx[pos:])
+		if n < 0 {
+			return 0, 0, protowire.ParseError(n)
+		}
+		pos += n
+		if num == systemBlobField && typ == protowire.BytesType {
+			value, n := protowire.ConsumeBytes(fibreTx[pos:])
+			if n < 0 {
+				return 0, 0, protowire.ParseError(n)
+			}
+			return pos + n - len(value), pos + n, nil
+		}
+		n = protowire.ConsumeFieldValue(num, typ, fibreTx[pos:])
+		if n < 0 {
+			return 0, 0, protowire.ParseError(n)
 		}
+		pos += n
 	}
+	return 0, 0, errors.New("fibre tx has no system blob")
+}
 
-	start := txWriter.Size() + pfbWriter.Size() - 1
+// compactShareIndex returns the index of the share of a compact share
+// sequence containing the byte at offset of the data of the sequence.
+func compactShareIndex(offset int) int {
+	if offset < share.FirstCompactShareContentSize {
+		return 0
+	}
+	return 1 + (offset-share.FirstCompactShareContentSize)/share.ContinuationCompactShareContentSize
+}
 
-	// the chosen tx is a regular tx
-	if txIndex < len(b.Txs) {
-		// If the remainder is 0, it means the tx will begin with the next share
-		// so we need to increment the start index.
-		if txWriter.Remainder() == 0 {
-			start++
+// fibreTxIndexes returns the indexes in b.Txs of the fibre transactions.
+func (b *Builder) fibreTxIndexes() []int {
+	var txIndexes []int
+	for txIndex, txBytes := range b.Txs {
+		if _, isFibreTx, _ := tx.UnmarshalFibreTx(txBytes); isFibreTx {
+			txIndexes = append(txIndexes, txIndex)
 		}
-		_ = txWriter.Add(len(b.Txs[txIndex]))
-	} else { // the chosen tx is a PFB
-		// If the remainder is 0, it means the tx will begin with the next share
-		// so we need to increment the start index.
-		if pfbWriter.Remainder() == 0 {
-			start++
+	}
+	return txIndexes
+}
+
+// AppendFibreTxs appends the fibre transactions in order until one can not be
+// appended, in which case it and all following transactions are left out.
+// Each fibre transaction is appended whole or not at all. It returns the
+// number of transactions appended and, if not all of them were, a *TxError
+// with the index of the first transaction that was left out. The share ranges
+// of the appended transactions are returned by FibreTxShareRanges.
+func (b *Builder) AppendFibreTxs(txs []*tx.FibreTx) (added int, err error) {
+	for i, fibreTx := range txs {
+		if fibreTx == nil {
+			return added, &TxError{Index: i, Err: errors.New("fibre tx is nil")}
+		}
+		txBytes, err := tx.MarshalFibreTx(fibreTx.Tx, fibreTx.SystemBlob)
+		if err != nil {
+			return added, &TxError{Index: i, Err: err}
+		}
+		if err := b.TryAppendTx(txBytes); err != nil {
+			return added, &TxError{Index: i, Err: err}
 		}
-		size := proto.Size(b.Pfbs[txIndex-len(b.Txs)])
-		_ = pfbWriter.Add(size)
+		added++
 	}
-	end := txWriter.Size() + pfbWriter.Size()
+	return added, nil
+}
 
-	return share.NewRange(start, end), nil
+// FibreTxShareRanges returns the share ranges of all fibre transactions in
+// the builder, whether appended with AppendFibreTxs, TryAppendTx or restored
+// with RestoreBuilder, in the order they were appended. The ranges use the
+// same convention as FindTxShareRange and match FindFibreTxShareRange. They
+// remain valid as other transactions are added, since transactions precede
+// all other data in the square.
+func (b *Builder) FibreTxShareRanges() []share.Range {
+	txIndexes := b.fibreTxIndexes()
+	ranges := make([]share.Range, 0, len(txIndexes))
+	for _, txIndex := range txIndexes {
+		// normal transactions always have a share range
+		txRange, err := b.FindTxShareRange(txIndex)
+		if err != nil {
+			panic(err)
+		}
+		ranges = append(ranges, txRange)
+	}
+	return ranges
 }
 
 func (b *Builder) GetWrappedPFB(txIndex int) (*v1.IndexWrapper, error) {
 	if txIndex < 0 {
-		return nil, fmt.Errorf("txIndex %d must not be negative", txIndex)
+		return nil, fmt.Errorf("%w: txIndex %d must not be negative", ErrIndexOutOfRange, txIndex)
 	}
 
 	if txIndex < len(b.Txs) {
-		return nil, fmt.Errorf("txIndex %d does not match a pfb", txIndex)
+		return nil, fmt.Errorf("%w: txIndex %d", ErrNotPFB, txIndex)
 	}
 
 	if txIndex >= len(b.Txs)+len(b.Pfbs) {
-		return nil, fmt.Errorf("txIndex %d out of range", txIndex)
+		return nil, fmt.Errorf("%w: txIndex %d", ErrIndexOutOfRange, txIndex)
 	}
 
 	if !b.done {
@@ -347,6 +827,17 @@ func (b *Builder) SubtreeRootThreshold() int {
 	return b.subtreeRootThreshold
 }
 
+// ReservedNamespaces returns the namespaces the builder writes compact shares
+// to.
+func (b *Builder) ReservedNamespaces() ReservedNamespaces {
+	return b.namespaces
+}
+
+// Version returns the square version whose rules the builder follows.
+func (b *Builder) Version() SquareVersion {
+	return b.version
+}
+
 func (b *Builder) NumPFBs() int {
 	return len(b.Pfbs)
 }
@@ -355,12 +846,37 @@ func (b *Builder) NumTxs() int {
 	return len(b.Txs) + len(b.Pfbs)
 }
 
+// Reset removes all transactions from the builder while retaining its
+// configuration and the capacity of its internal slices so it can be reused
+// to build another square.
+func (b *Builder) Reset() {
+	clear(b.Txs)
+	b.Txs = b.Txs[:0]
+	clear(b.Isrs)
+	b.Isrs = b.Isrs[:0]
+	clear(b.Pfbs)
+	b.Pfbs = b.Pfbs[:0]
+	clear(b.Blobs)
+	b.Blobs = b.Blobs[:0]
+	*b.TxCounter = share.CompactShareCounter{}
+	*b.IsrCounter = share.CompactShareCounter{}
+	*b.PfbCounter = share.CompactShareCounter{}
+	b.currentSize = 0
+	b.blobBytes = 0
+	b.done = false
+	b.appends = b.appends[:0]
+}
+
+func (b *Builder) insufficientSpace(required int) *InsufficientSpaceError {
+	return &InsufficientSpaceError{Required: required, Available: b.maxSquareSize*b.maxSquareSize - b.currentSize}
+}
+
 func (b *Builder) canFit(shareNum int) bool {
 	return b.currentSize+shareNum <= (b.maxSquareSize * b.maxSquareSize)
 }
 
 func (b *Builder) IsEmpty() bool {
-	return b.TxCounter.Size() == 0 && b.PfbCounter.Size() == 0
+	return b.TxCounter.Size() == 0 && b.IsrCounter.Size() == 0 && b.PfbCounter.Size() == 0
 }
 
 type Element struct {
@@ -371,8 +887,46 @@ type Element struct {
 	MaxPadding int
 }
 
-func newElement(blob *share.Blob, pfbIndex, blobIndex, subtreeRootThreshold int) *Element {
-	numShares := share.SparseSharesNeeded(uint32(len(blob.Data())))
+// ElementView is a read-only description of a blob pending in the builder.
+// PfbIndex is the index of the PFB paying for the blob amongst the PFBs and
+// BlobIndex is the index of the blob amongst the blobs of that PFB.
+type ElementView struct {
+	Namespace  share.Namespace
+	PfbIndex   int
+	BlobIndex  int
+	NumShares  int
+	MaxPadding int
+}
+
+// BlobElements returns a view of the blobs pending in the builder in the order
+// they are written to the square: sorted by namespace and, within a namespace,
+// by PfbIndex and then BlobIndex. Unlike the Blobs field, which Export sorts in
+// place, the order is the same whether or not the square has been exported.
+func (b *Builder) BlobElements() []ElementView {
+	views := make([]ElementView, len(b.Blobs))
+	for i, element := range b.Blobs {
+		views[i] = ElementView{
+			Namespace:  element.Blob.Namespace(),
+			PfbIndex:   element.PfbIndex,
+			BlobIndex:  element.BlobIndex,
+			NumShares:  element.NumShares,
+			MaxPadding: element.MaxPadding,
+		}
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if cmp := bytes.Compare(views[i].Namespace.Bytes(), views[j].Namespace.Bytes()); cmp != 0 {
+			return cmp < 0
+		}
+		if views[i].PfbIndex != views[j].PfbIndex {
+			return views[i].PfbIndex < views[j].PfbIndex
+		}
+		return views[i].BlobIndex < views[j].BlobIndex
+	})
+	return views
+}
+
+func (b *Builder) newElement(blob *share.Blob, pfbIndex, blobIndex int) *Element {
+	numShares := share.SparseSharesNeeded(blob.SequenceLen())
 	return &Element{
 		Blob:      blob,
 		PfbIndex:  pfbIndex,
@@ -401,34 +955,43 @@ func newElement(blob *share.Blob, pfbIndex, blobIndex, subtreeRootThreshold int)
 		//
 		// Note that the padding would actually belong to the namespace of the transaction before it, but
 		// this makes no difference to the total share size.
-		MaxPadding: inclusion.SubTreeWidth(numShares, subtreeRootThreshold) - 1,
+		MaxPadding: b.version.maxPadding(numShares, b.subtreeRootThreshold),
 	}
 }
 
-func (e Element) maxShareOffset() int {
-	return e.NumShares + e.MaxPadding
+// WorstCaseSharesUsed returns the number of shares a builder using the
+// DefaultSquareVersion rules reserves for blobs of the given data lengths
+// (using share version 0), including the worst-case padding needed to align
+// each blob to the blob share commitment rules. It excludes the shares of the
+// PFB that pays for the blobs.
+func WorstCaseSharesUsed(blobSizes []int, subtreeRootThreshold int) int {
+	return worstCaseSharesUsed(DefaultSquareVersion, blobSizes, subtreeRootThreshold)
+}
+
+// WorstCaseSharesUsedWithVersion behaves like WorstCaseSharesUsed but uses
+// the rules of the provided square version, so that it matches the
+// accounting of a builder created with that version.
+func WorstCaseSharesUsedWithVersion(version SquareVersion, blobSizes []int, subtreeRootThreshold int) (int, error) {
+	if err := version.Validate(); err != nil {
+		return 0, err
+	}
+	return worstCaseSharesUsed(version, blobSizes, subtreeRootThreshold), nil
 }
 
-// worstCaseShareIndexes returns the largest possible share indexes for a set of
-// blobs. Largest possible is "worst" in that protobuf uses varints to encode
-// integers, so larger integers can require more bytes to encode.
-//
-// Note: the implementation of this function assumes that the worst case share
-// index is always 128 * 128 to preserve backwards compatibility with
-// celestia-app v1.x.
-func worstCaseShareIndexes(blobs int) []uint32 {
-	// TODO: de-duplicate this constant with celestia-app SquareSizeUpperBound constant.
-	// https://github.com/celestiaorg/celestia-app/blob/a93bb625c6dc0ae6c7c357e9991815a68ab33c79/pkg/appconsts/v1/app_consts.go#L5
-	squareSizeUpperBound := 128
-	worstCaseShareIndex := squareSizeUpperBound * squareSizeUpperBound
-	shareIndexes := make([]uint32, blobs)
-	for i := range shareIndexes {
-		shareIndexes[i] = uint32(worstCaseShareIndex)
+func worstCaseSharesUsed(version SquareVersion, blobSizes []int, subtreeRootThreshold int) int {
+	sharesUsed := 0
+	for _, size := range blobSizes {
+		numShares := share.SparseSharesNeeded(uint32(size))
+		sharesUsed += numShares + version.maxPadding(numShares, subtreeRootThreshold)
 	}
-	return shareIndexes
+	return sharesUsed
+}
+
+func (e Element) maxShareOffset() int {
+	return e.NumShares + e.MaxPadding
 }
 
 // IsPowerOfTwo returns true if input is a power of two.
 func IsPowerOfTwo[I constraints.Integer](input I) bool {
-	return input&(input-1) == 0 && input != 0
+	return layout.IsPowerOfTwo(input)
 }
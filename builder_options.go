@@ -0,0 +1,223 @@
+package square
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// BuilderOption configures a Builder.
+type BuilderOption func(*Builder)
+
+// WithSquareVersion sets the square version whose rules the builder follows.
+// It defaults to DefaultSquareVersion.
+func WithSquareVersion(version SquareVersion) BuilderOption {
+	return func(b *Builder) {
+		b.version = version
+	}
+}
+
+// WithMaxBlobCount limits the total number of blobs in the square. A value of
+// zero or less disables the limit.
+func WithMaxBlobCount(n int) BuilderOption {
+	return func(b *Builder) {
+		b.maxBlobCount = n
+	}
+}
+
+// WithMaxBlobBytes limits the total size of the data of all blobs in the
+// square. A value of zero or less disables the limit.
+func WithMaxBlobBytes(n int) BuilderOption {
+	return func(b *Builder) {
+		b.maxBlobBytes = n
+	}
+}
+
+// checkBlobLimits returns an error if a square containing blobCount blobs
+// of blobBytes bytes in total exceeds the configured limits.
+func (b *Builder) checkBlobLimits(blobCount, blobBytes int) error {
+	if b.maxBlobCount > 0 && blobCount > b.maxBlobCount {
+		return ErrBlobCountExceeded
+	}
+	if b.maxBlobBytes > 0 && blobBytes > b.maxBlobBytes {
+		return ErrBlobBytesExceeded
+	}
+	return nil
+}
+
+// NamespacePolicy decides whether blobs of a namespace may be included in the
+// square. It returns a non-nil error describing why a namespace is denied.
+type NamespacePolicy func(ns share.Namespace) error
+
+// WithNamespacePolicy makes the builder reject blob txs with a blob in a
+// namespace denied by the policy. By default all namespaces are allowed.
+func WithNamespacePolicy(policy NamespacePolicy) BuilderOption {
+	return func(b *Builder) {
+		b.namespacePolicy = policy
+	}
+}
+
+// WithNamespaceOptions sets the options used to decode the blobs of the raw
+// blob txs passed to the builder, for example by ConstructWithOptions. Use
+// share.AllowedNamespaceVersions to accept blobs in namespace versions other
+// than version 0.
+func WithNamespaceOptions(opts ...share.NamespaceOption) BuilderOption {
+	return func(b *Builder) {
+		b.namespaceOpts = opts
+	}
+}
+
+// checkNamespacePolicy returns a *NamespaceDeniedError for the first blob of
+// the blob tx whose namespace is denied by the namespace policy.
+func (b *Builder) checkNamespacePolicy(blobTx *tx.BlobTx) error {
+	if b.namespacePolicy == nil {
+		return nil
+	}
+	for _, blob := range blobTx.Blobs {
+		if err := b.namespacePolicy(blob.Namespace()); err != nil {
+			return &NamespaceDeniedError{Namespace: blob.Namespace(), Err: err}
+		}
+	}
+	return nil
+}
+
+// WithContiguousPFBBlobs requires the blobs of each PFB to be placed next to
+// each other in the square, separated only by the padding required by the
+// blob share commitment rules. As blobs are ordered by namespace, this is
+// only possible if no blob of another PFB sorts between the blobs of a PFB.
+// Export returns ErrBlobsNotContiguous otherwise.
+func WithContiguousPFBBlobs() BuilderOption {
+	return func(b *Builder) {
+		b.contiguousPFBBlobs = true
+	}
+}
+
+// WithShareArena backs all shares of the square returned by Export with a
+// single contiguous buffer instead of allocating a buffer per share. This
+// reduces the number of allocations of large squares to a handful and
+// improves cache locality when hashing or extending the square.
+func WithShareArena() BuilderOption {
+	return func(b *Builder) {
+		b.shareArena = true
+	}
+}
+
+// ExportProgressFunc is called with the number of shares written so far and
+// the total number of shares of the square being exported.
+type ExportProgressFunc func(written, total int)
+
+// WithExportProgress registers a callback that is periodically called during
+// Export so that callers exporting large squares can report progress. The
+// callback is called one last time with written equal to total once the
+// square has been written. It is called synchronously so it should return
+// quickly.
+func WithExportProgress(fn ExportProgressFunc) BuilderOption {
+	return func(b *Builder) {
+		b.exportProgress = fn
+	}
+}
+
+// checkPFBBlobsContiguous returns an error if the blobs of a PFB are not
+// adjacent in the sorted blobs.
+func checkPFBBlobsContiguous(blobs []*Element) error {
+	first := make(map[int]int)
+	for i, element := range blobs {
+		start, seen := first[element.PfbIndex]
+		if !seen {
+			first[element.PfbIndex] = i
+			continue
+		}
+		// every blob since the first blob of the PFB must belong to it
+		if blobs[i-1].PfbIndex != element.PfbIndex {
+			return fmt.Errorf("%w: pfb %d has blobs at positions %d and %d", ErrBlobsNotContiguous, element.PfbIndex, start, i)
+		}
+	}
+	return nil
+}
+
+// ReservedNamespaces are the primary reserved namespaces the builder writes
+// compact shares to. Chains that need different reserved namespaces than
+// Celestia can override them using WithReservedNamespaces.
+type ReservedNamespaces struct {
+	Tx                     share.Namespace
+	IntermediateStateRoots share.Namespace
+	PayForBlob             share.Namespace
+}
+
+// DefaultReservedNamespaces returns the reserved namespaces used by Celestia.
+func DefaultReservedNamespaces() ReservedNamespaces {
+	return ReservedNamespaces{
+		Tx:                     share.TxNamespace,
+		IntermediateStateRoots: share.IntermediateStateRootsNamespace,
+		PayForBlob:             share.PayForBlobNamespace,
+	}
+}
+
+// Validate returns an error if any of the namespaces is not a primary reserved
+// namespace usable for compact shares or if they are not in ascending order,
+// as transactions must precede intermediate state roots, which must precede
+// the PFBs in the square.
+func (r ReservedNamespaces) Validate() error {
+	namespaces := []share.Namespace{r.Tx, r.IntermediateStateRoots, r.PayForBlob}
+	for i, ns := range namespaces {
+		if ns.IsEmpty() || !ns.IsPrimaryReserved() || ns.IsPrimaryReservedPadding() {
+			return fmt.Errorf("reserved namespace %s is not a primary reserved namespace", ns)
+		}
+		if i > 0 && !namespaces[i-1].IsLessThan(ns) {
+			return fmt.Errorf("reserved namespace %s must be greater than %s", ns, namespaces[i-1])
+		}
+	}
+	return nil
+}
+
+// WithReservedNamespaces overrides the namespaces the builder writes the
+// transactions, intermediate state roots and PFBs to. It defaults to
+// DefaultReservedNamespaces.
+func WithReservedNamespaces(namespaces ReservedNamespaces) BuilderOption {
+	return func(b *Builder) {
+		b.namespaces = namespaces
+	}
+}
+
+// ReadOption configures functions that read an existing square such as
+// Deconstruct, Validate and Stats.
+type ReadOption func(*readConfig)
+
+type readConfig struct {
+	namespaces    ReservedNamespaces
+	namespaceOpts []share.NamespaceOption
+}
+
+// UseReservedNamespaces overrides the namespaces in which the transactions,
+// intermediate state roots and PFBs are expected. It must match the
+// namespaces the square was built with using WithReservedNamespaces. It
+// defaults to DefaultReservedNamespaces.
+func UseReservedNamespaces(namespaces ReservedNamespaces) ReadOption {
+	return func(cfg *readConfig) {
+		cfg.namespaces = namespaces
+	}
+}
+
+// UseNamespaceOptions sets the options used to parse the blobs of the square.
+// Use share.AllowedNamespaceVersions to accept blobs in namespace versions
+// other than version 0.
+func UseNamespaceOptions(opts ...share.NamespaceOption) ReadOption {
+	return func(cfg *readConfig) {
+		cfg.namespaceOpts = opts
+	}
+}
+
+func newReadConfig(opts []ReadOption) *readConfig {
+	cfg := &readConfig{namespaces: DefaultReservedNamespaces()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// isReserved reports whether ns is one of the reserved namespaces or the
+// primary reserved padding namespace, which precede all blobs in the square.
+func (r ReservedNamespaces) isReserved(ns share.Namespace) bool {
+	return ns.Equals(r.Tx) || ns.Equals(r.IntermediateStateRoots) || ns.Equals(r.PayForBlob) || ns.IsPrimaryReservedPadding()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/celestiaorg/go-square/v2"
 	"github.com/celestiaorg/go-square/v2/internal/test"
 	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/squaretest"
 	"github.com/celestiaorg/go-square/v2/tx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -103,8 +105,93 @@ func TestBuilderInvalidConstructor(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestBuilderInsertAndRemoveBlobTx(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	blobTxs := generateBlobTxsWithNamespaces(
+		[]share.Namespace{ns2, ns1, ns2, ns1, ns1},
+		[][]int{{1000}, {2000}, {600, 700}, {3000}},
+	)
+	unmarshalled := make([]*tx.BlobTx, len(blobTxs))
+	for i, blobTx := range blobTxs {
+		var err error
+		unmarshalled[i], _, err = tx.UnmarshalBlobTx(blobTx)
+		require.NoError(t, err)
+	}
+	normalTxs := test.GenerateTxs(250, 250, 3)
+	withTxs := func(blobTxs ...[]byte) [][]byte {
+		return append(append([][]byte{}, normalTxs...), blobTxs...)
+	}
+
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, withTxs(blobTxs[0], blobTxs[1], blobTxs[3])...)
+	require.NoError(t, err)
+	// export first so that the blobs are sorted by namespace
+	_, err = builder.Export()
+	require.NoError(t, err)
+
+	require.False(t, builder.InsertBlobTx(-1, unmarshalled[2]))
+	require.False(t, builder.InsertBlobTx(4, unmarshalled[2]))
+	require.True(t, builder.InsertBlobTx(2, unmarshalled[2]))
+
+	expected, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, withTxs(blobTxs...)...)
+	require.NoError(t, err)
+	require.Equal(t, expected.CurrentSize(), builder.CurrentSize())
+	assertSameSquare(t, expected, builder)
+
+	require.Error(t, builder.RemoveBlobTx(4))
+	require.NoError(t, builder.RemoveBlobTx(0))
+	expected, err = square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, withTxs(blobTxs[1:]...)...)
+	require.NoError(t, err)
+	require.Equal(t, expected.CurrentSize(), builder.CurrentSize())
+	assertSameSquare(t, expected, builder)
+
+	// a blob tx that doesn't fit is rejected without modifying the builder
+	small, err := square.NewBuilder(2, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.True(t, small.AppendBlobTx(unmarshalled[0]))
+	require.False(t, small.InsertBlobTx(0, unmarshalled[3]))
+	require.Equal(t, 1, small.NumPFBs())
+}
+
+func TestWorstCaseSharesUsed(t *testing.T) {
+	require.Equal(t, 0, square.WorstCaseSharesUsed(nil, defaultSubtreeRootThreshold))
+
+	blobSizes := []int{100, 10_000, 100_000}
+	blobTx := test.GenerateBlobTx(blobSizes)
+	unmarshalled, _, err := tx.UnmarshalBlobTx(blobTx)
+	require.NoError(t, err)
+
+	for _, threshold := range []int{1, 4, defaultSubtreeRootThreshold} {
+		builder, err := square.NewBuilder(defaultMaxSquareSize, threshold)
+		require.NoError(t, err)
+		require.True(t, builder.AppendBlobTx(unmarshalled))
+		pfbShares := builder.PfbCounter.Size()
+		require.Equal(t, builder.CurrentSize()-pfbShares, square.WorstCaseSharesUsed(blobSizes, threshold))
+
+		for _, version := range square.SupportedSquareVersions() {
+			builder, err := square.NewBuilderWithVersion(version, defaultMaxSquareSize, threshold)
+			require.NoError(t, err)
+			require.True(t, builder.AppendBlobTx(unmarshalled))
+			sharesUsed, err := square.WorstCaseSharesUsedWithVersion(version, blobSizes, threshold)
+			require.NoError(t, err)
+			require.Equal(t, builder.CurrentSize()-builder.PfbCounter.Size(), sharesUsed, "version %s", version)
+		}
+	}
+
+	_, err = square.WorstCaseSharesUsedWithVersion(0, blobSizes, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+}
+
+func assertSameSquare(t *testing.T, expected, actual *square.Builder) {
+	expectedSquare, err := expected.Export()
+	require.NoError(t, err)
+	actualSquare, err := actual.Export()
+	require.NoError(t, err)
+	require.True(t, expectedSquare.Equals(actualSquare))
+}
+
 func newTx(len int) []byte {
-	return bytes.Repeat([]byte{0}, len-test.DelimLen(uint64(len)))
+	return bytes.Repeat([]byte{0}, len-share.DelimLen(uint64(len)))
 }
 
 func TestBuilderFindTxShareRange(t *testing.T) {
@@ -143,6 +230,60 @@ func TestBuilderFindTxShareRange(t *testing.T) {
 	}
 }
 
+func TestBuilderIntermediateStateRoots(t *testing.T) {
+	blockTxs := generateOrderedTxs(3, 3, 1000, 2)
+	roots := [][]byte{test.RandomBytes(32), test.RandomBytes(600), test.RandomBytes(32)}
+
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, blockTxs...)
+	require.NoError(t, err)
+	sizeBefore := builder.CurrentSize()
+	require.True(t, builder.SetIntermediateStateRoots(roots))
+	require.Greater(t, builder.CurrentSize(), sizeBefore)
+
+	dataSquare, err := builder.Export()
+	require.NoError(t, err)
+	require.NoError(t, square.Validate(dataSquare, defaultMaxSquareSize, defaultSubtreeRootThreshold))
+
+	isrRange := share.GetShareRangeForNamespace(dataSquare, share.IntermediateStateRootsNamespace)
+	require.Equal(t, builder.IsrCounter.Size(), isrRange.Len())
+	parsedRoots, err := share.ParseTxs(dataSquare[isrRange.Start:isrRange.End])
+	require.NoError(t, err)
+	require.Equal(t, roots, parsedRoots)
+	require.Equal(t, isrRange.Len(), square.Stats(dataSquare).ISRShares)
+
+	// PFBs are shifted by the intermediate state roots
+	for idx, txBytes := range blockTxs {
+		if blobTx, isBlobTx, _ := tx.UnmarshalBlobTx(txBytes); isBlobTx {
+			txBytes = blobTx.Tx
+		}
+		shareRange, err := builder.FindTxShareRange(idx)
+		require.NoError(t, err)
+		parsedShares, err := rawData(dataSquare[shareRange.Start : shareRange.End+1])
+		require.NoError(t, err)
+		require.True(t, bytes.Contains(parsedShares, txBytes))
+	}
+
+	// intermediate state roots are not transactions
+	txs, err := square.Deconstruct(dataSquare, test.DecodeMockPFB)
+	require.NoError(t, err)
+	require.Equal(t, blockTxs, txs)
+
+	// replacing the roots with no roots restores the original square
+	require.True(t, builder.SetIntermediateStateRoots(nil))
+	require.Equal(t, sizeBefore, builder.CurrentSize())
+	withoutRoots, err := builder.Export()
+	require.NoError(t, err)
+	expected, err := square.Construct(blockTxs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expected, withoutRoots)
+
+	// roots that do not fit are rejected
+	small, err := square.NewBuilder(1, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.False(t, small.SetIntermediateStateRoots([][]byte{test.RandomBytes(share.ShareSize)}))
+	require.True(t, small.IsEmpty())
+}
+
 func rawData(shares []share.Share) ([]byte, error) {
 	var data []byte
 	for _, share := range shares {
@@ -371,3 +512,503 @@ func TestBigBlock(t *testing.T) {
 
 //go:embed "internal/testdata/big_block.json"
 var bigBlockJSON string
+
+func TestBuilderBlobLimits(t *testing.T) {
+	blobTxs := test.GenerateBlobTxs(3, 2, 1000)
+	unmarshal := func(txBytes []byte) *tx.BlobTx {
+		blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
+		require.NoError(t, err)
+		require.True(t, isBlobTx)
+		return blobTx
+	}
+
+	t.Run("max blob count", func(t *testing.T) {
+		builder, err := square.NewBuilderWithOptions(defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithMaxBlobCount(4))
+		require.NoError(t, err)
+		require.NoError(t, builder.TryAppendBlobTx(unmarshal(blobTxs[0])))
+		require.NoError(t, builder.TryAppendBlobTx(unmarshal(blobTxs[1])))
+		require.ErrorIs(t, builder.TryAppendBlobTx(unmarshal(blobTxs[2])), square.ErrBlobCountExceeded)
+		require.False(t, builder.AppendBlobTx(unmarshal(blobTxs[2])))
+		require.False(t, builder.InsertBlobTx(0, unmarshal(blobTxs[2])))
+
+		// removing a blob tx frees up room for another
+		require.NoError(t, builder.RemoveBlobTx(0))
+		require.True(t, builder.InsertBlobTx(0, unmarshal(blobTxs[2])))
+		require.Equal(t, 2, builder.NumPFBs())
+	})
+
+	t.Run("max blob bytes", func(t *testing.T) {
+		builder, err := square.NewBuilderWithOptions(defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithMaxBlobBytes(3000))
+		require.NoError(t, err)
+		require.NoError(t, builder.TryAppendBlobTx(unmarshal(blobTxs[0])))
+		require.ErrorIs(t, builder.TryAppendBlobTx(unmarshal(blobTxs[1])), square.ErrBlobBytesExceeded)
+		require.Equal(t, 1, builder.NumPFBs())
+	})
+
+	t.Run("square full", func(t *testing.T) {
+		builder, err := square.NewBuilderWithOptions(1, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		err = builder.TryAppendBlobTx(unmarshal(blobTxs[0]))
+		require.Error(t, err)
+		require.NotErrorIs(t, err, square.ErrBlobCountExceeded)
+	})
+
+	t.Run("invalid square version", func(t *testing.T) {
+		_, err := square.NewBuilderWithOptions(defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithSquareVersion(0))
+		require.Error(t, err)
+	})
+}
+
+func TestMaxBlobSizeFitsInSquare(t *testing.T) {
+	for _, maxSquareSize := range []int{2, 8, 64} {
+		maxBlobSize := share.MaxBlobSizeWithMinimalPFB(maxSquareSize, defaultSubtreeRootThreshold)
+		_, err := square.Construct(test.GenerateBlobTxs(1, 1, maxBlobSize), maxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err, maxSquareSize)
+		_, err = square.Construct(test.GenerateBlobTxs(1, 1, maxBlobSize+1), maxSquareSize, defaultSubtreeRootThreshold)
+		require.ErrorIs(t, err, square.ErrNotEnoughSpace, maxSquareSize)
+	}
+}
+
+func TestBuilderReservedNamespaces(t *testing.T) {
+	primaryReserved := func(b byte) share.Namespace {
+		return share.MustNewV0Namespace(append(bytes.Repeat([]byte{0}, share.NamespaceVersionZeroIDSize-1), b))
+	}
+	namespaces := square.ReservedNamespaces{
+		Tx:                     primaryReserved(0x05),
+		IntermediateStateRoots: primaryReserved(0x06),
+		PayForBlob:             primaryReserved(0x07),
+	}
+	txs := test.GenerateTxs(250, 250, 5)
+	blobTxs := test.GenerateBlobTxs(3, 1, 1000)
+	dataSquare, err := square.ConstructWithOptions(append(txs, blobTxs...), defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithReservedNamespaces(namespaces))
+	require.NoError(t, err)
+
+	require.True(t, dataSquare[0].Namespace().Equals(namespaces.Tx))
+	require.True(t, dataSquare[0].IsCompactShare())
+	txRange := share.GetShareRangeForNamespace(dataSquare, namespaces.Tx)
+	parsedTxs, err := share.ParseTxs(dataSquare[txRange.Start:txRange.End])
+	require.NoError(t, err)
+	require.Equal(t, txs, parsedTxs)
+	pfbRange := share.GetShareRangeForNamespace(dataSquare, namespaces.PayForBlob)
+	require.Equal(t, txRange.End, pfbRange.Start)
+	require.True(t, share.GetShareRangeForNamespace(dataSquare, share.PayForBlobNamespace).IsEmpty())
+
+	defaultSquare, err := square.Construct(append(txs, blobTxs...), defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, len(defaultSquare), len(dataSquare))
+
+	invalid := []square.ReservedNamespaces{
+		{},
+		{Tx: namespaces.Tx, IntermediateStateRoots: namespaces.IntermediateStateRoots, PayForBlob: share.RandomBlobNamespace()},
+		{Tx: namespaces.Tx, IntermediateStateRoots: namespaces.IntermediateStateRoots, PayForBlob: share.PrimaryReservedPaddingNamespace},
+		{Tx: namespaces.PayForBlob, IntermediateStateRoots: namespaces.IntermediateStateRoots, PayForBlob: namespaces.Tx},
+		{Tx: namespaces.Tx, IntermediateStateRoots: namespaces.Tx, PayForBlob: namespaces.PayForBlob},
+	}
+	for _, reserved := range invalid {
+		require.Error(t, reserved.Validate())
+		_, err := square.NewBuilderWithOptions(defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithReservedNamespaces(reserved))
+		require.Error(t, err)
+	}
+	require.NoError(t, square.DefaultReservedNamespaces().Validate())
+}
+
+func TestReadReservedNamespaces(t *testing.T) {
+	primaryReserved := func(b byte) share.Namespace {
+		return share.MustNewV0Namespace(append(bytes.Repeat([]byte{0}, share.NamespaceVersionZeroIDSize-1), b))
+	}
+	namespaces := square.ReservedNamespaces{
+		Tx:                     primaryReserved(0x05),
+		IntermediateStateRoots: primaryReserved(0x06),
+		PayForBlob:             primaryReserved(0x07),
+	}
+	opt := square.UseReservedNamespaces(namespaces)
+	txs := append(test.GenerateTxs(250, 251, 5), test.GenerateBlobTxs(3, 2, 1000)...)
+	dataSquare, err := square.ConstructWithOptions(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithReservedNamespaces(namespaces))
+	require.NoError(t, err)
+
+	deconstructed, err := square.Deconstruct(dataSquare, test.DecodeMockPFB, opt)
+	require.NoError(t, err)
+	require.Equal(t, txs, deconstructed)
+	// without the option none of the compact shares are recognised
+	deconstructed, err = square.Deconstruct(dataSquare, test.DecodeMockPFB)
+	require.NoError(t, err)
+	require.Empty(t, deconstructed)
+
+	rebuilt, err := square.Rebuild(dataSquare, nil, test.DecodeMockPFB, defaultMaxSquareSize, defaultSubtreeRootThreshold, opt)
+	require.NoError(t, err)
+	require.True(t, rebuilt.Equals(dataSquare))
+
+	wpfbs, err := dataSquare.WrappedPFBs(opt)
+	require.NoError(t, err)
+	require.Len(t, wpfbs, 3)
+	wpfbs, err = dataSquare.WrappedPFBs()
+	require.NoError(t, err)
+	require.Empty(t, wpfbs)
+
+	require.NoError(t, square.Validate(dataSquare, defaultMaxSquareSize, defaultSubtreeRootThreshold, opt))
+	require.Error(t, square.Validate(dataSquare, defaultMaxSquareSize, defaultSubtreeRootThreshold))
+	require.NoError(t, square.VerifyPFBIndexes(dataSquare, defaultSubtreeRootThreshold, opt))
+	require.Error(t, square.VerifyPFBIndexes(dataSquare, defaultSubtreeRootThreshold))
+
+	stats := square.Stats(dataSquare, opt)
+	require.True(t, stats.TxShares > 0)
+	require.True(t, stats.PFBShares > 0)
+	require.Len(t, stats.BlobSharesPerNamespace, 1)
+	require.Zero(t, square.Stats(dataSquare).TxShares)
+
+	other, err := square.ConstructWithOptions(txs[:len(txs)-1], defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithReservedNamespaces(namespaces))
+	require.NoError(t, err)
+	require.Equal(t, len(dataSquare), len(other))
+	discrepancies := square.Explain(dataSquare, other, opt)
+	require.NotEmpty(t, discrepancies)
+	require.True(t, discrepancies[0].A.PFBIndex >= 0 || discrepancies[0].A.TxIndex >= 0)
+
+	invalid := square.UseReservedNamespaces(square.ReservedNamespaces{})
+	_, err = square.Deconstruct(dataSquare, test.DecodeMockPFB, invalid)
+	require.Error(t, err)
+	require.Error(t, square.Validate(dataSquare, defaultMaxSquareSize, defaultSubtreeRootThreshold, invalid))
+	require.Error(t, square.VerifyPFBIndexes(dataSquare, defaultSubtreeRootThreshold, invalid))
+}
+
+func TestBuilderNamespacePolicy(t *testing.T) {
+	allowed := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	denied := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	errSpam := errors.New("spam namespace")
+	policy := square.WithNamespacePolicy(func(ns share.Namespace) error {
+		if ns.Equals(denied) {
+			return errSpam
+		}
+		return nil
+	})
+	txs := generateBlobTxsWithNamespaces([]share.Namespace{allowed, allowed, denied}, [][]int{{100}, {100, 200}})
+	unmarshal := func(txBytes []byte) *tx.BlobTx {
+		blobTx, _, err := tx.UnmarshalBlobTx(txBytes)
+		require.NoError(t, err)
+		return blobTx
+	}
+
+	builder, err := square.NewBuilderWithOptions(defaultMaxSquareSize, defaultSubtreeRootThreshold, policy)
+	require.NoError(t, err)
+	require.NoError(t, builder.TryAppendBlobTx(unmarshal(txs[0])))
+	err = builder.TryAppendBlobTx(unmarshal(txs[1]))
+	require.ErrorIs(t, err, square.ErrNamespaceDenied)
+	require.ErrorIs(t, err, errSpam)
+	var deniedErr *square.NamespaceDeniedError
+	require.ErrorAs(t, err, &deniedErr)
+	require.Equal(t, denied, deniedErr.Namespace)
+	require.False(t, builder.InsertBlobTx(0, unmarshal(txs[1])))
+	require.Equal(t, 1, builder.NumPFBs())
+
+	_, err = square.ConstructWithOptions(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold, policy)
+	var txErr *square.TxError
+	require.ErrorAs(t, err, &txErr)
+	require.Equal(t, 1, txErr.Index)
+	require.ErrorIs(t, err, square.ErrNamespaceDenied)
+	require.Equal(t, "namespace denied", square.RejectionNamespaceDenied.String())
+
+	// without a policy all namespaces are allowed
+	_, err = square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+}
+
+func TestBuilderContiguousPFBBlobs(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	ns3 := share.MustNewV0Namespace(bytes.Repeat([]byte{3}, share.NamespaceVersionZeroIDSize))
+
+	contiguous := generateBlobTxsWithNamespaces([]share.Namespace{ns1, ns2, ns3}, [][]int{{1000, 2000}, {300}})
+	dataSquare, err := square.ConstructWithOptions(contiguous, defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithContiguousPFBBlobs())
+	require.NoError(t, err)
+	expected, err := square.Construct(contiguous, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+
+	interleaved := generateBlobTxsWithNamespaces([]share.Namespace{ns1, ns3, ns2}, [][]int{{1000, 2000}, {300}})
+	_, err = square.ConstructWithOptions(interleaved, defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithContiguousPFBBlobs())
+	require.ErrorIs(t, err, square.ErrBlobsNotContiguous)
+	_, err = square.Construct(interleaved, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+}
+
+func TestBuilderNamespaceOptions(t *testing.T) {
+	allowV1 := share.AllowedNamespaceVersions(share.NamespaceVersionZero, share.NamespaceVersionOne)
+	ns, err := share.NewNamespace(share.NamespaceVersionOne, bytes.Repeat([]byte{0xab}, share.NamespaceVersionOneIDSize), allowV1)
+	require.NoError(t, err)
+	blob, err := share.NewBlob(ns, test.RandomBytes(1000), share.ShareVersionZero, nil, allowV1)
+	require.NoError(t, err)
+	blobTx, err := tx.MarshalBlobTx(test.MockPFB([]uint32{1000}), blob)
+	require.NoError(t, err)
+
+	_, _, err = tx.UnmarshalBlobTx(blobTx)
+	require.Error(t, err)
+	decoded, isBlobTx, err := tx.UnmarshalBlobTx(blobTx, allowV1)
+	require.NoError(t, err)
+	require.True(t, isBlobTx)
+	require.Equal(t, []*share.Blob{blob}, decoded.Blobs)
+
+	txs := [][]byte{test.GenerateRandomTx(100, 200), blobTx}
+	_, err = square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.ErrorIs(t, err, square.ErrInvalidBlobTx)
+	dataSquare, err := square.ConstructWithOptions(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithNamespaceOptions(allowV1))
+	require.NoError(t, err)
+
+	_, err = square.Deconstruct(dataSquare, test.DecodeMockPFB)
+	require.Error(t, err)
+	recomputedTxs, err := square.Deconstruct(dataSquare, test.DecodeMockPFB, square.UseNamespaceOptions(allowV1))
+	require.NoError(t, err)
+	require.Equal(t, txs, recomputedTxs)
+}
+
+func TestBuilderShareArena(t *testing.T) {
+	txs := generateOrderedTxs(10, 10, 2, 1000)
+	expected, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	dataSquare, err := square.ConstructWithOptions(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithShareArena())
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+
+	// appending to a share must not overwrite the next share in the arena
+	next := bytes.Clone(dataSquare[1].ToBytes())
+	_ = append(dataSquare[0].ToBytes(), 0xff)
+	require.Equal(t, next, dataSquare[1].ToBytes())
+
+	empty, err := square.ConstructWithOptions(nil, defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithShareArena())
+	require.NoError(t, err)
+	require.Equal(t, square.EmptySquare(), empty)
+}
+
+func TestBuilderExportProgress(t *testing.T) {
+	var calls [][2]int
+	progress := func(written, total int) {
+		calls = append(calls, [2]int{written, total})
+	}
+	txs := generateOrderedTxs(200, 200, 1, 500)
+	dataSquare, err := square.ConstructWithOptions(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold, square.WithExportProgress(progress))
+	require.NoError(t, err)
+
+	require.Greater(t, len(calls), 1)
+	require.Equal(t, [2]int{len(dataSquare), len(dataSquare)}, calls[len(calls)-1])
+	for i, call := range calls {
+		require.Equal(t, len(dataSquare), call[1])
+		require.LessOrEqual(t, call[0], call[1])
+		if i > 0 {
+			require.GreaterOrEqual(t, call[0], calls[i-1][0])
+		}
+	}
+}
+
+func TestBuilderBlobElements(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	txs := generateBlobTxsWithNamespaces([]share.Namespace{ns2, ns1, ns1, ns2}, [][]int{{100, 200}, {300}, {400}})
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, txs...)
+	require.NoError(t, err)
+
+	before := builder.BlobElements()
+	require.Len(t, before, 4)
+	expected := []struct {
+		ns                  share.Namespace
+		pfbIndex, blobIndex int
+	}{
+		{ns1, 0, 1},
+		{ns1, 1, 0},
+		{ns2, 0, 0},
+		{ns2, 2, 0},
+	}
+	for i, e := range expected {
+		require.Equal(t, e.ns, before[i].Namespace)
+		require.Equal(t, e.pfbIndex, before[i].PfbIndex)
+		require.Equal(t, e.blobIndex, before[i].BlobIndex)
+		require.Equal(t, share.SparseSharesNeeded(uint32([]int{200, 300, 100, 400}[i])), before[i].NumShares)
+	}
+
+	_, err = builder.Export()
+	require.NoError(t, err)
+	require.Equal(t, before, builder.BlobElements())
+}
+
+func TestBuilderExportCopy(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	txs := append(test.GenerateTxs(200, 400, 5), generateBlobTxsWithNamespaces([]share.Namespace{ns2, ns1, ns1}, [][]int{{1000, 2000}, {300}})...)
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, txs...)
+	require.NoError(t, err)
+
+	blobs := append([]*square.Element{}, builder.Blobs...)
+	shareIndexes := make([][]uint32, len(builder.Pfbs))
+	for i, iw := range builder.Pfbs {
+		shareIndexes[i] = append([]uint32{}, iw.ShareIndexes...)
+	}
+
+	copied, err := builder.ExportCopy()
+	require.NoError(t, err)
+	require.Equal(t, blobs, builder.Blobs)
+	for i, iw := range builder.Pfbs {
+		require.Equal(t, shareIndexes[i], iw.ShareIndexes)
+	}
+
+	exported, err := builder.Export()
+	require.NoError(t, err)
+	require.Equal(t, exported, copied)
+}
+
+func TestBuilderAppendAfterExport(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	ns3 := share.MustNewV0Namespace(bytes.Repeat([]byte{3}, share.NamespaceVersionZeroIDSize))
+	txs := generateBlobTxsWithNamespaces([]share.Namespace{ns3, ns1, ns2, ns1, ns2}, [][]int{{1000, 2000}, {300}, {400, 500}})
+
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, txs[:2]...)
+	require.NoError(t, err)
+	_, err = builder.Export()
+	require.NoError(t, err)
+
+	// appending after Export must result in the same square as appending
+	// all transactions before exporting
+	blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txs[2])
+	require.NoError(t, err)
+	require.True(t, isBlobTx)
+	require.True(t, builder.AppendBlobTx(blobTx))
+	dataSquare, err := builder.Export()
+	require.NoError(t, err)
+
+	expected, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+
+	for pfbIndex, sizes := range [][]int{{1000, 2000}, {300}, {400, 500}} {
+		for blobIndex := range sizes {
+			got, err := builder.FindBlobStartingIndex(pfbIndex, blobIndex)
+			require.NoError(t, err)
+			require.Equal(t, int(builder.Pfbs[pfbIndex].ShareIndexes[blobIndex]), got)
+		}
+	}
+}
+
+func TestBuilderFindFibreTxShareRange(t *testing.T) {
+	gen := squaretest.NewGenerator(1)
+	normalTxs := gen.Txs(100, 200, 3)
+	fibreTxs := [][]byte{gen.FibreTx(gen.Namespace()), gen.FibreTx(gen.Namespace())}
+	txs := append(append(append([][]byte{}, normalTxs[:1]...), fibreTxs[0]), normalTxs[1:]...)
+	txs = append(txs, fibreTxs[1])
+	txs = append(txs, gen.BlobTxs(2, 1, 1000)...)
+
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, txs...)
+	require.NoError(t, err)
+	dataSquare, err := builder.Export()
+	require.NoError(t, err)
+
+	for i, txIndex := range []int{1, 4} {
+		fibreRange, err := builder.FindFibreTxShareRange(i)
+		require.NoError(t, err)
+		txRange, err := builder.FindTxShareRange(txIndex)
+		require.NoError(t, err)
+		require.Equal(t, txRange, fibreRange)
+
+		parsedShares, err := rawData(dataSquare[fibreRange.Start : fibreRange.End+1])
+		require.NoError(t, err)
+		require.True(t, bytes.Contains(parsedShares, fibreTxs[i]))
+	}
+
+	// the system blob of each fibre tx can be read from its sub-range
+	var txData []byte
+	for _, sh := range dataSquare {
+		if !sh.Namespace().Equals(share.TxNamespace) {
+			break
+		}
+		txData = append(txData, sh.RawData()...)
+	}
+	for i := range fibreTxs {
+		fibreTx, _, err := tx.UnmarshalFibreTx(fibreTxs[i])
+		require.NoError(t, err)
+		fibreRange, err := builder.FindFibreTxShareRange(i)
+		require.NoError(t, err)
+		blobRange, err := builder.FindSystemBlobRange(i)
+		require.NoError(t, err)
+		require.True(t, blobRange.Shares.Start >= fibreRange.Start)
+		require.True(t, blobRange.Shares.End <= fibreRange.End)
+
+		systemBlob, err := share.UnmarshalBlob(txData[blobRange.Bytes.Start:blobRange.Bytes.End])
+		require.NoError(t, err)
+		require.Equal(t, fibreTx.SystemBlob, systemBlob)
+
+		shareData, err := rawData(dataSquare[blobRange.Shares.Start:blobRange.Shares.End])
+		require.NoError(t, err)
+		encoded, err := systemBlob.Marshal()
+		require.NoError(t, err)
+		require.True(t, bytes.Contains(shareData, encoded))
+	}
+	_, err = builder.FindSystemBlobRange(2)
+	require.ErrorIs(t, err, square.ErrIndexOutOfRange)
+
+	// fibre txs appended as normal txs are included in FibreTxShareRanges
+	ranges := builder.FibreTxShareRanges()
+	require.Len(t, ranges, 2)
+	for i, fibreRange := range ranges {
+		expected, err := builder.FindFibreTxShareRange(i)
+		require.NoError(t, err)
+		require.Equal(t, expected, fibreRange)
+	}
+
+	// and survive restoring the builder
+	state, err := builder.MarshalState()
+	require.NoError(t, err)
+	restored, err := square.RestoreBuilder(state)
+	require.NoError(t, err)
+	require.Equal(t, ranges, restored.FibreTxShareRanges())
+
+	_, err = builder.FindFibreTxShareRange(2)
+	require.ErrorIs(t, err, square.ErrIndexOutOfRange)
+	_, err = builder.FindFibreTxShareRange(-1)
+	require.ErrorIs(t, err, square.ErrIndexOutOfRange)
+}
+
+func TestBuilderAppendFibreTxs(t *testing.T) {
+	gen := squaretest.NewGenerator(1)
+	fibreTxs := make([]*tx.FibreTx, 50)
+	for i := range fibreTxs {
+		fibreTx, isFibreTx, err := tx.UnmarshalFibreTx(gen.FibreTx(gen.Namespace()))
+		require.NoError(t, err)
+		require.True(t, isFibreTx)
+		fibreTxs[i] = fibreTx
+	}
+
+	// a 4x4 square only fits some of the fibre txs
+	builder, err := square.NewBuilder(4, defaultSubtreeRootThreshold, gen.Txs(200, 201, 1)...)
+	require.NoError(t, err)
+	added, err := builder.AppendFibreTxs(fibreTxs)
+	require.True(t, added > 0)
+	require.Less(t, added, len(fibreTxs))
+	var txErr *square.TxError
+	require.ErrorAs(t, err, &txErr)
+	require.Equal(t, added, txErr.Index)
+	var spaceErr *square.InsufficientSpaceError
+	require.ErrorAs(t, err, &spaceErr)
+	require.Equal(t, 1+added, builder.NumTxs())
+
+	ranges := builder.FibreTxShareRanges()
+	require.Len(t, ranges, added)
+	dataSquare, err := builder.Export()
+	require.NoError(t, err)
+	for i, fibreRange := range ranges {
+		expected, err := builder.FindFibreTxShareRange(i)
+		require.NoError(t, err)
+		require.Equal(t, expected, fibreRange)
+
+		fibreTx, err := tx.MarshalFibreTx(fibreTxs[i].Tx, fibreTxs[i].SystemBlob)
+		require.NoError(t, err)
+		parsedShares, err := rawData(dataSquare[fibreRange.Start:fibreRange.End])
+		require.NoError(t, err)
+		require.True(t, bytes.Contains(parsedShares, fibreTx))
+	}
+
+	require.NoError(t, builder.RevertLastN(1))
+	require.Len(t, builder.FibreTxShareRanges(), added-1)
+	builder.Reset()
+	require.Empty(t, builder.FibreTxShareRanges())
+
+	added, err = builder.AppendFibreTxs([]*tx.FibreTx{fibreTxs[0], nil})
+	require.Equal(t, 1, added)
+	require.ErrorAs(t, err, &txErr)
+	require.Equal(t, 1, txErr.Index)
+}
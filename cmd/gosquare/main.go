@@ -0,0 +1,241 @@
+// Command gosquare is a debugging tool for the data square layout.
+//
+// Usage:
+//
+//	gosquare build [flags] <txs.json>
+//	gosquare inspect <square.bin>
+//	gosquare share-range [flags] -tx <index> [-blob <index>] <txs.json>
+//	gosquare commitment [flags] -namespace <hex> <blob data file>
+//
+// Transactions are read as a JSON array of base64 encoded transactions. A
+// square is read as the concatenation of its shares. A path of "-" reads
+// from stdin.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	square "github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+const (
+	defaultMaxSquareSize        = 128
+	defaultSubtreeRootThreshold = 64
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		// the flag set has already printed the usage
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gosquare:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected a subcommand: build, inspect, share-range or commitment")
+	}
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "build":
+		return build(args, stdin, stdout)
+	case "inspect":
+		return inspect(args, stdin, stdout)
+	case "share-range":
+		return shareRange(args, stdin, stdout)
+	case "commitment":
+		return commitment(args, stdin, stdout)
+	default:
+		return fmt.Errorf("unknown subcommand %q", cmd)
+	}
+}
+
+type squareFlags struct {
+	maxSquareSize        int
+	subtreeRootThreshold int
+}
+
+func newFlagSet(name string, sf *squareFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	if sf != nil {
+		fs.IntVar(&sf.maxSquareSize, "max-square-size", defaultMaxSquareSize, "maximum width of the square")
+		fs.IntVar(&sf.subtreeRootThreshold, "subtree-root-threshold", defaultSubtreeRootThreshold, "subtree root threshold")
+	}
+	return fs
+}
+
+// build constructs a square and prints its hash and share statistics. The
+// hash is the SHA-256 of the concatenated shares and is meant for comparing
+// squares, it is not the data root.
+func build(args []string, stdin io.Reader, stdout io.Writer) error {
+	var sf squareFlags
+	fs := newFlagSet("build", &sf)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	txs, err := readTxs(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	dataSquare, err := square.Construct(txs, sf.maxSquareSize, sf.subtreeRootThreshold)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	for _, sh := range dataSquare {
+		hash.Write(sh.ToBytes())
+	}
+	stats := square.Stats(dataSquare)
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "hash:\t%x\n", hash.Sum(nil))
+	fmt.Fprintf(w, "size:\t%d\n", dataSquare.Size())
+	fmt.Fprintf(w, "shares:\t%d\n", stats.TotalShares)
+	fmt.Fprintf(w, "tx shares:\t%d\n", stats.TxShares)
+	fmt.Fprintf(w, "pfb shares:\t%d\n", stats.PFBShares)
+	fmt.Fprintf(w, "blob shares:\t%d\n", stats.BlobShares)
+	fmt.Fprintf(w, "padding shares:\t%d\n", stats.PaddingShares())
+	fmt.Fprintf(w, "fill ratio:\t%.4f\n", stats.FillRatio)
+	return w.Flush()
+}
+
+// inspect lists the namespaces of a square with their share ranges.
+func inspect(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("inspect", nil)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	data, err := readInput(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	if len(data)%share.ShareSize != 0 {
+		return fmt.Errorf("square of %d bytes is not a multiple of the share size %d", len(data), share.ShareSize)
+	}
+	raw := make([][]byte, 0, len(data)/share.ShareSize)
+	for i := 0; i < len(data); i += share.ShareSize {
+		raw = append(raw, data[i:i+share.ShareSize])
+	}
+	shares, err := share.FromBytes(raw)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tSTART\tEND\tBLOBS")
+	for _, info := range square.Namespaces(shares) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", info.Namespace, info.Range.Start, info.Range.End, info.Blobs)
+	}
+	return w.Flush()
+}
+
+// shareRange prints the range of shares occupied by a transaction or, if a
+// blob index is provided, by one of its blobs.
+func shareRange(args []string, stdin io.Reader, stdout io.Writer) error {
+	var sf squareFlags
+	fs := newFlagSet("share-range", &sf)
+	txIndex := fs.Int("tx", -1, "index of the transaction")
+	blobIndex := fs.Int("blob", -1, "index of the blob within the transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *txIndex < 0 {
+		return errors.New("-tx must be provided")
+	}
+	txs, err := readTxs(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	var r share.Range
+	if *blobIndex < 0 {
+		r, err = square.TxShareRange(txs, *txIndex, sf.maxSquareSize, sf.subtreeRootThreshold)
+	} else {
+		r, err = square.BlobShareRange(txs, *txIndex, *blobIndex, sf.maxSquareSize, sf.subtreeRootThreshold)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "%d %d\n", r.Start, r.End)
+	return err
+}
+
+// commitment prints the share commitment of a blob containing the data of the
+// provided file.
+func commitment(args []string, stdin io.Reader, stdout io.Writer) error {
+	var sf squareFlags
+	fs := newFlagSet("commitment", &sf)
+	nsHex := fs.String("namespace", "", "hex encoded 29 byte namespace of the blob")
+	shareVersion := fs.Uint("share-version", uint(share.ShareVersionZero), "share version of the blob")
+	signerHex := fs.String("signer", "", "hex encoded signer, required for share version 1")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	nsBytes, err := hex.DecodeString(*nsHex)
+	if err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	ns, err := share.NewNamespaceFromBytes(nsBytes)
+	if err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	var signer []byte
+	if *signerHex != "" {
+		if signer, err = hex.DecodeString(*signerHex); err != nil {
+			return fmt.Errorf("invalid signer: %w", err)
+		}
+	}
+	if *shareVersion > uint(share.MaxShareVersion) {
+		return fmt.Errorf("share version can not be greater than %d", share.MaxShareVersion)
+	}
+	data, err := readInput(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	blob, err := share.NewBlob(ns, data, uint8(*shareVersion), signer)
+	if err != nil {
+		return err
+	}
+	c, err := inclusion.CreateCommitment(blob, inclusion.MerkleRoot, sf.subtreeRootThreshold)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "%x\n", c)
+	return err
+}
+
+func readTxs(args []string, stdin io.Reader) ([][]byte, error) {
+	data, err := readInput(args, stdin)
+	if err != nil {
+		return nil, err
+	}
+	var txs [][]byte
+	if err := json.Unmarshal(data, &txs); err != nil {
+		return nil, fmt.Errorf("decoding txs: %w", err)
+	}
+	return txs, nil
+}
+
+func readInput(args []string, stdin io.Reader) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, errors.New("expected exactly one input file")
+	}
+	if args[0] == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(args[0])
+}
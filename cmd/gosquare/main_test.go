@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	square "github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/squaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	gen := squaretest.NewGenerator(1)
+	txs := append(gen.Txs(100, 200, 2), gen.BlobTxs(2, 1, 1000)...)
+	txsJSON, err := json.Marshal(txs)
+	require.NoError(t, err)
+	dataSquare, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	exec := func(stdin []byte, args ...string) (string, error) {
+		var out bytes.Buffer
+		err := run(args, bytes.NewReader(stdin), &out)
+		return out.String(), err
+	}
+
+	t.Run("build", func(t *testing.T) {
+		out, err := exec(txsJSON, "build", "-")
+		require.NoError(t, err)
+		lines := strings.Split(out, "\n")
+		require.Equal(t, []string{"size:", fmt.Sprint(dataSquare.Size())}, strings.Fields(lines[1]))
+	})
+
+	t.Run("inspect", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "square.bin")
+		require.NoError(t, os.WriteFile(path, bytes.Join(share.ToBytes(dataSquare), nil), 0o600))
+		out, err := exec(nil, "inspect", path)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Len(t, lines, len(square.Namespaces(dataSquare))+1)
+		require.True(t, strings.HasPrefix(lines[1], share.TxNamespace.String()))
+
+		_, err = exec([]byte{1}, "inspect", "-")
+		require.Error(t, err)
+	})
+
+	t.Run("share-range", func(t *testing.T) {
+		expected, err := square.BlobShareRange(txs, 2, 0, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		out, err := exec(txsJSON, "share-range", "-tx", "2", "-blob", "0", "-")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("%d %d\n", expected.Start, expected.End), out)
+
+		_, err = exec(txsJSON, "share-range", "-")
+		require.Error(t, err)
+	})
+
+	t.Run("commitment", func(t *testing.T) {
+		ns := gen.Namespace()
+		data := gen.Bytes(2000)
+		blob, err := share.NewV0Blob(ns, data)
+		require.NoError(t, err)
+		expected, err := inclusion.CreateCommitment(blob, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		out, err := exec(data, "commitment", "-namespace", hex.EncodeToString(ns.Bytes()), "-")
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(expected)+"\n", out)
+	})
+
+	t.Run("unknown subcommand", func(t *testing.T) {
+		_, err := exec(nil, "unknown")
+		require.Error(t, err)
+		_, err = exec(nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,138 @@
+package square
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/wrapper"
+)
+
+// Codec erasure codes a row or column of the data square. It is satisfied by
+// the codecs of rsmt2d. The codec must be the same one used by the network
+// (i.e. rsmt2d.NewLeoRSCodec for Celestia) for the data root to match the one
+// in block headers.
+type Codec interface {
+	// Encode returns the parity shares for the provided data shares. The
+	// number of parity shares must equal the number of data shares.
+	Encode(data [][]byte) ([][]byte, error)
+}
+
+// DataRoot returns the data root of the square. This is the root of the
+// merkle tree over the row and column roots of the extended data square, as
+// committed to in block headers. The square is extended using the provided
+// codec.
+func (s Square) DataRoot(codec Codec) ([]byte, error) {
+	rowRoots, colRoots, err := s.AxisRoots(codec)
+	if err != nil {
+		return nil, err
+	}
+	return DataRoot(rowRoots, colRoots), nil
+}
+
+// DataRoot returns the root of the merkle tree over the provided row and
+// column roots of an extended data square.
+func DataRoot(rowRoots, colRoots [][]byte) []byte {
+	roots := make([][]byte, 0, len(rowRoots)+len(colRoots))
+	roots = append(roots, rowRoots...)
+	roots = append(roots, colRoots...)
+	return inclusion.MerkleRoot(roots)
+}
+
+// AxisRoots extends the square using the provided codec and returns the
+// namespaced merkle tree roots of each row and column of the extended data
+// square.
+func (s Square) AxisRoots(codec Codec) (rowRoots, colRoots [][]byte, err error) {
+	eds, err := s.extend(codec)
+	if err != nil {
+		return nil, nil, err
+	}
+	width := len(eds)
+	rowRoots = make([][]byte, width)
+	colRoots = make([][]byte, width)
+	column := make([][]byte, width)
+	for i := 0; i < width; i++ {
+		rowRoots[i], err = erasuredAxisRoot(eds[i], i)
+		if err != nil {
+			return nil, nil, err
+		}
+		for j := 0; j < width; j++ {
+			column[j] = eds[j][i]
+		}
+		colRoots[i], err = erasuredAxisRoot(column, i)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return rowRoots, colRoots, nil
+}
+
+// extend returns the rows of the extended data square. The original data is
+// in the upper left quadrant. The upper right and lower left quadrants are
+// the parity of the original rows and columns respectively and the lower
+// right quadrant is the parity of the rows of the lower left quadrant.
+func (s Square) extend(codec Codec) ([][][]byte, error) {
+	if codec == nil {
+		return nil, errors.New("codec must not be nil")
+	}
+	size := s.Size()
+	if size*size != len(s) {
+		return nil, fmt.Errorf("square of %d shares is not a square", len(s))
+	}
+	eds := make([][][]byte, 2*size)
+	for i := range eds {
+		eds[i] = make([][]byte, 2*size)
+	}
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			eds[row][col] = s[row*size+col].ToBytes()
+		}
+	}
+
+	encode := func(data [][]byte) ([][]byte, error) {
+		parity, err := codec.Encode(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(parity) != len(data) {
+			return nil, fmt.Errorf("codec returned %d parity shares for %d data shares", len(parity), len(data))
+		}
+		return parity, nil
+	}
+
+	// extend the original columns into the lower left quadrant
+	column := make([][]byte, size)
+	for col := 0; col < size; col++ {
+		for row := 0; row < size; row++ {
+			column[row] = eds[row][col]
+		}
+		parity, err := encode(column)
+		if err != nil {
+			return nil, err
+		}
+		for row := 0; row < size; row++ {
+			eds[size+row][col] = parity[row]
+		}
+	}
+	// extend every row into the right half
+	for row := 0; row < 2*size; row++ {
+		parity, err := encode(eds[row][:size])
+		if err != nil {
+			return nil, err
+		}
+		copy(eds[row][size:], parity)
+	}
+	return eds, nil
+}
+
+// erasuredAxisRoot returns the root of the namespaced merkle tree over a row
+// or column of the extended data square.
+func erasuredAxisRoot(shares [][]byte, axisIndex int) ([]byte, error) {
+	tree := wrapper.NewErasuredNamespacedMerkleTree(uint64(len(shares)/2), uint(axisIndex))
+	for _, sh := range shares {
+		if err := tree.Push(sh); err != nil {
+			return nil, err
+		}
+	}
+	return tree.Root()
+}
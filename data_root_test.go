@@ -0,0 +1,161 @@
+package square_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/stretchr/testify/require"
+)
+
+// mirrorCodec is a trivial codec that uses the reversed data as parity.
+type mirrorCodec struct {
+	err         error
+	parityShort bool
+}
+
+func (c mirrorCodec) Encode(data [][]byte) ([][]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	parity := make([][]byte, len(data))
+	for i := range data {
+		parity[i] = data[len(data)-1-i]
+	}
+	if c.parityShort {
+		parity = parity[1:]
+	}
+	return parity, nil
+}
+
+// reedSolomonCodec is a systematic Reed–Solomon codec over GF(2^8). The data
+// shares are the evaluations of a polynomial at the points 0..k-1 and the
+// parity shares its evaluations at the points k..2k-1.
+type reedSolomonCodec struct{}
+
+func (reedSolomonCodec) Encode(data [][]byte) ([][]byte, error) {
+	k := len(data)
+	if k == 0 || 2*k > 256 {
+		return nil, fmt.Errorf("unsupported number of data shares %d", k)
+	}
+	parity := make([][]byte, k)
+	for j := range parity {
+		x := byte(k + j)
+		parity[j] = make([]byte, len(data[0]))
+		for i, shard := range data {
+			// the Lagrange basis polynomial of point i evaluated at x
+			coeff := byte(1)
+			for m := 0; m < k; m++ {
+				if m != i {
+					coeff = gfMul(coeff, gfDiv(x^byte(m), byte(i)^byte(m)))
+				}
+			}
+			for b := range shard {
+				parity[j][b] ^= gfMul(coeff, shard[b])
+			}
+		}
+	}
+	return parity, nil
+}
+
+// gfMul multiplies a and b in GF(2^8) with the reducing polynomial 0x11d.
+func gfMul(a, b byte) byte {
+	var product byte
+	for b > 0 {
+		if b&1 == 1 {
+			product ^= a
+		}
+		carry := a&0x80 != 0
+		a <<= 1
+		if carry {
+			a ^= 0x1d
+		}
+		b >>= 1
+	}
+	return product
+}
+
+// gfDiv divides a by b in GF(2^8) using b^254 as the inverse of b.
+func gfDiv(a, b byte) byte {
+	inverse := byte(1)
+	for i := 0; i < 254; i++ {
+		inverse = gfMul(inverse, b)
+	}
+	return gfMul(a, inverse)
+}
+
+// TestDataRootGolden checks the data root of the empty square against the
+// data root of empty Celestia blocks, i.e. the hash of the minimum data
+// availability header of celestia-app. With a single data share every
+// systematic Reed–Solomon code repeats the data share as parity, so the
+// result does not depend on the codec used by the network.
+func TestDataRootGolden(t *testing.T) {
+	expected, err := hex.DecodeString("3d96b7d238e7e0456f6af8e7cdf0a67bd6cf9c2089ecb559c659dcaa1f880353")
+	require.NoError(t, err)
+
+	dataRoot, err := square.EmptySquare().DataRoot(reedSolomonCodec{})
+	require.NoError(t, err)
+	require.Equal(t, expected, dataRoot)
+}
+
+func TestReedSolomonCodec(t *testing.T) {
+	// with two data shares at the points 0 and 1, the parity at the point x
+	// is (x+1)*d0 + x*d1, so the parity at 2 and 3 is 3*d0 + 2*d1 and
+	// 2*d0 + 3*d1
+	parity, err := reedSolomonCodec{}.Encode([][]byte{{1, 0}, {0, 1}})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{3, 2}, {2, 3}}, parity)
+
+	// a single data share is repeated as parity
+	parity, err = reedSolomonCodec{}.Encode([][]byte{{1, 2, 3}})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{1, 2, 3}}, parity)
+}
+
+func TestDataRoot(t *testing.T) {
+	txs := test.GenerateTxs(250, 250, 10)
+	txs = append(txs, test.GenerateBlobTxs(5, 1, 1024)...)
+	dataSquare, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	rowRoots, colRoots, err := dataSquare.AxisRoots(reedSolomonCodec{})
+	require.NoError(t, err)
+	require.Len(t, rowRoots, 2*dataSquare.Size())
+	require.Len(t, colRoots, 2*dataSquare.Size())
+
+	// original rows start with their own namespace, parity rows with the
+	// parity namespace
+	require.Equal(t, share.TxNamespace.Bytes(), rowRoots[0][:share.NamespaceSize])
+	require.Equal(t, share.ParitySharesNamespace.Bytes(), rowRoots[dataSquare.Size()][:share.NamespaceSize])
+	require.Equal(t, share.TxNamespace.Bytes(), colRoots[0][:share.NamespaceSize])
+
+	dataRoot, err := dataSquare.DataRoot(reedSolomonCodec{})
+	require.NoError(t, err)
+	require.Equal(t, inclusion.MerkleRoot(append(rowRoots, colRoots...)), dataRoot)
+	require.Equal(t, dataRoot, square.DataRoot(rowRoots, colRoots))
+
+	emptyRoot, err := square.EmptySquare().DataRoot(reedSolomonCodec{})
+	require.NoError(t, err)
+	require.NotEqual(t, dataRoot, emptyRoot)
+}
+
+func TestDataRootErrors(t *testing.T) {
+	dataSquare := square.EmptySquare()
+	_, err := dataSquare.DataRoot(nil)
+	require.Error(t, err)
+
+	codecErr := errors.New("codec failure")
+	_, err = dataSquare.DataRoot(mirrorCodec{err: codecErr})
+	require.ErrorIs(t, err, codecErr)
+
+	_, err = dataSquare.DataRoot(mirrorCodec{parityShort: true})
+	require.Error(t, err)
+
+	_, err = square.Square(share.TailPaddingShares(3)).DataRoot(mirrorCodec{})
+	require.Error(t, err)
+}
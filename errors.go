@@ -0,0 +1,129 @@
+package square
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+var (
+	// ErrInvalidMaxSquareSize is returned when the max square size is not a
+	// strictly positive power of two.
+	ErrInvalidMaxSquareSize = errors.New("invalid max square size")
+	// ErrNotEnoughSpace is returned when a transaction does not fit in the
+	// square. The error is an *InsufficientSpaceError.
+	ErrNotEnoughSpace = errors.New("not enough space")
+	// ErrTxAfterBlobTx is returned when a normal transaction is ordered after
+	// a blob transaction.
+	ErrTxAfterBlobTx = errors.New("normal tx can not be appended after blob tx")
+	// ErrInvalidBlobTx is returned when a transaction looks like a blob tx but
+	// can not be unmarshalled.
+	ErrInvalidBlobTx = errors.New("invalid blob tx")
+	// ErrBlobCountExceeded is returned when adding a blob tx would exceed the
+	// maximum number of blobs configured with WithMaxBlobCount.
+	ErrBlobCountExceeded = errors.New("max blob count exceeded")
+	// ErrBlobBytesExceeded is returned when adding a blob tx would exceed the
+	// maximum number of blob bytes configured with WithMaxBlobBytes.
+	ErrBlobBytesExceeded = errors.New("max blob bytes exceeded")
+	// ErrIndexOutOfRange is returned when a tx, pfb or blob index does not
+	// exist in the builder.
+	ErrIndexOutOfRange = errors.New("index out of range")
+	// ErrNotPFB is returned when a tx index refers to a normal transaction
+	// where a PFB was expected.
+	ErrNotPFB = errors.New("tx is not a pfb")
+	// ErrNamespaceDenied is returned when the namespace policy configured with
+	// WithNamespacePolicy denies a namespace of a blob tx. The error is a
+	// *NamespaceDeniedError.
+	ErrNamespaceDenied = errors.New("namespace denied")
+	// ErrBlobsNotContiguous is returned by Export when WithContiguousPFBBlobs
+	// is set and the blobs of a PFB can not be placed next to each other.
+	ErrBlobsNotContiguous = errors.New("blobs of pfb are not contiguous")
+	// ErrNothingToRevert is returned by RevertLastN when fewer appends than
+	// requested can be reverted.
+	ErrNothingToRevert = errors.New("not enough appends to revert")
+	// ErrBlobNotFound is returned when no blob in the square matches the
+	// requested namespace and commitment.
+	ErrBlobNotFound = errors.New("blob not found")
+	// ErrSquareHashMismatch is returned by Reconstruct when the reconstructed
+	// square does not match the expected hash. The error is a
+	// *SquareHashMismatchError.
+	ErrSquareHashMismatch = errors.New("square hash mismatch")
+	// ErrInvalidPFBIndexes is returned by VerifyPFBIndexes when the share
+	// indexes of the wrapped PFBs do not match the placement of the blobs.
+	ErrInvalidPFBIndexes = errors.New("invalid pfb share indexes")
+)
+
+// InsufficientSpaceError describes a transaction that does not fit in the
+// square. It matches ErrNotEnoughSpace.
+type InsufficientSpaceError struct {
+	// Required is the number of shares needed by the transaction, including
+	// the worst-case padding of its blobs.
+	Required int
+	// Available is the number of shares left in the square.
+	Available int
+}
+
+func (e *InsufficientSpaceError) Error() string {
+	return fmt.Sprintf("not enough space: %d shares required, %d available", e.Required, e.Available)
+}
+
+func (e *InsufficientSpaceError) Is(target error) bool {
+	return target == ErrNotEnoughSpace
+}
+
+// TxError wraps the error of the transaction at Index that could not be added
+// to the square.
+type TxError struct {
+	Index int
+	Err   error
+}
+
+func (e *TxError) Error() string {
+	return fmt.Sprintf("tx at index %d: %v", e.Index, e.Err)
+}
+
+func (e *TxError) Unwrap() error {
+	return e.Err
+}
+
+// NamespaceDeniedError describes a blob namespace that was denied by the
+// namespace policy of the builder. It matches ErrNamespaceDenied and unwraps
+// to the error returned by the policy.
+type NamespaceDeniedError struct {
+	Namespace share.Namespace
+	Err       error
+}
+
+func (e *NamespaceDeniedError) Error() string {
+	return fmt.Sprintf("namespace %s denied: %v", e.Namespace, e.Err)
+}
+
+func (e *NamespaceDeniedError) Is(target error) bool {
+	return target == ErrNamespaceDenied
+}
+
+func (e *NamespaceDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// SquareHashMismatchError describes a reconstructed square whose hash does not
+// match the expected hash. It matches ErrSquareHashMismatch.
+type SquareHashMismatchError struct {
+	Expected [32]byte
+	Actual   [32]byte
+	// Version is the square version used to reconstruct the square
+	Version SquareVersion
+	// SquareSize is the size of the reconstructed square
+	SquareSize int
+	// NumTxs is the number of transactions the square was reconstructed from
+	NumTxs int
+}
+
+func (e *SquareHashMismatchError) Error() string {
+	return fmt.Sprintf("square hash mismatch: expected %X, got %X (version %s, square size %d, %d txs)", e.Expected, e.Actual, e.Version, e.SquareSize, e.NumTxs)
+}
+
+func (e *SquareHashMismatchError) Is(target error) bool {
+	return target == ErrSquareHashMismatch
+}
@@ -0,0 +1,86 @@
+package square
+
+import (
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// Estimator tracks the worst-case number of shares a set of transactions and
+// blobs would occupy in a data square using the same accounting as the
+// Builder. Unlike the Builder it only keeps counters and never holds the
+// transactions or blobs themselves, making it suitable for mempools that want
+// to track the footprint of pending transactions.
+type Estimator struct {
+	subtreeRootThreshold int
+	version              SquareVersion
+
+	txCounter  *share.CompactShareCounter
+	pfbCounter *share.CompactShareCounter
+	blobShares int
+}
+
+// NewEstimator returns an empty estimator using the DefaultSquareVersion rules.
+func NewEstimator(subtreeRootThreshold int) *Estimator {
+	return NewEstimatorWithVersion(DefaultSquareVersion, subtreeRootThreshold)
+}
+
+// NewEstimatorWithVersion returns an empty estimator that accounts for blob
+// padding according to the rules of the provided square version.
+func NewEstimatorWithVersion(version SquareVersion, subtreeRootThreshold int) *Estimator {
+	return &Estimator{
+		subtreeRootThreshold: subtreeRootThreshold,
+		version:              version,
+		txCounter:            share.NewCompactShareCounter(),
+		pfbCounter:           share.NewCompactShareCounter(),
+	}
+}
+
+// AddTx adds a normal transaction of the given size in bytes.
+func (e *Estimator) AddTx(size int) {
+	e.txCounter.Add(size)
+}
+
+// AddPFB adds the PFB of a blob transaction where txSize is the size of the
+// PFB transaction in bytes and numBlobs is the number of blobs it pays for. The
+// PFB is accounted for with worst-case share indexes as in the Builder.
+func (e *Estimator) AddPFB(txSize int, numBlobs int) {
+	e.AddPFBWithHeight(txSize, numBlobs, 0)
+}
+
+// AddPFBWithHeight behaves like AddPFB for a blob transaction carrying the
+// provided height hint. See tx.BlobTx.HeightHint.
+func (e *Estimator) AddPFBWithHeight(txSize int, numBlobs int, height uint64) {
+	e.pfbCounter.Add(tx.WorstCaseIndexWrapperSizeWithHeight(txSize, numBlobs, height))
+}
+
+// AddBlob adds a blob where size is the length of its sequence (see
+// share.Blob.SequenceLen). The blob is accounted for with the worst-case
+// padding needed to align it. It returns an error if the namespace can not be
+// used for blobs.
+func (e *Estimator) AddBlob(ns share.Namespace, size int) error {
+	if err := ns.ValidateForBlob(); err != nil {
+		return err
+	}
+	numShares := share.SparseSharesNeeded(uint32(size))
+	e.blobShares += numShares + e.version.maxPadding(numShares, e.subtreeRootThreshold)
+	return nil
+}
+
+// Size returns the worst-case number of shares used by everything added to
+// the estimator.
+func (e *Estimator) Size() int {
+	return e.txCounter.Size() + e.pfbCounter.Size() + e.blobShares
+}
+
+// FitsIn returns true if everything added to the estimator is guaranteed to
+// fit in a square of maxSquareSize.
+func (e *Estimator) FitsIn(maxSquareSize int) bool {
+	return e.Size() <= maxSquareSize*maxSquareSize
+}
+
+// Reset clears the estimator so that it can be reused.
+func (e *Estimator) Reset() {
+	e.txCounter = share.NewCompactShareCounter()
+	e.pfbCounter = share.NewCompactShareCounter()
+	e.blobShares = 0
+}
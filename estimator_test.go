@@ -0,0 +1,47 @@
+package square_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimatorMatchesBuilder(t *testing.T) {
+	for _, version := range square.SupportedSquareVersions() {
+		builder, err := square.NewBuilderWithVersion(version, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		estimator := square.NewEstimatorWithVersion(version, defaultSubtreeRootThreshold)
+
+		for _, rawTx := range test.GenerateTxs(250, 500, 20) {
+			require.True(t, builder.AppendTx(rawTx))
+			estimator.AddTx(len(rawTx))
+		}
+		for _, rawTx := range test.GenerateBlobTxs(20, 3, 2000) {
+			blobTx, isBlobTx, err := tx.UnmarshalBlobTx(rawTx)
+			require.NoError(t, err)
+			require.True(t, isBlobTx)
+			require.True(t, builder.AppendBlobTx(blobTx))
+
+			estimator.AddPFB(len(blobTx.Tx), len(blobTx.Blobs))
+			for _, blob := range blobTx.Blobs {
+				require.NoError(t, estimator.AddBlob(blob.Namespace(), int(blob.SequenceLen())))
+			}
+		}
+		require.Equal(t, builder.CurrentSize(), estimator.Size(), version)
+		require.True(t, estimator.FitsIn(defaultMaxSquareSize))
+		require.False(t, estimator.FitsIn(1))
+
+		estimator.Reset()
+		require.Zero(t, estimator.Size())
+	}
+}
+
+func TestEstimatorRejectsReservedNamespace(t *testing.T) {
+	estimator := square.NewEstimator(defaultSubtreeRootThreshold)
+	require.Error(t, estimator.AddBlob(share.TxNamespace, 100))
+	require.Zero(t, estimator.Size())
+}
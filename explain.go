@@ -0,0 +1,164 @@
+package square
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// MaxDiscrepancies is the maximum number of differing shares reported by
+// Explain.
+const MaxDiscrepancies = 16
+
+// Discrepancy describes a share that differs between two squares.
+type Discrepancy struct {
+	// Index is the index of the share in the squares. It is -1 if the squares
+	// differ in size.
+	Index int
+	// A and B describe the share in the first and second square respectively.
+	A, B ShareContext
+}
+
+func (d Discrepancy) String() string {
+	if d.Index < 0 {
+		return "squares differ in size"
+	}
+	return fmt.Sprintf("share %d: %s != %s", d.Index, d.A, d.B)
+}
+
+// ShareContext is the decoded context of a share used to explain a
+// discrepancy.
+type ShareContext struct {
+	Namespace     share.Namespace
+	ShareVersion  uint8
+	SequenceStart bool
+	// SequenceLen is the sequence length of the share. It is zero for
+	// continuation shares.
+	SequenceLen uint32
+	// TxIndex is the index of the first normal transaction, or, for shares in
+	// the PFB namespace, the index of the first PFB occupying the share. It is
+	// -1 if the share contains no transaction or it could not be derived.
+	TxIndex int
+	// PFBIndex and BlobIndex identify the blob the share belongs to. They
+	// are -1 if the share is not part of a blob or they could not be derived.
+	PFBIndex  int
+	BlobIndex int
+}
+
+func (c ShareContext) String() string {
+	str := fmt.Sprintf("{namespace: %s, version: %d, start: %t, sequence len: %d", c.Namespace, c.ShareVersion, c.SequenceStart, c.SequenceLen)
+	if c.TxIndex >= 0 {
+		str += fmt.Sprintf(", tx: %d", c.TxIndex)
+	}
+	if c.PFBIndex >= 0 {
+		str += fmt.Sprintf(", pfb: %d, blob: %d", c.PFBIndex, c.BlobIndex)
+	}
+	return str + "}"
+}
+
+// Explain compares two squares and reports up to MaxDiscrepancies differing
+// shares along with their decoded context. If the squares differ in size, a
+// single discrepancy with an index of -1 is returned. It returns nil if the
+// squares are equal.
+func Explain(a, b Square, opts ...ReadOption) []Discrepancy {
+	cfg := newReadConfig(opts)
+	if len(a) != len(b) {
+		return []Discrepancy{{Index: -1}}
+	}
+	var discrepancies []Discrepancy
+	var ownersA, ownersB []shareOwner
+	for i := range a {
+		if bytes.Equal(a[i].ToBytes(), b[i].ToBytes()) {
+			continue
+		}
+		// deriving the owners requires parsing the squares so only do it
+		// once there is a discrepancy
+		if ownersA == nil {
+			ownersA, ownersB = shareOwners(a, cfg.namespaces), shareOwners(b, cfg.namespaces)
+		}
+		discrepancies = append(discrepancies, Discrepancy{
+			Index: i,
+			A:     newShareContext(a[i], ownersA[i]),
+			B:     newShareContext(b[i], ownersB[i]),
+		})
+		if len(discrepancies) == MaxDiscrepancies {
+			break
+		}
+	}
+	return discrepancies
+}
+
+func newShareContext(sh share.Share, owner shareOwner) ShareContext {
+	return ShareContext{
+		Namespace:     sh.Namespace(),
+		ShareVersion:  sh.Version(),
+		SequenceStart: sh.IsSequenceStart(),
+		SequenceLen:   sh.SequenceLen(),
+		TxIndex:       owner.txIndex,
+		PFBIndex:      owner.pfbIndex,
+		BlobIndex:     owner.blobIndex,
+	}
+}
+
+type shareOwner struct {
+	txIndex   int
+	pfbIndex  int
+	blobIndex int
+}
+
+// shareOwners returns the owner of each share in the square as far as it can
+// be derived from the square alone.
+func shareOwners(s Square, namespaces ReservedNamespaces) []shareOwner {
+	owners := make([]shareOwner, len(s))
+	for i := range owners {
+		owners[i] = shareOwner{txIndex: -1, pfbIndex: -1, blobIndex: -1}
+	}
+
+	assignTxs := func(ns share.Namespace) [][]byte {
+		r := share.GetShareRangeForNamespace(s, ns)
+		if r.IsEmpty() {
+			return nil
+		}
+		txs, err := share.ParseTxs(s[r.Start:r.End])
+		if err != nil {
+			return nil
+		}
+		splitter := share.NewCompactShareSplitter(ns, share.ShareVersionZero)
+		for _, txBytes := range txs {
+			if err := splitter.WriteTx(txBytes); err != nil {
+				return nil
+			}
+		}
+		ranges := splitter.ShareRanges(r.Start)
+		for txIndex := len(txs) - 1; txIndex >= 0; txIndex-- {
+			txRange := ranges[sha256.Sum256(txs[txIndex])]
+			for i := txRange.Start; i < txRange.End && i < len(s); i++ {
+				owners[i].txIndex = txIndex
+			}
+		}
+		return txs
+	}
+	assignTxs(namespaces.Tx)
+	wpfbs := assignTxs(namespaces.PayForBlob)
+
+	for pfbIndex, wpfbBytes := range wpfbs {
+		wpfb, isWpfb := tx.UnmarshalIndexWrapper(wpfbBytes)
+		if !isWpfb {
+			continue
+		}
+		for blobIndex, shareIndex := range wpfb.ShareIndexes {
+			if int(shareIndex) >= len(s) {
+				continue
+			}
+			end := int(shareIndex) + share.SparseSharesNeeded(s[shareIndex].SequenceLen())
+			for i := int(shareIndex); i < end && i < len(s); i++ {
+				owners[i].pfbIndex = pfbIndex
+				owners[i].blobIndex = blobIndex
+			}
+		}
+	}
+	return owners
+}
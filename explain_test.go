@@ -0,0 +1,53 @@
+package square_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/squaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	gen := squaretest.NewGenerator(3)
+	txs := append(gen.Txs(200, 300, 3), gen.BlobTxs(2, 1, 1000)...)
+	a, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Nil(t, square.Explain(a, a))
+
+	blobRange, err := square.BlobShareRange(txs, 4, 0, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	tamper := func(s square.Square, index int) {
+		data := append([]byte(nil), s[index].ToBytes()...)
+		data[len(data)-1]++
+		sh, err := share.NewShare(data)
+		require.NoError(t, err)
+		s[index] = *sh
+	}
+	b := append(square.Square(nil), a...)
+	tamper(b, 0)
+	tamper(b, blobRange.Start+1)
+
+	discrepancies := square.Explain(a, b)
+	require.Len(t, discrepancies, 2)
+
+	require.Equal(t, 0, discrepancies[0].Index)
+	require.Equal(t, share.TxNamespace, discrepancies[0].A.Namespace)
+	require.True(t, discrepancies[0].A.SequenceStart)
+	require.Equal(t, 0, discrepancies[0].A.TxIndex)
+	require.Equal(t, -1, discrepancies[0].A.PFBIndex)
+
+	require.Equal(t, blobRange.Start+1, discrepancies[1].Index)
+	require.False(t, discrepancies[1].B.SequenceStart)
+	require.Equal(t, -1, discrepancies[1].B.TxIndex)
+	require.Equal(t, 1, discrepancies[1].B.PFBIndex)
+	require.Equal(t, 0, discrepancies[1].B.BlobIndex)
+	require.Contains(t, discrepancies[1].String(), "pfb: 1, blob: 0")
+
+	smaller, err := square.Construct(txs[:3], defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	discrepancies = square.Explain(a, smaller)
+	require.Equal(t, []square.Discrepancy{{Index: -1}}, discrepancies)
+	require.Equal(t, "squares differ in size", discrepancies[0].String())
+}
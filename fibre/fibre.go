@@ -0,0 +1,64 @@
+// Package fibre contains the canonical encoding of the system blobs that
+// PayForFibre transactions include in the square in place of the fibre data.
+package fibre
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+const (
+	// VersionSize is the number of bytes used to encode the fibre version.
+	VersionSize = 4
+	// CommitmentSize is the number of bytes of the commitment to the fibre
+	// data.
+	CommitmentSize = 32
+	// SystemBlobSize is the size of the data of a system blob.
+	SystemBlobSize = VersionSize + CommitmentSize
+)
+
+// FibreInfo is the information committed to in a system blob.
+type FibreInfo struct {
+	Namespace  share.Namespace
+	Version    uint32
+	Commitment []byte
+	Signer     []byte
+}
+
+// SystemBlobFromCommitment creates the system blob for fibre data with the
+// given commitment. The data of the blob consists of the big endian encoded
+// version followed by the 32 byte commitment. The blob uses share version 1
+// so that the signer is committed to as well.
+func SystemBlobFromCommitment(ns share.Namespace, version uint32, commitment, signer []byte) (*share.Blob, error) {
+	if len(commitment) != CommitmentSize {
+		return nil, fmt.Errorf("commitment must be %d bytes, got %d", CommitmentSize, len(commitment))
+	}
+	data := make([]byte, SystemBlobSize)
+	binary.BigEndian.PutUint32(data[:VersionSize], version)
+	copy(data[VersionSize:], commitment)
+	return share.NewV1Blob(ns, data, signer)
+}
+
+// ParseSystemBlob decodes the fibre information from a system blob created by
+// SystemBlobFromCommitment.
+func ParseSystemBlob(blob *share.Blob) (FibreInfo, error) {
+	if blob == nil {
+		return FibreInfo{}, errors.New("system blob is nil")
+	}
+	if blob.ShareVersion() != share.ShareVersionOne {
+		return FibreInfo{}, fmt.Errorf("system blob must have share version %d, got %d", share.ShareVersionOne, blob.ShareVersion())
+	}
+	data := blob.Data()
+	if len(data) != SystemBlobSize {
+		return FibreInfo{}, fmt.Errorf("system blob data must be %d bytes, got %d", SystemBlobSize, len(data))
+	}
+	return FibreInfo{
+		Namespace:  blob.Namespace(),
+		Version:    binary.BigEndian.Uint32(data[:VersionSize]),
+		Commitment: data[VersionSize:],
+		Signer:     blob.Signer(),
+	}, nil
+}
@@ -0,0 +1,99 @@
+package fibre_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/fibre"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemBlobRoundTrip(t *testing.T) {
+	ns := share.RandomBlobNamespace()
+	commitment := bytes.Repeat([]byte{7}, fibre.CommitmentSize)
+	signer := bytes.Repeat([]byte{1}, share.SignerSize)
+
+	blob, err := fibre.SystemBlobFromCommitment(ns, 2, commitment, signer)
+	require.NoError(t, err)
+	require.Equal(t, fibre.SystemBlobSize, blob.DataLen())
+
+	fibreTx, err := tx.MarshalFibreTx([]byte("tx"), blob)
+	require.NoError(t, err)
+	unmarshalled, isFibreTx, err := tx.UnmarshalFibreTx(fibreTx)
+	require.NoError(t, err)
+	require.True(t, isFibreTx)
+
+	info, err := fibre.ParseSystemBlob(unmarshalled.SystemBlob)
+	require.NoError(t, err)
+	require.Equal(t, fibre.FibreInfo{
+		Namespace:  ns,
+		Version:    2,
+		Commitment: commitment,
+		Signer:     signer,
+	}, info)
+}
+
+func TestSystemBlobErrors(t *testing.T) {
+	ns := share.RandomBlobNamespace()
+	signer := bytes.Repeat([]byte{1}, share.SignerSize)
+
+	_, err := fibre.SystemBlobFromCommitment(ns, 0, []byte{1}, signer)
+	require.Error(t, err)
+	_, err = fibre.SystemBlobFromCommitment(ns, 0, make([]byte, fibre.CommitmentSize), nil)
+	require.Error(t, err)
+
+	_, err = fibre.ParseSystemBlob(nil)
+	require.Error(t, err)
+
+	v0Blob, err := share.NewV0Blob(ns, make([]byte, fibre.SystemBlobSize))
+	require.NoError(t, err)
+	_, err = fibre.ParseSystemBlob(v0Blob)
+	require.Error(t, err)
+
+	shortBlob, err := share.NewV1Blob(ns, make([]byte, fibre.SystemBlobSize-1), signer)
+	require.NoError(t, err)
+	_, err = fibre.ParseSystemBlob(shortBlob)
+	require.Error(t, err)
+}
+
+func TestSystemBlobProto(t *testing.T) {
+	ns := share.RandomBlobNamespace()
+	commitment := bytes.Repeat([]byte{7}, fibre.CommitmentSize)
+	signer := bytes.Repeat([]byte{1}, share.SignerSize)
+
+	blob, err := fibre.SystemBlobFromCommitment(ns, 3, commitment, signer)
+	require.NoError(t, err)
+
+	pb, err := fibre.SystemBlobToProto(blob)
+	require.NoError(t, err)
+	require.Equal(t, ns.ID(), pb.NamespaceId)
+	require.Equal(t, uint32(ns.Version()), pb.NamespaceVersion)
+	require.Equal(t, uint32(3), pb.FibreBlobVersion)
+	require.Equal(t, commitment, pb.Commitment)
+	require.Equal(t, signer, pb.Signer)
+
+	// converting back produces the same blob on the wire
+	converted, err := fibre.SystemBlobFromProto(pb)
+	require.NoError(t, err)
+	expected, err := blob.Marshal()
+	require.NoError(t, err)
+	actual, err := converted.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	info, err := fibre.FibreInfoFromProto(pb)
+	require.NoError(t, err)
+	require.Equal(t, pb, info.ToProto())
+
+	_, err = fibre.FibreInfoFromProto(nil)
+	require.Error(t, err)
+	pb.Commitment = pb.Commitment[1:]
+	_, err = fibre.SystemBlobFromProto(pb)
+	require.Error(t, err)
+	pb.Commitment = commitment
+	pb.NamespaceVersion = 256
+	_, err = fibre.SystemBlobFromProto(pb)
+	require.Error(t, err)
+}
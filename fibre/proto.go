@@ -0,0 +1,66 @@
+package fibre
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+// ToProto converts the fibre information into its explicit protobuf
+// representation.
+func (f FibreInfo) ToProto() *v1.FibreSystemBlob {
+	return &v1.FibreSystemBlob{
+		NamespaceId:      f.Namespace.ID(),
+		NamespaceVersion: uint32(f.Namespace.Version()),
+		Signer:           f.Signer,
+		FibreBlobVersion: f.Version,
+		Commitment:       f.Commitment,
+	}
+}
+
+// FibreInfoFromProto converts the explicit protobuf representation of a
+// system blob into fibre information.
+func FibreInfoFromProto(pb *v1.FibreSystemBlob) (FibreInfo, error) {
+	if pb == nil {
+		return FibreInfo{}, errors.New("fibre system blob is nil")
+	}
+	if pb.NamespaceVersion > math.MaxUint8 {
+		return FibreInfo{}, errors.New("namespace version can not be greater than MaxUint8")
+	}
+	ns, err := share.NewNamespace(uint8(pb.NamespaceVersion), pb.NamespaceId)
+	if err != nil {
+		return FibreInfo{}, err
+	}
+	if len(pb.Commitment) != CommitmentSize {
+		return FibreInfo{}, fmt.Errorf("commitment must be %d bytes, got %d", CommitmentSize, len(pb.Commitment))
+	}
+	return FibreInfo{
+		Namespace:  ns,
+		Version:    pb.FibreBlobVersion,
+		Commitment: pb.Commitment,
+		Signer:     pb.Signer,
+	}, nil
+}
+
+// SystemBlobFromProto converts the explicit protobuf representation into the
+// system blob that is included in the square and sent on the wire.
+func SystemBlobFromProto(pb *v1.FibreSystemBlob) (*share.Blob, error) {
+	info, err := FibreInfoFromProto(pb)
+	if err != nil {
+		return nil, err
+	}
+	return SystemBlobFromCommitment(info.Namespace, info.Version, info.Commitment, info.Signer)
+}
+
+// SystemBlobToProto converts a system blob into its explicit protobuf
+// representation.
+func SystemBlobToProto(blob *share.Blob) (*v1.FibreSystemBlob, error) {
+	info, err := ParseSystemBlob(blob)
+	if err != nil {
+		return nil, err
+	}
+	return info.ToProto(), nil
+}
@@ -0,0 +1,152 @@
+package inclusion
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"sync"
+
+	"github.com/celestiaorg/nmt"
+	"github.com/celestiaorg/nmt/namespace"
+
+	sh "github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/wrapper"
+)
+
+// BatchHasher computes the namespaced merkle tree leaf hashes of many leaves
+// at once. It allows accelerated implementations, for example ones batching
+// sha256 with AVX2 or SHA-NI, to be plugged into CreateParallelCommitments.
+//
+// HashLeaves must return one hash per leaf, in order, equal to the hash of the
+// leaf prefixed with namespace as computed by nmt.NmtHasher.HashLeaf with the
+// same base hash function as the commitment. It may be called concurrently.
+type BatchHasher interface {
+	HashLeaves(namespace []byte, leaves [][]byte) [][]byte
+}
+
+// NewBatchHasher returns the default BatchHasher which hashes the leaves one
+// by one using sha256.
+func NewBatchHasher() BatchHasher {
+	return serialBatchHasher{newHasher: sha256.New}
+}
+
+type serialBatchHasher struct {
+	newHasher func() hash.Hash
+}
+
+func (h serialBatchHasher) HashLeaves(ns []byte, leaves [][]byte) [][]byte {
+	hasher := newNmtHasher(h.newHasher())
+	hashes := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		ndata := make([]byte, 0, len(ns)+len(leaf))
+		ndata = append(ndata, ns...)
+		ndata = append(ndata, leaf...)
+		hashes[i] = hasher.MustHashLeaf(ndata)
+	}
+	return hashes
+}
+
+func newNmtHasher(h hash.Hash) *nmt.NmtHasher {
+	return nmt.NewNmtHasher(h, namespace.IDSize(sh.NamespaceSize), true)
+}
+
+// precomputedLeafHasher is an nmt.Hasher which returns the leaf hashes
+// computed by a BatchHasher in push order and hashes nodes as usual.
+type precomputedLeafHasher struct {
+	*nmt.NmtHasher
+	hashes [][]byte
+}
+
+func (h *precomputedLeafHasher) HashLeaf([]byte) ([]byte, error) {
+	if len(h.hashes) == 0 {
+		return nil, errors.New("no precomputed leaf hash left")
+	}
+	leafHash := h.hashes[0]
+	h.hashes = h.hashes[1:]
+	return leafHash, nil
+}
+
+// CreateParallelCommitments generates the share commitments of the blobs
+// using up to workers goroutines. The leaves of every subtree are hashed in a
+// single call to hasher, which defaults to a serial hasher using the base hash
+// function of opts if nil. A custom hasher must use the same hash function as
+// opts. The commitments are equal to those returned by CreateCommitments with
+// the same opts.
+func CreateParallelCommitments(blobs []*sh.Blob, merkleRootFn MerkleRootFn, subtreeRootThreshold, workers int, hasher BatchHasher, opts ...CommitmentOption) ([][]byte, error) {
+	cfg := newCommitmentConfig(opts)
+	if hasher == nil {
+		hasher = serialBatchHasher{newHasher: cfg.newHasher}
+	}
+	workers = max(1, min(workers, len(blobs)))
+
+	commitments := make([][]byte, len(blobs))
+	errs := make([]error, len(blobs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				roots, err := batchSubtreeRoots(blobs[i], subtreeRootThreshold, hasher, cfg)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				commitments[i] = merkleRootFn(roots)
+			}
+		}()
+	}
+	for i := range blobs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return commitments, nil
+}
+
+// batchSubtreeRoots computes the same subtree roots as GenerateSubtreeRoots
+// but hashes the leaves of each subtree using hasher.
+func batchSubtreeRoots(blob *sh.Blob, subtreeRootThreshold int, hasher BatchHasher, cfg *commitmentConfig) ([][]byte, error) {
+	shares, err := splitBlobs(blob)
+	if err != nil {
+		return nil, err
+	}
+	subTreeWidth := SubTreeWidth(len(shares), subtreeRootThreshold)
+	treeSizes, err := MerkleMountainRangeSizes(uint64(len(shares)), uint64(subTreeWidth))
+	if err != nil {
+		return nil, err
+	}
+
+	ns := blob.Namespace().Bytes()
+	subTreeRoots := make([][]byte, len(treeSizes))
+	cursor := uint64(0)
+	for i, treeSize := range treeSizes {
+		leaves := sh.ToBytes(shares[cursor : cursor+treeSize])
+		cursor += treeSize
+		hashes := hasher.HashLeaves(ns, leaves)
+		if len(hashes) != len(leaves) {
+			return nil, fmt.Errorf("batch hasher returned %d hashes for %d leaves", len(hashes), len(leaves))
+		}
+		leafHasher := &precomputedLeafHasher{NmtHasher: newNmtHasher(cfg.newHasher()), hashes: hashes}
+		tree := wrapper.NewErasuredNamespacedMerkleTreeWithHasher(cfg.newHasher(), uint64(len(leaves)), 0, nmt.CustomHasher(leafHasher))
+		for _, leaf := range leaves {
+			if err := tree.Push(leaf); err != nil {
+				return nil, err
+			}
+		}
+		root, err := tree.Root()
+		if err != nil {
+			return nil, err
+		}
+		subTreeRoots[i] = root
+	}
+	return subTreeRoots, nil
+}
@@ -0,0 +1,75 @@
+package inclusion_test
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/stretchr/testify/require"
+)
+
+type countingBatchHasher struct {
+	inclusion.BatchHasher
+	calls chan struct{}
+}
+
+func (h countingBatchHasher) HashLeaves(namespace []byte, leaves [][]byte) [][]byte {
+	h.calls <- struct{}{}
+	return h.BatchHasher.HashLeaves(namespace, leaves)
+}
+
+func TestCreateParallelCommitments(t *testing.T) {
+	blobs := make([]*share.Blob, 8)
+	for i := range blobs {
+		blob, err := share.NewV0Blob(share.RandomBlobNamespace(), bytes.Repeat([]byte{byte(i)}, (i+1)*1000))
+		require.NoError(t, err)
+		blobs[i] = blob
+	}
+	expected, err := inclusion.CreateCommitments(blobs, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		commitments, err := inclusion.CreateParallelCommitments(blobs, inclusion.MerkleRoot, defaultSubtreeRootThreshold, workers, nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, commitments)
+	}
+
+	hasher := countingBatchHasher{BatchHasher: inclusion.NewBatchHasher(), calls: make(chan struct{}, 1000)}
+	commitments, err := inclusion.CreateParallelCommitments(blobs, inclusion.MerkleRoot, defaultSubtreeRootThreshold, 4, hasher)
+	require.NoError(t, err)
+	require.Equal(t, expected, commitments)
+	require.True(t, len(hasher.calls) > 0)
+}
+
+type truncatingBatchHasher struct{}
+
+func (truncatingBatchHasher) HashLeaves(namespace []byte, leaves [][]byte) [][]byte {
+	return inclusion.NewBatchHasher().HashLeaves(namespace, leaves[1:])
+}
+
+func TestCreateParallelCommitmentsInvalidHasher(t *testing.T) {
+	blob, err := share.NewV0Blob(share.RandomBlobNamespace(), bytes.Repeat([]byte{1}, 100))
+	require.NoError(t, err)
+	_, err = inclusion.CreateParallelCommitments([]*share.Blob{blob}, inclusion.MerkleRoot, defaultSubtreeRootThreshold, 1, truncatingBatchHasher{})
+	require.Error(t, err)
+}
+
+func TestCreateParallelCommitmentsWithHasher(t *testing.T) {
+	blobs := make([]*share.Blob, 4)
+	for i := range blobs {
+		blob, err := share.NewV0Blob(share.RandomBlobNamespace(), bytes.Repeat([]byte{byte(i)}, (i+1)*3000))
+		require.NoError(t, err)
+		blobs[i] = blob
+	}
+	expected, err := inclusion.CreateCommitments(blobs, inclusion.MerkleRoot, defaultSubtreeRootThreshold, inclusion.WithHasher(sha512.New))
+	require.NoError(t, err)
+	defaults, err := inclusion.CreateCommitments(blobs, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.NotEqual(t, defaults, expected)
+
+	commitments, err := inclusion.CreateParallelCommitments(blobs, inclusion.MerkleRoot, defaultSubtreeRootThreshold, 2, nil, inclusion.WithHasher(sha512.New))
+	require.NoError(t, err)
+	require.Equal(t, expected, commitments)
+}
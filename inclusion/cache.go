@@ -0,0 +1,139 @@
+package inclusion
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+
+	sh "github.com/celestiaorg/go-square/v2/share"
+)
+
+// subtreeRootKey identifies the subtree roots of a blob.
+type subtreeRootKey struct {
+	namespace            string
+	sharesHash           [sha256.Size]byte
+	subtreeRootThreshold int
+}
+
+type subtreeRootEntry struct {
+	key   subtreeRootKey
+	roots [][]byte
+}
+
+// SubtreeRootCache memoizes the subtree roots of blobs, keyed by the blob's
+// namespace and the hash of its shares. When the same blob appears in multiple
+// candidate squares, for example during consensus retries, its roots only
+// need to be computed once. The least recently used entries are evicted once
+// the cache is full. It is safe for concurrent use. Roots are always computed
+// with the default sha256 hasher.
+type SubtreeRootCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[subtreeRootKey]*list.Element
+	// lru orders the entries from most to least recently used
+	lru    *list.List
+	hits   uint64
+	misses uint64
+}
+
+// NewSubtreeRootCache returns a cache holding the subtree roots of up to
+// maxEntries blobs.
+func NewSubtreeRootCache(maxEntries int) *SubtreeRootCache {
+	return &SubtreeRootCache{
+		maxEntries: maxEntries,
+		entries:    make(map[subtreeRootKey]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// GenerateSubtreeRoots behaves like GenerateSubtreeRoots but returns the
+// memoized roots if the blob has been seen before. The returned roots are
+// shared with the cache and must not be modified.
+func (c *SubtreeRootCache) GenerateSubtreeRoots(blob *sh.Blob, subtreeRootThreshold int) ([][]byte, error) {
+	shares, err := splitBlobs(blob)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	for _, share := range shares {
+		hash.Write(share.ToBytes())
+	}
+	key := subtreeRootKey{
+		namespace:            string(blob.Namespace().Bytes()),
+		subtreeRootThreshold: subtreeRootThreshold,
+	}
+	hash.Sum(key.sharesHash[:0])
+
+	if roots, ok := c.get(key); ok {
+		return roots, nil
+	}
+	roots, err := generateSubtreeRoots(shares, subtreeRootThreshold, newCommitmentConfig(nil))
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, roots)
+	return roots, nil
+}
+
+// CreateCommitment behaves like CreateCommitment but uses the memoized
+// subtree roots of the blob if it has been seen before.
+func (c *SubtreeRootCache) CreateCommitment(blob *sh.Blob, merkleRootFn MerkleRootFn, subtreeRootThreshold int) ([]byte, error) {
+	subTreeRoots, err := c.GenerateSubtreeRoots(blob, subtreeRootThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return merkleRootFn(subTreeRoots), nil
+}
+
+// Hits returns the number of lookups that returned memoized roots.
+func (c *SubtreeRootCache) Hits() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+// Misses returns the number of lookups that had to compute the roots.
+func (c *SubtreeRootCache) Misses() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.misses
+}
+
+// Len returns the number of blobs whose roots are in the cache.
+func (c *SubtreeRootCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *SubtreeRootCache) get(key subtreeRootKey) ([][]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*subtreeRootEntry).roots, true
+}
+
+func (c *SubtreeRootCache) add(key subtreeRootKey, roots [][]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxEntries <= 0 {
+		return
+	}
+	// another goroutine may have added the roots in the meantime
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&subtreeRootEntry{key: key, roots: roots})
+	if c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*subtreeRootEntry).key)
+	}
+}
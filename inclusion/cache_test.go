@@ -0,0 +1,53 @@
+package inclusion_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtreeRootCache(t *testing.T) {
+	cache := inclusion.NewSubtreeRootCache(2)
+	blobs := make([]*share.Blob, 3)
+	for i := range blobs {
+		blob, err := share.NewV0Blob(share.RandomBlobNamespace(), bytes.Repeat([]byte{byte(i)}, 2000))
+		require.NoError(t, err)
+		blobs[i] = blob
+	}
+
+	for _, blob := range blobs[:2] {
+		expected, err := inclusion.GenerateSubtreeRoots(blob, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			roots, err := cache.GenerateSubtreeRoots(blob, defaultSubtreeRootThreshold)
+			require.NoError(t, err)
+			require.Equal(t, expected, roots)
+		}
+	}
+	require.Equal(t, uint64(2), cache.Hits())
+	require.Equal(t, uint64(2), cache.Misses())
+	require.Equal(t, 2, cache.Len())
+
+	// a different threshold is cached separately
+	_, err := cache.GenerateSubtreeRoots(blobs[0], 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), cache.Misses())
+
+	// the least recently used blob was evicted
+	require.Equal(t, 2, cache.Len())
+	_, err = cache.GenerateSubtreeRoots(blobs[1], defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), cache.Hits())
+	_, err = cache.GenerateSubtreeRoots(blobs[0], defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), cache.Misses())
+
+	expected, err := inclusion.CreateCommitment(blobs[2], inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	commitment, err := cache.CreateCommitment(blobs[2], inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expected, commitment)
+}
@@ -1,38 +1,81 @@
 package inclusion
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"hash"
 
 	sh "github.com/celestiaorg/go-square/v2/share"
-	"github.com/celestiaorg/nmt"
+	"github.com/celestiaorg/go-square/v2/wrapper"
 )
 
 type MerkleRootFn func([][]byte) []byte
 
+// CommitmentOption configures how share commitments and subtree roots are
+// computed.
+type CommitmentOption func(*commitmentConfig)
+
+type commitmentConfig struct {
+	newHasher func() hash.Hash
+}
+
+// WithHasher overrides the base hash function of the namespaced merkle trees
+// used to compute subtree roots. The default is sha256 which is required for
+// compatibility with Celestia. Note that the merkleRootFn passed to
+// CreateCommitment is independent of this option and should be chosen to match.
+func WithHasher(newHasher func() hash.Hash) CommitmentOption {
+	return func(cfg *commitmentConfig) {
+		cfg.newHasher = newHasher
+	}
+}
+
+func newCommitmentConfig(opts []CommitmentOption) *commitmentConfig {
+	cfg := &commitmentConfig{newHasher: sha256.New}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
 // CreateCommitment generates the share commitment for a given blob.
 // See [data square layout rationale] and [blob share commitment rules].
 //
 // [data square layout rationale]: ../../specs/src/specs/data_square_layout.md
 // [blob share commitment rules]: ../../specs/src/specs/data_square_layout.md#blob-share-commitment-rules
-func CreateCommitment(blob *sh.Blob, merkleRootFn MerkleRootFn, subtreeRootThreshold int) ([]byte, error) {
-	subTreeRoots, err := GenerateSubtreeRoots(blob, subtreeRootThreshold)
+func CreateCommitment(blob *sh.Blob, merkleRootFn MerkleRootFn, subtreeRootThreshold int, opts ...CommitmentOption) ([]byte, error) {
+	subTreeRoots, err := GenerateSubtreeRoots(blob, subtreeRootThreshold, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return merkleRootFn(subTreeRoots), nil
 }
 
+// VerifyCommitment reports whether commitment is the share commitment of the
+// given blob. An error is only returned if the commitment of the blob could
+// not be generated.
+func VerifyCommitment(blob *sh.Blob, commitment []byte, merkleRootFn MerkleRootFn, subtreeRootThreshold int, opts ...CommitmentOption) (bool, error) {
+	expected, err := CreateCommitment(blob, merkleRootFn, subtreeRootThreshold, opts...)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(expected, commitment), nil
+}
+
 // GenerateSubtreeRoots generates the subtree roots of a blob.
 // See [data square layout rationale] and [blob share commitment rules].
 //
 // [data square layout rationale]: ../../specs/src/specs/data_square_layout.md
 // [blob share commitment rules]: ../../specs/src/specs/data_square_layout.md#blob-share-commitment-rules
-func GenerateSubtreeRoots(blob *sh.Blob, subtreeRootThreshold int) ([][]byte, error) {
+func GenerateSubtreeRoots(blob *sh.Blob, subtreeRootThreshold int, opts ...CommitmentOption) ([][]byte, error) {
 	shares, err := splitBlobs(blob)
 	if err != nil {
 		return nil, err
 	}
+	return generateSubtreeRoots(shares, subtreeRootThreshold, newCommitmentConfig(opts))
+}
 
+// generateSubtreeRoots generates the subtree roots of the shares of a blob.
+func generateSubtreeRoots(shares []sh.Share, subtreeRootThreshold int, cfg *commitmentConfig) ([][]byte, error) {
 	// the commitment is the root of a merkle mountain range with max tree size
 	// determined by the number of roots required to create a share commitment
 	// over that blob. The size of the tree is only increased if the number of
@@ -49,24 +92,15 @@ func GenerateSubtreeRoots(blob *sh.Blob, subtreeRootThreshold int) ([][]byte, er
 		cursor += treeSize
 	}
 
-	namespace := blob.Namespace()
 	// create the commitments by pushing each leaf set onto an NMT
 	subTreeRoots := make([][]byte, len(leafSets))
 	for i, set := range leafSets {
-		// Create the NMT. TODO: use NMT wrapper.
-		tree := nmt.New(sha256.New(), nmt.NamespaceIDSize(sh.NamespaceSize), nmt.IgnoreMaxNamespace(true))
+		// The shares of a blob lie in the original data square so the wrapper
+		// prefixes each leaf with the blob's namespace, matching the leaves of
+		// the row roots.
+		tree := wrapper.NewErasuredNamespacedMerkleTreeWithHasher(cfg.newHasher(), uint64(len(set)), 0)
 		for _, leaf := range set {
-			// the namespace must be added again here even though it is already
-			// included in the leaf to ensure that the hash will match that of
-			// the NMT wrapper (pkg/wrapper). Each namespace is added to keep
-			// the namespace in the share, and therefore the parity data, while
-			// also allowing for the manual addition of the parity namespace to
-			// the parity data.
-			nsLeaf := make([]byte, 0)
-			nsLeaf = append(nsLeaf, namespace.Bytes()...)
-			nsLeaf = append(nsLeaf, leaf...)
-
-			err = tree.Push(nsLeaf)
+			err = tree.Push(leaf)
 			if err != nil {
 				return nil, err
 			}
@@ -81,10 +115,10 @@ func GenerateSubtreeRoots(blob *sh.Blob, subtreeRootThreshold int) ([][]byte, er
 	return subTreeRoots, nil
 }
 
-func CreateCommitments(blobs []*sh.Blob, merkleRootFn MerkleRootFn, subtreeRootThreshold int) ([][]byte, error) {
+func CreateCommitments(blobs []*sh.Blob, merkleRootFn MerkleRootFn, subtreeRootThreshold int, opts ...CommitmentOption) ([][]byte, error) {
 	commitments := make([][]byte, len(blobs))
 	for i, blob := range blobs {
-		commitment, err := CreateCommitment(blob, merkleRootFn, subtreeRootThreshold)
+		commitment, err := CreateCommitment(blob, merkleRootFn, subtreeRootThreshold, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -130,3 +164,14 @@ func splitBlobs(blobs ...*sh.Blob) ([]sh.Share, error) {
 	}
 	return writer.Export(), nil
 }
+
+// BatchCommitment generates the share commitment of the physical blob of a
+// batch of blobs. This is the single commitment that a PFB paying for the
+// batch commits to.
+func BatchCommitment(batch *sh.BlobBatch, merkleRootFn MerkleRootFn, subtreeRootThreshold int, opts ...CommitmentOption) ([]byte, error) {
+	blob, err := batch.Blob()
+	if err != nil {
+		return nil, err
+	}
+	return CreateCommitment(blob, merkleRootFn, subtreeRootThreshold, opts...)
+}
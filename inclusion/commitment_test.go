@@ -3,6 +3,7 @@ package inclusion_test
 import (
 	"bytes"
 	"crypto/sha256"
+	"crypto/sha512"
 	"testing"
 
 	"github.com/celestiaorg/go-square/v2/inclusion"
@@ -113,3 +114,87 @@ func twoLeafMerkleRoot(data [][]byte) []byte {
 	sum := sha256.Sum256(append(h1[:], h2[:]...))
 	return sum[:]
 }
+
+func TestMerkleRoot(t *testing.T) {
+	leaf := func(item []byte) []byte {
+		sum := sha256.Sum256(append([]byte{0}, item...))
+		return sum[:]
+	}
+	inner := func(left, right []byte) []byte {
+		sum := sha256.Sum256(append(append([]byte{1}, left...), right...))
+		return sum[:]
+	}
+	empty := sha256.Sum256(nil)
+	items := [][]byte{{1}, {2}, {3}}
+
+	assert.Equal(t, empty[:], inclusion.MerkleRoot(nil))
+	assert.Equal(t, leaf(items[0]), inclusion.MerkleRoot(items[:1]))
+	assert.Equal(t, inner(leaf(items[0]), leaf(items[1])), inclusion.MerkleRoot(items[:2]))
+	assert.Equal(t, inner(inner(leaf(items[0]), leaf(items[1])), leaf(items[2])), inclusion.MerkleRoot(items))
+}
+
+func TestBatchCommitment(t *testing.T) {
+	batch := share.NewBlobBatch(share.RandomBlobNamespace())
+	for i := 0; i < 10; i++ {
+		require.NoError(t, batch.Add(bytes.Repeat([]byte{byte(i)}, 100)))
+	}
+	blob, err := batch.Blob()
+	require.NoError(t, err)
+
+	expected, err := inclusion.CreateCommitment(blob, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	commitment, err := inclusion.BatchCommitment(batch, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expected, commitment)
+
+	_, err = inclusion.BatchCommitment(share.NewBlobBatch(share.RandomBlobNamespace()), inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+}
+
+func TestVerifyCommitment(t *testing.T) {
+	blob, err := share.NewV0Blob(share.RandomBlobNamespace(), bytes.Repeat([]byte{0xFF}, 3*share.ContinuationSparseShareContentSize))
+	require.NoError(t, err)
+	commitment, err := inclusion.CreateCommitment(blob, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	ok, err := inclusion.VerifyCommitment(blob, commitment, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	tampered := bytes.Clone(commitment)
+	tampered[0] ^= 0xFF
+	ok, err = inclusion.VerifyCommitment(blob, tampered, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = inclusion.VerifyCommitment(blob, nil, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCreateCommitmentWithHasher(t *testing.T) {
+	blob, err := share.NewV0Blob(share.RandomBlobNamespace(), bytes.Repeat([]byte{0xFF}, 3*share.ContinuationSparseShareContentSize))
+	require.NoError(t, err)
+
+	defaultRoots, err := inclusion.GenerateSubtreeRoots(blob, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	sha256Roots, err := inclusion.GenerateSubtreeRoots(blob, defaultSubtreeRootThreshold, inclusion.WithHasher(sha256.New))
+	require.NoError(t, err)
+	assert.Equal(t, defaultRoots, sha256Roots)
+
+	sha512Roots, err := inclusion.GenerateSubtreeRoots(blob, defaultSubtreeRootThreshold, inclusion.WithHasher(sha512.New))
+	require.NoError(t, err)
+	require.Len(t, sha512Roots, len(defaultRoots))
+	for _, root := range sha512Roots {
+		assert.Len(t, root, 2*share.NamespaceSize+sha512.Size)
+	}
+
+	commitment, err := inclusion.CreateCommitment(blob, inclusion.MerkleRoot, defaultSubtreeRootThreshold, inclusion.WithHasher(sha512.New))
+	require.NoError(t, err)
+	ok, err := inclusion.VerifyCommitment(blob, commitment, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = inclusion.VerifyCommitment(blob, commitment, inclusion.MerkleRoot, defaultSubtreeRootThreshold, inclusion.WithHasher(sha512.New))
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
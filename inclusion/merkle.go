@@ -0,0 +1,37 @@
+package inclusion
+
+import (
+	"crypto/sha256"
+	"math/bits"
+)
+
+var (
+	leafPrefix  = []byte{0}
+	innerPrefix = []byte{1}
+)
+
+// MerkleRoot is a MerkleRootFn computing the RFC 6962 merkle root of items
+// using SHA-256. It matches the merkle tree used by CometBFT to compute share
+// commitments.
+func MerkleRoot(items [][]byte) []byte {
+	switch len(items) {
+	case 0:
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	case 1:
+		return hashWithPrefix(leafPrefix, items[0])
+	default:
+		// split at the largest power of two smaller than the number of items
+		k := 1 << (bits.Len(uint(len(items)-1)) - 1)
+		left := MerkleRoot(items[:k])
+		right := MerkleRoot(items[k:])
+		return hashWithPrefix(innerPrefix, append(left, right...))
+	}
+}
+
+func hashWithPrefix(prefix, data []byte) []byte {
+	h := sha256.New()
+	h.Write(prefix)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,101 @@
+// Package layout contains the arithmetic of the blob share commitment rules
+// that determine where blobs are placed in the data square. It only depends on
+// the standard library and squaremath and uses integer arithmetic exclusively so that it
+// compiles under tinygo and WebAssembly and produces identical results on
+// every platform.
+//
+// See https://github.com/celestiaorg/celestia-app/blob/main/specs/src/specs/data_square_layout.md
+// for more information.
+package layout
+
+import "github.com/celestiaorg/go-square/v2/squaremath"
+
+// Integer is a constraint that permits any integer type.
+type Integer = squaremath.Integer
+
+// NextShareIndex determines the next index in a square that can be used. It
+// follows the blob share commitment rules defined in ADR-013. Assumes that all
+// args are non negative, that squareSize is a power of two and that the blob can
+// fit in the square. The cursor is expected to be the index after the end of
+// the previous blob.
+func NextShareIndex(cursor, blobShareLen, subtreeRootThreshold int) int {
+	return RoundUpByMultipleOf(cursor, SubTreeWidth(blobShareLen, subtreeRootThreshold))
+}
+
+// BlobSharesUsed returns the number of shares used by blobs with the given
+// share lengths starting at cursor and the share index of each blob.
+func BlobSharesUsed(cursor, subtreeRootThreshold int, blobShareLens ...int) (sharesUsed int, indexes []uint32) {
+	start := cursor
+	indexes = make([]uint32, len(blobShareLens))
+	for i, blobLen := range blobShareLens {
+		cursor = NextShareIndex(cursor, blobLen, subtreeRootThreshold)
+		indexes[i] = uint32(cursor)
+		cursor += blobLen
+	}
+	return cursor - start, indexes
+}
+
+// SubTreeWidth returns the maximum number of leaves per subtree in the share
+// commitment over a given blob. The input should be the total number of shares
+// used by that blob. See ADR-013.
+func SubTreeWidth(shareCount, subtreeRootThreshold int) int {
+	// round up if the width is not an exact multiple of the threshold
+	s := shareCount / subtreeRootThreshold
+	if shareCount%subtreeRootThreshold != 0 {
+		s++
+	}
+	// use the minimum of the power of two subtree width and the min square
+	// size, this guarantees that a valid value is returned
+	s = RoundUpPowerOfTwo(s)
+	if minSquareSize := BlobMinSquareSize(shareCount); minSquareSize < s {
+		return minSquareSize
+	}
+	return s
+}
+
+// MaxPadding returns the worst-case number of padding shares needed to align
+// a blob of numShares to the blob share commitment rules.
+func MaxPadding(numShares, subtreeRootThreshold int) int {
+	return SubTreeWidth(numShares, subtreeRootThreshold) - 1
+}
+
+// BlobMinSquareSize returns the minimum square size that can contain shareCount
+// number of shares.
+func BlobMinSquareSize(shareCount int) int {
+	size := 1
+	for size*size < shareCount {
+		size <<= 1
+	}
+	return size
+}
+
+// RoundUpByMultipleOf rounds cursor up to the next multiple of v. If cursor is divisible
+// by v, then it returns cursor. It panics if v is not positive or the result
+// overflows. Use squaremath.RoundUpByMultipleOf to handle these as errors.
+func RoundUpByMultipleOf(cursor, v int) int {
+	return must(squaremath.RoundUpByMultipleOf(cursor, v))
+}
+
+// RoundUpPowerOfTwo returns the next power of two greater than or equal to input.
+// It panics if the result overflows I. Use squaremath.RoundUpPowerOfTwo to
+// handle overflows as errors.
+func RoundUpPowerOfTwo[I Integer](input I) I {
+	return must(squaremath.RoundUpPowerOfTwo(input))
+}
+
+// RoundDownPowerOfTwo returns the next power of two less than or equal to input.
+func RoundDownPowerOfTwo[I Integer](input I) (I, error) {
+	return squaremath.RoundDownPowerOfTwo(input)
+}
+
+// IsPowerOfTwo returns true if input is a power of two.
+func IsPowerOfTwo[I Integer](input I) bool {
+	return squaremath.IsPowerOfTwo(input)
+}
+
+func must[I Integer](result I, err error) I {
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package layout_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/layout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobMinSquareSize(t *testing.T) {
+	for shareCount := 0; shareCount < 10_000; shareCount++ {
+		expected := layout.RoundUpPowerOfTwo(int(math.Ceil(math.Sqrt(float64(shareCount)))))
+		require.Equal(t, expected, layout.BlobMinSquareSize(shareCount), shareCount)
+	}
+}
+
+func TestSubTreeWidth(t *testing.T) {
+	tests := []struct {
+		shareCount, threshold, expected int
+	}{
+		{1, 64, 1},
+		{64, 64, 1},
+		{65, 64, 2},
+		{128, 64, 2},
+		{129, 64, 4},
+		{5, 1, 4},
+		{17, 1, 8},
+		{11000, 64, 128},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, layout.SubTreeWidth(tt.shareCount, tt.threshold), tt)
+		assert.Equal(t, tt.expected-1, layout.MaxPadding(tt.shareCount, tt.threshold), tt)
+	}
+}
+
+func TestBlobSharesUsed(t *testing.T) {
+	sharesUsed, indexes := layout.BlobSharesUsed(3, 1, 4, 1)
+	assert.Equal(t, []uint32{4, 8}, indexes)
+	assert.Equal(t, 6, sharesUsed)
+	assert.Equal(t, 16, layout.NextShareIndex(13, 16, 1))
+}
+
+func TestPowerOfTwo(t *testing.T) {
+	assert.Equal(t, uint8(8), layout.RoundUpPowerOfTwo(uint8(5)))
+	rounded, err := layout.RoundDownPowerOfTwo(int64(5))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), rounded)
+	_, err = layout.RoundDownPowerOfTwo(0)
+	require.Error(t, err)
+	assert.True(t, layout.IsPowerOfTwo(uint32(64)))
+	assert.False(t, layout.IsPowerOfTwo(0))
+	assert.False(t, layout.IsPowerOfTwo(6))
+}
@@ -0,0 +1,134 @@
+package square
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+// NamespaceInfo describes the shares of a single namespace in a square.
+type NamespaceInfo struct {
+	Namespace share.Namespace
+	// Range is the range of shares of the namespace, including any namespace
+	// padding following its blobs.
+	Range share.Range
+	// Blobs is the number of blobs in the namespace. It is zero for reserved
+	// namespaces.
+	Blobs int
+}
+
+// Namespaces returns each distinct namespace in the square in the order they
+// appear along with the range of shares they occupy and their number of
+// blobs.
+func Namespaces(s Square) []NamespaceInfo {
+	var infos []NamespaceInfo
+	for i, sh := range s {
+		ns := sh.Namespace()
+		last := len(infos) - 1
+		if last < 0 || !infos[last].Namespace.Equals(ns) {
+			infos = append(infos, NamespaceInfo{
+				Namespace: ns,
+				Range:     share.NewRange(i, i),
+			})
+			last++
+		}
+		infos[last].Range.End++
+		if !ns.IsReserved() && sh.IsSequenceStart() && !sh.IsPadding() {
+			infos[last].Blobs++
+		}
+	}
+	return infos
+}
+
+// NamespaceFilterResult holds the shares of a single namespace in a square
+// along with the range they occupy. The range is what a namespace inclusion
+// proof over the square would need to cover.
+type NamespaceFilterResult struct {
+	Namespace share.Namespace
+	// Range is the range of shares of the namespace, including any namespace
+	// padding following its blobs.
+	Range  share.Range
+	Shares []share.Share
+}
+
+// FilterByNamespaces returns the shares of each of the provided namespaces in
+// a single pass over the square. The result is keyed by the hex encoded
+// namespace (see share.Namespace.String). Namespaces that are not present in
+// the square are omitted.
+func FilterByNamespaces(s Square, nss []share.Namespace) map[string]NamespaceFilterResult {
+	wanted := make(map[string]struct{}, len(nss))
+	for _, ns := range nss {
+		wanted[string(ns.Bytes())] = struct{}{}
+	}
+	filtered := make(map[string]NamespaceFilterResult, len(nss))
+	for i, sh := range s {
+		ns := sh.Namespace()
+		if _, ok := wanted[string(ns.Bytes())]; !ok {
+			continue
+		}
+		key := ns.String()
+		found, ok := filtered[key]
+		if !ok {
+			found = NamespaceFilterResult{Namespace: ns, Range: share.NewRange(i, i)}
+		}
+		// shares of a namespace are contiguous as the square is sorted
+		found.Range.End = i + 1
+		found.Shares = append(found.Shares, sh)
+		filtered[key] = found
+	}
+	return filtered
+}
+
+// ExtractNamespaceBlobData returns the reassembled data of each blob of the
+// namespace in the square, in the order the blobs appear. Namespace padding
+// shares are skipped. It returns nil if the namespace is not present in the
+// square and an error if the namespace is reserved.
+func ExtractNamespaceBlobData(s Square, ns share.Namespace) ([][]byte, error) {
+	if ns.IsReserved() {
+		return nil, fmt.Errorf("namespace %s is reserved and does not contain blobs", ns)
+	}
+	r := share.GetShareRangeForNamespace(s, ns)
+	if r.IsEmpty() {
+		return nil, nil
+	}
+	blobs, err := share.ParseBlobs(s[r.Start:r.End], share.AllowedNamespaceVersions(ns.Version()))
+	if err != nil {
+		return nil, fmt.Errorf("parsing blobs of namespace %s: %w", ns, err)
+	}
+	data := make([][]byte, len(blobs))
+	for i, blob := range blobs {
+		data[i] = blob.Data()
+	}
+	return data, nil
+}
+
+// BlobShareRangeByCommitment returns the range of shares of the blob in the
+// namespace whose share commitment matches commitment. The range is end
+// exclusive and relative to the start of the square. The commitments are
+// recomputed for each blob of the namespace using inclusion.MerkleRoot. An
+// error matching ErrBlobNotFound is returned if no blob matches.
+func BlobShareRangeByCommitment(s Square, ns share.Namespace, commitment []byte, subtreeRootThreshold int) (share.Range, error) {
+	if ns.IsReserved() {
+		return share.Range{}, fmt.Errorf("namespace %s is reserved and does not contain blobs", ns)
+	}
+	r := share.GetShareRangeForNamespace(s, ns)
+	if r.IsEmpty() {
+		return share.Range{}, fmt.Errorf("%w: namespace %s is not present in the square", ErrBlobNotFound, ns)
+	}
+	blobs, ranges, err := share.ParseBlobsWithRanges(s[r.Start:r.End], share.AllowedNamespaceVersions(ns.Version()))
+	if err != nil {
+		return share.Range{}, fmt.Errorf("parsing blobs of namespace %s: %w", ns, err)
+	}
+	for i, blob := range blobs {
+		ok, err := inclusion.VerifyCommitment(blob, commitment, inclusion.MerkleRoot, subtreeRootThreshold)
+		if err != nil {
+			return share.Range{}, err
+		}
+		if ok {
+			ranges[i].Add(r.Start)
+			return ranges[i], nil
+		}
+	}
+	return share.Range{}, fmt.Errorf("%w: no blob in namespace %s matches commitment %X", ErrBlobNotFound, ns, commitment)
+}
@@ -0,0 +1,147 @@
+package square_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaces(t *testing.T) {
+	require.Equal(t, []square.NamespaceInfo{{
+		Namespace: share.TailPaddingNamespace,
+		Range:     share.NewRange(0, 1),
+	}}, square.Namespaces(square.EmptySquare()))
+
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	txs := test.GenerateTxs(250, 250, 2)
+	txs = append(txs, generateBlobTxsWithNamespaces(
+		[]share.Namespace{ns2, ns1, ns2},
+		[][]int{{100}, {2000, 300}},
+	)...)
+	s, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	infos := square.Namespaces(s)
+	expectedNamespaces := []share.Namespace{share.TxNamespace, share.PayForBlobNamespace, ns1, ns2, share.TailPaddingNamespace}
+	if square.Stats(s).ReservedPaddingShares > 0 {
+		expectedNamespaces = append(expectedNamespaces[:2], append([]share.Namespace{share.PrimaryReservedPaddingNamespace}, expectedNamespaces[2:]...)...)
+	}
+	require.Len(t, infos, len(expectedNamespaces))
+	end := 0
+	for i, info := range infos {
+		require.Equal(t, expectedNamespaces[i], info.Namespace)
+		require.Equal(t, end, info.Range.Start)
+		require.Equal(t, share.GetShareRangeForNamespace(s, info.Namespace), info.Range)
+		end = info.Range.End
+		switch {
+		case info.Namespace.Equals(ns1):
+			require.Equal(t, 1, info.Blobs)
+		case info.Namespace.Equals(ns2):
+			require.Equal(t, 2, info.Blobs)
+		default:
+			require.Equal(t, 0, info.Blobs)
+		}
+	}
+	require.Equal(t, len(s), end)
+}
+
+func TestFilterByNamespaces(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	missing := share.MustNewV0Namespace(bytes.Repeat([]byte{3}, share.NamespaceVersionZeroIDSize))
+	txs := test.GenerateTxs(250, 250, 2)
+	txs = append(txs, generateBlobTxsWithNamespaces(
+		[]share.Namespace{ns2, ns1, ns2},
+		[][]int{{100}, {2000, 300}},
+	)...)
+	s, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	filtered := square.FilterByNamespaces(s, []share.Namespace{ns1, ns2, share.TxNamespace, missing})
+	require.Len(t, filtered, 3)
+	for _, ns := range []share.Namespace{ns1, ns2, share.TxNamespace} {
+		found, ok := filtered[ns.String()]
+		require.True(t, ok)
+		require.Equal(t, ns, found.Namespace)
+		expectedRange := share.GetShareRangeForNamespace(s, ns)
+		require.Equal(t, expectedRange, found.Range)
+		require.Equal(t, []share.Share(s[expectedRange.Start:expectedRange.End]), found.Shares)
+	}
+	_, ok := filtered[missing.String()]
+	require.False(t, ok)
+
+	require.Empty(t, square.FilterByNamespaces(s, nil))
+}
+
+func TestExtractNamespaceBlobData(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	missing := share.MustNewV0Namespace(bytes.Repeat([]byte{3}, share.NamespaceVersionZeroIDSize))
+	blobTxs := generateBlobTxsWithNamespaces(
+		[]share.Namespace{ns2, ns1, ns2},
+		[][]int{{100}, {2000, 300}},
+	)
+	s, err := square.Construct(blobTxs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	expected := make(map[string][][]byte)
+	for _, txBytes := range blobTxs {
+		blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
+		require.NoError(t, err)
+		require.True(t, isBlobTx)
+		for _, blob := range blobTx.Blobs {
+			key := blob.Namespace().String()
+			expected[key] = append(expected[key], blob.Data())
+		}
+	}
+
+	for _, ns := range []share.Namespace{ns1, ns2} {
+		data, err := square.ExtractNamespaceBlobData(s, ns)
+		require.NoError(t, err)
+		require.Equal(t, expected[ns.String()], data)
+	}
+
+	data, err := square.ExtractNamespaceBlobData(s, missing)
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	_, err = square.ExtractNamespaceBlobData(s, share.PayForBlobNamespace)
+	require.Error(t, err)
+}
+
+func TestBlobShareRangeByCommitment(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	txs := generateBlobTxsWithNamespaces([]share.Namespace{ns2, ns1, ns2}, [][]int{{100}, {2000, 300}})
+	s, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	for pfbIndex, txBytes := range txs {
+		blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
+		require.NoError(t, err)
+		require.True(t, isBlobTx)
+		for blobIndex, blob := range blobTx.Blobs {
+			commitment, err := inclusion.CreateCommitment(blob, inclusion.MerkleRoot, defaultSubtreeRootThreshold)
+			require.NoError(t, err)
+			got, err := square.BlobShareRangeByCommitment(s, blob.Namespace(), commitment, defaultSubtreeRootThreshold)
+			require.NoError(t, err)
+			expected, err := square.BlobShareRange(txs, pfbIndex, blobIndex, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+			require.NoError(t, err)
+			require.Equal(t, expected, got)
+		}
+	}
+
+	_, err = square.BlobShareRangeByCommitment(s, ns1, bytes.Repeat([]byte{1}, 32), defaultSubtreeRootThreshold)
+	require.ErrorIs(t, err, square.ErrBlobNotFound)
+	_, err = square.BlobShareRangeByCommitment(s, share.RandomBlobNamespace(), bytes.Repeat([]byte{1}, 32), defaultSubtreeRootThreshold)
+	require.ErrorIs(t, err, square.ErrBlobNotFound)
+	_, err = square.BlobShareRangeByCommitment(s, share.TxNamespace, bytes.Repeat([]byte{1}, 32), defaultSubtreeRootThreshold)
+	require.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package square
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+// PaddingKind is the type of padding share.
+type PaddingKind uint8
+
+const (
+	// NamespacePadding follows a blob so that the next blob starts at an index
+	// conforming to the blob share commitment rules.
+	NamespacePadding PaddingKind = iota + 1
+	// ReservedPadding follows the reserved namespaces so that the first blob
+	// starts at an index conforming to the blob share commitment rules.
+	ReservedPadding
+	// TailPadding follows the last blob to fill up the square.
+	TailPadding
+)
+
+func (k PaddingKind) String() string {
+	switch k {
+	case NamespacePadding:
+		return "namespace padding"
+	case ReservedPadding:
+		return "reserved padding"
+	case TailPadding:
+		return "tail padding"
+	default:
+		return "unknown padding"
+	}
+}
+
+// PaddingRun is a contiguous run of padding shares of the same kind and
+// namespace.
+type PaddingRun struct {
+	Kind      PaddingKind
+	Namespace share.Namespace
+	Range     share.Range
+}
+
+// PaddingSummary lists all padding in a square.
+type PaddingSummary struct {
+	Runs []PaddingRun
+	// Shares is the total number of padding shares per kind.
+	Shares map[PaddingKind]int
+	// BytesLost is the number of bytes occupied by padding shares.
+	BytesLost int
+}
+
+// PaddingReport returns every run of padding shares in the square along with
+// a summary of the space lost to padding.
+func PaddingReport(s Square) PaddingSummary {
+	summary := PaddingSummary{Shares: make(map[PaddingKind]int)}
+	for i, sh := range s {
+		if !sh.IsPadding() {
+			continue
+		}
+		ns := sh.Namespace()
+		kind := NamespacePadding
+		switch {
+		case ns.IsPrimaryReservedPadding():
+			kind = ReservedPadding
+		case ns.IsTailPadding():
+			kind = TailPadding
+		}
+		summary.Shares[kind]++
+		summary.BytesLost += share.ShareSize
+
+		last := len(summary.Runs) - 1
+		if last >= 0 {
+			run := &summary.Runs[last]
+			if run.Kind == kind && run.Range.End == i && bytes.Equal(run.Namespace.Bytes(), ns.Bytes()) {
+				run.Range.End++
+				continue
+			}
+		}
+		summary.Runs = append(summary.Runs, PaddingRun{
+			Kind:      kind,
+			Namespace: ns,
+			Range:     share.NewRange(i, i+1),
+		})
+	}
+	return summary
+}
+
+// PaddingEstimate returns the worst-case number of padding shares needed to
+// align the blobs currently in the builder to the blob share commitment rules.
+// It does not include tail padding, which depends on the final square size.
+func (b *Builder) PaddingEstimate() int {
+	padding := 0
+	for _, element := range b.Blobs {
+		padding += element.MaxPadding
+	}
+	return padding
+}
+
+// TailPaddingCount returns the number of tail padding shares at the end of
+// the square.
+func TailPaddingCount(s Square) int {
+	tailPadding := share.TailPaddingShare()
+	count := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		if !bytes.Equal(s[i].ToBytes(), tailPadding.ToBytes()) {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// TrimTailPadding returns the shares of the square without the tail padding
+// at its end. Tail padding is deterministic so storage layers can persist the
+// trimmed shares along with the square size and regenerate the square using
+// RestoreTailPadding.
+func TrimTailPadding(s Square) []share.Share {
+	return s[:len(s)-TailPaddingCount(s)]
+}
+
+// RestoreTailPadding returns the square of the given size consisting of the
+// provided shares followed by tail padding. It reverses TrimTailPadding.
+func RestoreTailPadding(shares []share.Share, squareSize int) (Square, error) {
+	if squareSize <= 0 {
+		return nil, fmt.Errorf("square size %d must be strictly positive", squareSize)
+	}
+	totalShares := squareSize * squareSize
+	if len(shares) > totalShares {
+		return nil, fmt.Errorf("%d shares do not fit in a square of size %d", len(shares), squareSize)
+	}
+	s := make(Square, 0, totalShares)
+	s = append(s, shares...)
+	return append(s, share.TailPaddingShares(totalShares-len(shares))...), nil
+}
@@ -0,0 +1,78 @@
+package square_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaddingReport(t *testing.T) {
+	summary := square.PaddingReport(square.EmptySquare())
+	require.Equal(t, []square.PaddingRun{{
+		Kind:      square.TailPadding,
+		Namespace: share.TailPaddingNamespace,
+		Range:     share.NewRange(0, 1),
+	}}, summary.Runs)
+	require.Equal(t, share.ShareSize, summary.BytesLost)
+
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	txs := test.GenerateTxs(250, 250, 4)
+	txs = append(txs, generateBlobTxsWithNamespaces(
+		[]share.Namespace{ns1, ns2},
+		[][]int{{100}, {share.ContinuationSparseShareContentSize * 8}},
+	)...)
+	// a low threshold forces the 9 share blob to start at a multiple of 4
+	builder, err := square.NewBuilder(defaultMaxSquareSize, 1, txs...)
+	require.NoError(t, err)
+	estimate := builder.PaddingEstimate()
+	s, err := builder.Export()
+	require.NoError(t, err)
+
+	stats := square.Stats(s)
+	summary = square.PaddingReport(s)
+	require.Equal(t, stats.ReservedPaddingShares, summary.Shares[square.ReservedPadding])
+	require.Equal(t, stats.NamespacePaddingShares, summary.Shares[square.NamespacePadding])
+	require.Equal(t, stats.TailPaddingShares, summary.Shares[square.TailPadding])
+	require.Equal(t, stats.PaddingShares()*share.ShareSize, summary.BytesLost)
+	require.LessOrEqual(t, stats.ReservedPaddingShares+stats.NamespacePaddingShares, estimate)
+
+	require.Greater(t, summary.Shares[square.NamespacePadding], 0)
+	for _, run := range summary.Runs {
+		for i := run.Range.Start; i < run.Range.End; i++ {
+			require.True(t, s[i].IsPadding())
+			require.Equal(t, run.Namespace, s[i].Namespace())
+		}
+		if run.Kind == square.NamespacePadding {
+			require.Equal(t, ns1, run.Namespace)
+		}
+	}
+}
+
+func TestTrimTailPadding(t *testing.T) {
+	require.Equal(t, 1, square.TailPaddingCount(square.EmptySquare()))
+	require.Empty(t, square.TrimTailPadding(square.EmptySquare()))
+
+	s, err := square.Construct(generateOrderedTxs(10, 3, 1, 1000), defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	tailPadding := square.PaddingReport(s).Shares[square.TailPadding]
+	require.Greater(t, tailPadding, 0)
+	require.Equal(t, tailPadding, square.TailPaddingCount(s))
+
+	trimmed := square.TrimTailPadding(s)
+	require.Len(t, trimmed, len(s)-tailPadding)
+	require.False(t, trimmed[len(trimmed)-1].Namespace().IsTailPadding())
+
+	restored, err := square.RestoreTailPadding(trimmed, s.Size())
+	require.NoError(t, err)
+	require.Equal(t, s, restored)
+
+	_, err = square.RestoreTailPadding(trimmed, 1)
+	require.Error(t, err)
+	_, err = square.RestoreTailPadding(nil, 0)
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package square
+
+// BuilderPool is a pool of builders sharing the same configuration. Proposers
+// that build a candidate square on every mempool change can use it to reuse
+// the internal slices of builders instead of reallocating them. It is safe
+// for concurrent use.
+type BuilderPool struct {
+	maxSquareSize        int
+	subtreeRootThreshold int
+	opts                 []BuilderOption
+	builders             chan *Builder
+}
+
+// NewBuilderPool returns a pool holding up to n pre-allocated builders
+// configured with the provided options.
+func NewBuilderPool(maxSquareSize, subtreeRootThreshold, n int, opts ...BuilderOption) (*BuilderPool, error) {
+	p := &BuilderPool{
+		maxSquareSize:        maxSquareSize,
+		subtreeRootThreshold: subtreeRootThreshold,
+		opts:                 opts,
+		builders:             make(chan *Builder, max(n, 0)),
+	}
+	// always construct one builder to validate the configuration
+	builder, err := p.newBuilder()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			if builder, err = p.newBuilder(); err != nil {
+				return nil, err
+			}
+		}
+		p.builders <- builder
+	}
+	return p, nil
+}
+
+// Get returns an empty builder from the pool. If the pool is empty, a new
+// builder is allocated.
+func (p *BuilderPool) Get() *Builder {
+	select {
+	case builder := <-p.builders:
+		return builder
+	default:
+		// the configuration was validated by NewBuilderPool
+		builder, err := p.newBuilder()
+		if err != nil {
+			panic(err)
+		}
+		return builder
+	}
+}
+
+// Put resets the builder and returns it to the pool. The builder must have
+// been obtained from Get and must not be used afterwards. If the pool is
+// full, the builder is dropped.
+func (p *BuilderPool) Put(builder *Builder) {
+	if builder == nil {
+		return
+	}
+	builder.Reset()
+	select {
+	case p.builders <- builder:
+	default:
+	}
+}
+
+func (p *BuilderPool) newBuilder() (*Builder, error) {
+	return NewBuilderWithOptions(p.maxSquareSize, p.subtreeRootThreshold, p.opts...)
+}
@@ -0,0 +1,45 @@
+package square_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderPool(t *testing.T) {
+	pool, err := square.NewBuilderPool(defaultMaxSquareSize, defaultSubtreeRootThreshold, 2)
+	require.NoError(t, err)
+
+	txs := generateOrderedTxs(10, 10, 2, 1000)
+	expected, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		builder := pool.Get()
+		require.True(t, builder.IsEmpty())
+		require.Zero(t, builder.CurrentSize())
+		for _, txBytes := range txs {
+			blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
+			if isBlobTx {
+				require.NoError(t, err)
+				require.True(t, builder.AppendBlobTx(blobTx))
+			} else {
+				require.True(t, builder.AppendTx(txBytes))
+			}
+		}
+		dataSquare, err := builder.Export()
+		require.NoError(t, err)
+		require.Equal(t, expected, dataSquare)
+		pool.Put(builder)
+	}
+
+	// the pool allocates new builders once it is drained
+	for i := 0; i < 3; i++ {
+		require.NotNil(t, pool.Get())
+	}
+
+	_, err = square.NewBuilderPool(3, defaultSubtreeRootThreshold, 0)
+	require.ErrorIs(t, err, square.ErrInvalidMaxSquareSize)
+}
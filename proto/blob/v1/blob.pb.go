@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.34.2
+// 	protoc-gen-go v1.35.1
 // 	protoc        (unknown)
 // source: proto/blob/v1/blob.proto
 
@@ -36,15 +36,20 @@ type BlobProto struct {
 	// Signer is sdk.AccAddress that paid for this blob. This field is optional
 	// and can only be used when share_version is set to 1.
 	Signer []byte `protobuf:"bytes,5,opt,name=signer,proto3" json:"signer,omitempty"`
+	// Metadata is a small user-defined header that is encoded alongside the
+	// data. This field is optional and can only be used when share_version is
+	// set to 3.
+	Metadata []byte `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Codec is the id of the codec used to compress the data. This field is
+	// optional and can only be used when share_version is set to 4.
+	Codec uint32 `protobuf:"varint,7,opt,name=codec,proto3" json:"codec,omitempty"`
 }
 
 func (x *BlobProto) Reset() {
 	*x = BlobProto{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blob_v1_blob_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_blob_v1_blob_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *BlobProto) String() string {
@@ -55,7 +60,7 @@ func (*BlobProto) ProtoMessage() {}
 
 func (x *BlobProto) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_blob_v1_blob_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -105,6 +110,20 @@ func (x *BlobProto) GetSigner() []byte {
 	return nil
 }
 
+func (x *BlobProto) GetMetadata() []byte {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *BlobProto) GetCodec() uint32 {
+	if x != nil {
+		return x.Codec
+	}
+	return 0
+}
+
 // BlobTx wraps an encoded sdk.Tx with a second field to contain blobs of data.
 // The raw bytes of the blobs are not signed over, instead we verify each blob
 // using the relevant MsgPayForBlobs that is signed over in the encoded sdk.Tx.
@@ -116,15 +135,16 @@ type BlobTx struct {
 	Tx     []byte       `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
 	Blobs  []*BlobProto `protobuf:"bytes,2,rep,name=blobs,proto3" json:"blobs,omitempty"`
 	TypeId string       `protobuf:"bytes,3,opt,name=type_id,json=typeId,proto3" json:"type_id,omitempty"`
+	// HeightHint is the optional height hint that is copied to the
+	// IndexWrapper of the PFB when the BlobTx is included in a square.
+	HeightHint uint64 `protobuf:"varint,4,opt,name=height_hint,json=heightHint,proto3" json:"height_hint,omitempty"`
 }
 
 func (x *BlobTx) Reset() {
 	*x = BlobTx{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blob_v1_blob_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_blob_v1_blob_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *BlobTx) String() string {
@@ -135,7 +155,7 @@ func (*BlobTx) ProtoMessage() {}
 
 func (x *BlobTx) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_blob_v1_blob_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -171,6 +191,13 @@ func (x *BlobTx) GetTypeId() string {
 	return ""
 }
 
+func (x *BlobTx) GetHeightHint() uint64 {
+	if x != nil {
+		return x.HeightHint
+	}
+	return 0
+}
+
 // IndexWrapper adds index metadata to a transaction. This is used to track
 // transactions that pay for blobs, and where the blobs start in the square.
 type IndexWrapper struct {
@@ -181,15 +208,17 @@ type IndexWrapper struct {
 	Tx           []byte   `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
 	ShareIndexes []uint32 `protobuf:"varint,2,rep,packed,name=share_indexes,json=shareIndexes,proto3" json:"share_indexes,omitempty"`
 	TypeId       string   `protobuf:"bytes,3,opt,name=type_id,json=typeId,proto3" json:"type_id,omitempty"`
+	// HeightHint is an optional block height attached to the PFB, for example
+	// the height after which its blobs may be pruned. Zero means no hint, in
+	// which case the wrapper is encoded exactly as before.
+	HeightHint uint64 `protobuf:"varint,4,opt,name=height_hint,json=heightHint,proto3" json:"height_hint,omitempty"`
 }
 
 func (x *IndexWrapper) Reset() {
 	*x = IndexWrapper{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blob_v1_blob_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_blob_v1_blob_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *IndexWrapper) String() string {
@@ -200,7 +229,7 @@ func (*IndexWrapper) ProtoMessage() {}
 
 func (x *IndexWrapper) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_blob_v1_blob_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -236,12 +265,167 @@ func (x *IndexWrapper) GetTypeId() string {
 	return ""
 }
 
+func (x *IndexWrapper) GetHeightHint() uint64 {
+	if x != nil {
+		return x.HeightHint
+	}
+	return 0
+}
+
+// FibreTx wraps an encoded sdk.Tx containing a MsgPayForFibre with the system
+// blob that it pays for. Unlike a BlobTx, the data referenced by a FibreTx is
+// not included in the square; only the system blob, which commits to that
+// data, is.
+type FibreTx struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tx         []byte     `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	SystemBlob *BlobProto `protobuf:"bytes,2,opt,name=system_blob,json=systemBlob,proto3" json:"system_blob,omitempty"`
+	TypeId     string     `protobuf:"bytes,3,opt,name=type_id,json=typeId,proto3" json:"type_id,omitempty"`
+}
+
+func (x *FibreTx) Reset() {
+	*x = FibreTx{}
+	mi := &file_proto_blob_v1_blob_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FibreTx) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FibreTx) ProtoMessage() {}
+
+func (x *FibreTx) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blob_v1_blob_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FibreTx.ProtoReflect.Descriptor instead.
+func (*FibreTx) Descriptor() ([]byte, []int) {
+	return file_proto_blob_v1_blob_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FibreTx) GetTx() []byte {
+	if x != nil {
+		return x.Tx
+	}
+	return nil
+}
+
+func (x *FibreTx) GetSystemBlob() *BlobProto {
+	if x != nil {
+		return x.SystemBlob
+	}
+	return nil
+}
+
+func (x *FibreTx) GetTypeId() string {
+	if x != nil {
+		return x.TypeId
+	}
+	return ""
+}
+
+// FibreSystemBlob is an explicit representation of the system blob of a
+// FibreTx. On the wire the system blob is still a BlobProto whose data is the
+// big endian fibre_blob_version followed by the commitment; this message
+// exists so that implementations do not have to slice the data themselves.
+type FibreSystemBlob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NamespaceId      []byte `protobuf:"bytes,1,opt,name=namespace_id,json=namespaceId,proto3" json:"namespace_id,omitempty"`
+	NamespaceVersion uint32 `protobuf:"varint,2,opt,name=namespace_version,json=namespaceVersion,proto3" json:"namespace_version,omitempty"`
+	// Signer is sdk.AccAddress that paid for the fibre data.
+	Signer           []byte `protobuf:"bytes,3,opt,name=signer,proto3" json:"signer,omitempty"`
+	FibreBlobVersion uint32 `protobuf:"varint,4,opt,name=fibre_blob_version,json=fibreBlobVersion,proto3" json:"fibre_blob_version,omitempty"`
+	// Commitment is the 32 byte commitment to the fibre data.
+	Commitment []byte `protobuf:"bytes,5,opt,name=commitment,proto3" json:"commitment,omitempty"`
+}
+
+func (x *FibreSystemBlob) Reset() {
+	*x = FibreSystemBlob{}
+	mi := &file_proto_blob_v1_blob_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FibreSystemBlob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FibreSystemBlob) ProtoMessage() {}
+
+func (x *FibreSystemBlob) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blob_v1_blob_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FibreSystemBlob.ProtoReflect.Descriptor instead.
+func (*FibreSystemBlob) Descriptor() ([]byte, []int) {
+	return file_proto_blob_v1_blob_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FibreSystemBlob) GetNamespaceId() []byte {
+	if x != nil {
+		return x.NamespaceId
+	}
+	return nil
+}
+
+func (x *FibreSystemBlob) GetNamespaceVersion() uint32 {
+	if x != nil {
+		return x.NamespaceVersion
+	}
+	return 0
+}
+
+func (x *FibreSystemBlob) GetSigner() []byte {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *FibreSystemBlob) GetFibreBlobVersion() uint32 {
+	if x != nil {
+		return x.FibreBlobVersion
+	}
+	return 0
+}
+
+func (x *FibreSystemBlob) GetCommitment() []byte {
+	if x != nil {
+		return x.Commitment
+	}
+	return nil
+}
+
 var File_proto_blob_v1_blob_proto protoreflect.FileDescriptor
 
 var file_proto_blob_v1_blob_proto_rawDesc = []byte{
 	0x0a, 0x18, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x6c, 0x6f, 0x62, 0x2f, 0x76, 0x31, 0x2f,
 	0x62, 0x6c, 0x6f, 0x62, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x2e, 0x76, 0x31, 0x22, 0xac, 0x01, 0x0a, 0x09, 0x42, 0x6c,
+	0x6f, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x2e, 0x76, 0x31, 0x22, 0xde, 0x01, 0x0a, 0x09, 0x42, 0x6c,
 	0x6f, 0x62, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x61, 0x6d, 0x65, 0x73,
 	0x70, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x6e,
 	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
@@ -252,23 +436,50 @@ var file_proto_blob_v1_blob_proto_rawDesc = []byte{
 	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10,
 	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
 	0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x22, 0x61, 0x0a, 0x06, 0x42, 0x6c, 0x6f, 0x62,
-	0x54, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02,
-	0x74, 0x78, 0x12, 0x2e, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x2e, 0x76,
-	0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x05, 0x62, 0x6c, 0x6f,
-	0x62, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x79, 0x70, 0x65, 0x49, 0x64, 0x22, 0x5c, 0x0a, 0x0c, 0x49,
-	0x6e, 0x64, 0x65, 0x78, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x74,
-	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x74, 0x78, 0x12, 0x23, 0x0a, 0x0d, 0x73,
-	0x68, 0x61, 0x72, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x0d, 0x52, 0x0c, 0x73, 0x68, 0x61, 0x72, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73,
-	0x12, 0x17, 0x0a, 0x07, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x74, 0x79, 0x70, 0x65, 0x49, 0x64, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x65, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x61,
-	0x6f, 0x72, 0x67, 0x2f, 0x67, 0x6f, 0x2d, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x2f, 0x76, 0x32,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x6c, 0x6f, 0x62, 0x2f, 0x76, 0x31, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x22, 0x82, 0x01, 0x0a, 0x06, 0x42,
+	0x6c, 0x6f, 0x62, 0x54, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x02, 0x74, 0x78, 0x12, 0x2e, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x62, 0x6c, 0x6f,
+	0x62, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x05,
+	0x62, 0x6c, 0x6f, 0x62, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x79, 0x70, 0x65, 0x49, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x48, 0x69, 0x6e, 0x74, 0x22,
+	0x7d, 0x0a, 0x0c, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x12,
+	0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x74, 0x78, 0x12,
+	0x23, 0x0a, 0x0d, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x68, 0x61, 0x72, 0x65, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x65, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x79, 0x70, 0x65, 0x49, 0x64, 0x12, 0x1f, 0x0a,
+	0x0b, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x48, 0x69, 0x6e, 0x74, 0x22, 0x6d,
+	0x0a, 0x07, 0x46, 0x69, 0x62, 0x72, 0x65, 0x54, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x74, 0x78, 0x12, 0x39, 0x0a, 0x0b, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x6c, 0x6f, 0x62, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x0a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x42, 0x6c, 0x6f, 0x62, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x79, 0x70, 0x65, 0x49, 0x64, 0x22, 0xc7, 0x01,
+	0x0a, 0x0f, 0x46, 0x69, 0x62, 0x72, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x42, 0x6c, 0x6f,
+	0x62, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x2b, 0x0a, 0x11, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x10, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x12, 0x66, 0x69, 0x62,
+	0x72, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x66, 0x69, 0x62, 0x72, 0x65, 0x42, 0x6c, 0x6f, 0x62,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x65, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x61, 0x6f, 0x72,
+	0x67, 0x2f, 0x67, 0x6f, 0x2d, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x2f, 0x76, 0x32, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x6c, 0x6f, 0x62, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -283,19 +494,22 @@ func file_proto_blob_v1_blob_proto_rawDescGZIP() []byte {
 	return file_proto_blob_v1_blob_proto_rawDescData
 }
 
-var file_proto_blob_v1_blob_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_blob_v1_blob_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_proto_blob_v1_blob_proto_goTypes = []any{
-	(*BlobProto)(nil),    // 0: proto.blob.v1.BlobProto
-	(*BlobTx)(nil),       // 1: proto.blob.v1.BlobTx
-	(*IndexWrapper)(nil), // 2: proto.blob.v1.IndexWrapper
+	(*BlobProto)(nil),       // 0: proto.blob.v1.BlobProto
+	(*BlobTx)(nil),          // 1: proto.blob.v1.BlobTx
+	(*IndexWrapper)(nil),    // 2: proto.blob.v1.IndexWrapper
+	(*FibreTx)(nil),         // 3: proto.blob.v1.FibreTx
+	(*FibreSystemBlob)(nil), // 4: proto.blob.v1.FibreSystemBlob
 }
 var file_proto_blob_v1_blob_proto_depIdxs = []int32{
 	0, // 0: proto.blob.v1.BlobTx.blobs:type_name -> proto.blob.v1.BlobProto
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0, // 1: proto.blob.v1.FibreTx.system_blob:type_name -> proto.blob.v1.BlobProto
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_proto_blob_v1_blob_proto_init() }
@@ -303,51 +517,13 @@ func file_proto_blob_v1_blob_proto_init() {
 	if File_proto_blob_v1_blob_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_blob_v1_blob_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*BlobProto); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_blob_v1_blob_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*BlobTx); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_blob_v1_blob_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*IndexWrapper); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_blob_v1_blob_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// indexWrapperJSON is the canonical JSON representation of an IndexWrapper:
+//
+//	{
+//	  "tx":           base64url (unpadded) encoded transaction,
+//	  "shareIndexes": share indexes of the blobs,
+//	  "typeId":       type id,
+//	  "heightHint":   height hint, omitted if zero
+//	}
+//
+// The schema is independent of the generated protobuf code and must not
+// change.
+type indexWrapperJSON struct {
+	Tx           string   `json:"tx"`
+	ShareIndexes []uint32 `json:"shareIndexes"`
+	TypeID       string   `json:"typeId"`
+	HeightHint   uint64   `json:"heightHint,omitempty"`
+}
+
+// MarshalJSON encodes the index wrapper using its canonical JSON
+// representation.
+func (x *IndexWrapper) MarshalJSON() ([]byte, error) {
+	shareIndexes := x.ShareIndexes
+	if shareIndexes == nil {
+		shareIndexes = []uint32{}
+	}
+	return json.Marshal(indexWrapperJSON{
+		Tx:           base64.RawURLEncoding.EncodeToString(x.Tx),
+		ShareIndexes: shareIndexes,
+		TypeID:       x.TypeId,
+		HeightHint:   x.HeightHint,
+	})
+}
+
+// legacyIndexWrapperJSON is the representation used before the canonical
+// one, which was the default encoding of the generated struct.
+type legacyIndexWrapperJSON struct {
+	Tx           []byte   `json:"tx"`
+	ShareIndexes []uint32 `json:"share_indexes"`
+	TypeID       string   `json:"type_id"`
+}
+
+// UnmarshalJSON decodes an index wrapper from its canonical JSON
+// representation. The legacy representation, with "share_indexes" and
+// "type_id" fields and standard base64 encoding, is also accepted.
+func (x *IndexWrapper) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	_, hasLegacyIndexes := fields["share_indexes"]
+	_, hasLegacyTypeID := fields["type_id"]
+	if hasLegacyIndexes || hasLegacyTypeID {
+		var legacy legacyIndexWrapperJSON
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return err
+		}
+		x.Reset()
+		x.Tx = legacy.Tx
+		x.ShareIndexes = legacy.ShareIndexes
+		x.TypeId = legacy.TypeID
+		return nil
+	}
+	var iw indexWrapperJSON
+	if err := json.Unmarshal(data, &iw); err != nil {
+		return err
+	}
+	tx, err := base64.RawURLEncoding.DecodeString(iw.Tx)
+	if err != nil {
+		return fmt.Errorf("invalid base64url encoding of tx: %w", err)
+	}
+	x.Reset()
+	x.Tx = tx
+	if len(iw.ShareIndexes) > 0 {
+		x.ShareIndexes = iw.ShareIndexes
+	}
+	x.TypeId = iw.TypeID
+	x.HeightHint = iw.HeightHint
+	return nil
+}
@@ -0,0 +1,582 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        (unknown)
+// source: proto/square/v1/service.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ConstructRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Txs                  [][]byte `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	MaxSquareSize        uint32   `protobuf:"varint,2,opt,name=max_square_size,json=maxSquareSize,proto3" json:"max_square_size,omitempty"`
+	SubtreeRootThreshold uint32   `protobuf:"varint,3,opt,name=subtree_root_threshold,json=subtreeRootThreshold,proto3" json:"subtree_root_threshold,omitempty"`
+}
+
+func (x *ConstructRequest) Reset() {
+	*x = ConstructRequest{}
+	mi := &file_proto_square_v1_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConstructRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConstructRequest) ProtoMessage() {}
+
+func (x *ConstructRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_square_v1_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConstructRequest.ProtoReflect.Descriptor instead.
+func (*ConstructRequest) Descriptor() ([]byte, []int) {
+	return file_proto_square_v1_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ConstructRequest) GetTxs() [][]byte {
+	if x != nil {
+		return x.Txs
+	}
+	return nil
+}
+
+func (x *ConstructRequest) GetMaxSquareSize() uint32 {
+	if x != nil {
+		return x.MaxSquareSize
+	}
+	return 0
+}
+
+func (x *ConstructRequest) GetSubtreeRootThreshold() uint32 {
+	if x != nil {
+		return x.SubtreeRootThreshold
+	}
+	return 0
+}
+
+type ConstructResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Shares are the shares of the square in row major order.
+	Shares     [][]byte `protobuf:"bytes,1,rep,name=shares,proto3" json:"shares,omitempty"`
+	SquareSize uint32   `protobuf:"varint,2,opt,name=square_size,json=squareSize,proto3" json:"square_size,omitempty"`
+}
+
+func (x *ConstructResponse) Reset() {
+	*x = ConstructResponse{}
+	mi := &file_proto_square_v1_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConstructResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConstructResponse) ProtoMessage() {}
+
+func (x *ConstructResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_square_v1_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConstructResponse.ProtoReflect.Descriptor instead.
+func (*ConstructResponse) Descriptor() ([]byte, []int) {
+	return file_proto_square_v1_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConstructResponse) GetShares() [][]byte {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+func (x *ConstructResponse) GetSquareSize() uint32 {
+	if x != nil {
+		return x.SquareSize
+	}
+	return 0
+}
+
+type TxShareRangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Txs                  [][]byte `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	TxIndex              uint32   `protobuf:"varint,2,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	MaxSquareSize        uint32   `protobuf:"varint,3,opt,name=max_square_size,json=maxSquareSize,proto3" json:"max_square_size,omitempty"`
+	SubtreeRootThreshold uint32   `protobuf:"varint,4,opt,name=subtree_root_threshold,json=subtreeRootThreshold,proto3" json:"subtree_root_threshold,omitempty"`
+}
+
+func (x *TxShareRangeRequest) Reset() {
+	*x = TxShareRangeRequest{}
+	mi := &file_proto_square_v1_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxShareRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxShareRangeRequest) ProtoMessage() {}
+
+func (x *TxShareRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_square_v1_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxShareRangeRequest.ProtoReflect.Descriptor instead.
+func (*TxShareRangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_square_v1_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TxShareRangeRequest) GetTxs() [][]byte {
+	if x != nil {
+		return x.Txs
+	}
+	return nil
+}
+
+func (x *TxShareRangeRequest) GetTxIndex() uint32 {
+	if x != nil {
+		return x.TxIndex
+	}
+	return 0
+}
+
+func (x *TxShareRangeRequest) GetMaxSquareSize() uint32 {
+	if x != nil {
+		return x.MaxSquareSize
+	}
+	return 0
+}
+
+func (x *TxShareRangeRequest) GetSubtreeRootThreshold() uint32 {
+	if x != nil {
+		return x.SubtreeRootThreshold
+	}
+	return 0
+}
+
+type BlobShareRangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Txs                  [][]byte `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	TxIndex              uint32   `protobuf:"varint,2,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	BlobIndex            uint32   `protobuf:"varint,3,opt,name=blob_index,json=blobIndex,proto3" json:"blob_index,omitempty"`
+	MaxSquareSize        uint32   `protobuf:"varint,4,opt,name=max_square_size,json=maxSquareSize,proto3" json:"max_square_size,omitempty"`
+	SubtreeRootThreshold uint32   `protobuf:"varint,5,opt,name=subtree_root_threshold,json=subtreeRootThreshold,proto3" json:"subtree_root_threshold,omitempty"`
+}
+
+func (x *BlobShareRangeRequest) Reset() {
+	*x = BlobShareRangeRequest{}
+	mi := &file_proto_square_v1_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobShareRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobShareRangeRequest) ProtoMessage() {}
+
+func (x *BlobShareRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_square_v1_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobShareRangeRequest.ProtoReflect.Descriptor instead.
+func (*BlobShareRangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_square_v1_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BlobShareRangeRequest) GetTxs() [][]byte {
+	if x != nil {
+		return x.Txs
+	}
+	return nil
+}
+
+func (x *BlobShareRangeRequest) GetTxIndex() uint32 {
+	if x != nil {
+		return x.TxIndex
+	}
+	return 0
+}
+
+func (x *BlobShareRangeRequest) GetBlobIndex() uint32 {
+	if x != nil {
+		return x.BlobIndex
+	}
+	return 0
+}
+
+func (x *BlobShareRangeRequest) GetMaxSquareSize() uint32 {
+	if x != nil {
+		return x.MaxSquareSize
+	}
+	return 0
+}
+
+func (x *BlobShareRangeRequest) GetSubtreeRootThreshold() uint32 {
+	if x != nil {
+		return x.SubtreeRootThreshold
+	}
+	return 0
+}
+
+// ShareRangeResponse is an end exclusive range of shares.
+type ShareRangeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start uint32 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint32 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *ShareRangeResponse) Reset() {
+	*x = ShareRangeResponse{}
+	mi := &file_proto_square_v1_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareRangeResponse) ProtoMessage() {}
+
+func (x *ShareRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_square_v1_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareRangeResponse.ProtoReflect.Descriptor instead.
+func (*ShareRangeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_square_v1_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ShareRangeResponse) GetStart() uint32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *ShareRangeResponse) GetEnd() uint32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type CommitmentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Blob is a protobuf encoded proto.blob.v1.BlobProto.
+	Blob                 []byte `protobuf:"bytes,1,opt,name=blob,proto3" json:"blob,omitempty"`
+	SubtreeRootThreshold uint32 `protobuf:"varint,2,opt,name=subtree_root_threshold,json=subtreeRootThreshold,proto3" json:"subtree_root_threshold,omitempty"`
+}
+
+func (x *CommitmentRequest) Reset() {
+	*x = CommitmentRequest{}
+	mi := &file_proto_square_v1_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitmentRequest) ProtoMessage() {}
+
+func (x *CommitmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_square_v1_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitmentRequest.ProtoReflect.Descriptor instead.
+func (*CommitmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_square_v1_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CommitmentRequest) GetBlob() []byte {
+	if x != nil {
+		return x.Blob
+	}
+	return nil
+}
+
+func (x *CommitmentRequest) GetSubtreeRootThreshold() uint32 {
+	if x != nil {
+		return x.SubtreeRootThreshold
+	}
+	return 0
+}
+
+type CommitmentResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Commitment []byte `protobuf:"bytes,1,opt,name=commitment,proto3" json:"commitment,omitempty"`
+}
+
+func (x *CommitmentResponse) Reset() {
+	*x = CommitmentResponse{}
+	mi := &file_proto_square_v1_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitmentResponse) ProtoMessage() {}
+
+func (x *CommitmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_square_v1_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitmentResponse.ProtoReflect.Descriptor instead.
+func (*CommitmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_square_v1_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CommitmentResponse) GetCommitment() []byte {
+	if x != nil {
+		return x.Commitment
+	}
+	return nil
+}
+
+var File_proto_square_v1_service_proto protoreflect.FileDescriptor
+
+var file_proto_square_v1_service_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x2f, 0x76,
+	0x31, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x22, 0x82, 0x01, 0x0a, 0x10, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x78, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x03, 0x74, 0x78, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f, 0x73,
+	0x71, 0x75, 0x61, 0x72, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0d, 0x6d, 0x61, 0x78, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x34, 0x0a, 0x16, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f,
+	0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x14, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x54, 0x68, 0x72, 0x65,
+	0x73, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x4c, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68,
+	0x61, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x22, 0xa0, 0x01, 0x0a, 0x13, 0x54, 0x78, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x74,
+	0x78, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x03, 0x74, 0x78, 0x73, 0x12, 0x19, 0x0a,
+	0x08, 0x74, 0x78, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x74, 0x78, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f,
+	0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x34, 0x0a, 0x16, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74,
+	0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x14, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x54, 0x68, 0x72,
+	0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0xc1, 0x01, 0x0a, 0x15, 0x42, 0x6c, 0x6f, 0x62, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x74, 0x78, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x03, 0x74,
+	0x78, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x78, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x78, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1d, 0x0a,
+	0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x26, 0x0a, 0x0f,
+	0x6d, 0x61, 0x78, 0x5f, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x5f,
+	0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f,
+	0x74, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x3c, 0x0a, 0x12, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x5d, 0x0a, 0x11, 0x43, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6c, 0x6f,
+	0x62, 0x12, 0x34, 0x0a, 0x16, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x72, 0x6f, 0x6f,
+	0x74, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x14, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x54, 0x68,
+	0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x34, 0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x32, 0xed, 0x02,
+	0x0a, 0x06, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x12, 0x52, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x73,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x71,
+	0x75, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x0c,
+	0x54, 0x78, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x24, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x78, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x71, 0x75, 0x61, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5d, 0x0a, 0x0e, 0x42, 0x6c, 0x6f, 0x62, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x62,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x0a, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x71, 0x75,
+	0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x73, 0x71, 0x75, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35, 0x5a,
+	0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x65, 0x6c, 0x65,
+	0x73, 0x74, 0x69, 0x61, 0x6f, 0x72, 0x67, 0x2f, 0x67, 0x6f, 0x2d, 0x73, 0x71, 0x75, 0x61, 0x72,
+	0x65, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x71, 0x75, 0x61, 0x72,
+	0x65, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_square_v1_service_proto_rawDescOnce sync.Once
+	file_proto_square_v1_service_proto_rawDescData = file_proto_square_v1_service_proto_rawDesc
+)
+
+func file_proto_square_v1_service_proto_rawDescGZIP() []byte {
+	file_proto_square_v1_service_proto_rawDescOnce.Do(func() {
+		file_proto_square_v1_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_square_v1_service_proto_rawDescData)
+	})
+	return file_proto_square_v1_service_proto_rawDescData
+}
+
+var file_proto_square_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_square_v1_service_proto_goTypes = []any{
+	(*ConstructRequest)(nil),      // 0: proto.square.v1.ConstructRequest
+	(*ConstructResponse)(nil),     // 1: proto.square.v1.ConstructResponse
+	(*TxShareRangeRequest)(nil),   // 2: proto.square.v1.TxShareRangeRequest
+	(*BlobShareRangeRequest)(nil), // 3: proto.square.v1.BlobShareRangeRequest
+	(*ShareRangeResponse)(nil),    // 4: proto.square.v1.ShareRangeResponse
+	(*CommitmentRequest)(nil),     // 5: proto.square.v1.CommitmentRequest
+	(*CommitmentResponse)(nil),    // 6: proto.square.v1.CommitmentResponse
+}
+var file_proto_square_v1_service_proto_depIdxs = []int32{
+	0, // 0: proto.square.v1.Square.Construct:input_type -> proto.square.v1.ConstructRequest
+	2, // 1: proto.square.v1.Square.TxShareRange:input_type -> proto.square.v1.TxShareRangeRequest
+	3, // 2: proto.square.v1.Square.BlobShareRange:input_type -> proto.square.v1.BlobShareRangeRequest
+	5, // 3: proto.square.v1.Square.Commitment:input_type -> proto.square.v1.CommitmentRequest
+	1, // 4: proto.square.v1.Square.Construct:output_type -> proto.square.v1.ConstructResponse
+	4, // 5: proto.square.v1.Square.TxShareRange:output_type -> proto.square.v1.ShareRangeResponse
+	4, // 6: proto.square.v1.Square.BlobShareRange:output_type -> proto.square.v1.ShareRangeResponse
+	6, // 7: proto.square.v1.Square.Commitment:output_type -> proto.square.v1.CommitmentResponse
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_square_v1_service_proto_init() }
+func file_proto_square_v1_service_proto_init() {
+	if File_proto_square_v1_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_square_v1_service_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_square_v1_service_proto_goTypes,
+		DependencyIndexes: file_proto_square_v1_service_proto_depIdxs,
+		MessageInfos:      file_proto_square_v1_service_proto_msgTypes,
+	}.Build()
+	File_proto_square_v1_service_proto = out.File
+	file_proto_square_v1_service_proto_rawDesc = nil
+	file_proto_square_v1_service_proto_goTypes = nil
+	file_proto_square_v1_service_proto_depIdxs = nil
+}
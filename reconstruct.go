@@ -0,0 +1,53 @@
+package square
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// ConstructionParams are the parameters a square was constructed with at a
+// given height.
+type ConstructionParams struct {
+	// Version is the square version used at the height. If unset,
+	// DefaultSquareVersion is used.
+	Version              SquareVersion
+	MaxSquareSize        int
+	SubtreeRootThreshold int
+}
+
+// Hash returns the sha256 hash of the concatenated shares of the square.
+func (s Square) Hash() [32]byte {
+	h := sha256.New()
+	for _, sh := range s {
+		h.Write(sh.ToBytes())
+	}
+	var sum [32]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// Reconstruct constructs the square from the ordered transactions of a block
+// using the given parameters and verifies that its hash, as returned by
+// Square.Hash, matches expectedHash. If the hashes differ, a
+// *SquareHashMismatchError describing the reconstructed square is returned.
+// This allows archival nodes to re-verify historical blocks.
+func Reconstruct(txs [][]byte, expectedHash [32]byte, params ConstructionParams) error {
+	version := params.Version
+	if version == 0 {
+		version = DefaultSquareVersion
+	}
+	s, err := ConstructWithVersion(version, txs, params.MaxSquareSize, params.SubtreeRootThreshold)
+	if err != nil {
+		return fmt.Errorf("constructing square: %w", err)
+	}
+	if actual := s.Hash(); actual != expectedHash {
+		return &SquareHashMismatchError{
+			Expected:   expectedHash,
+			Actual:     actual,
+			Version:    version,
+			SquareSize: s.Size(),
+			NumTxs:     len(txs),
+		}
+	}
+	return nil
+}
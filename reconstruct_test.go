@@ -0,0 +1,41 @@
+package square_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconstruct(t *testing.T) {
+	txs := generateOrderedTxs(10, 10, 2, 1000)
+	params := square.ConstructionParams{
+		Version:              square.SquareVersionOne,
+		MaxSquareSize:        defaultMaxSquareSize,
+		SubtreeRootThreshold: defaultSubtreeRootThreshold,
+	}
+	s, err := square.ConstructWithVersion(square.SquareVersionOne, txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	hash := s.Hash()
+
+	require.NoError(t, square.Reconstruct(txs, hash, params))
+
+	// dropping a tx changes the square
+	err = square.Reconstruct(txs[1:], hash, params)
+	require.ErrorIs(t, err, square.ErrSquareHashMismatch)
+	var mismatch *square.SquareHashMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, hash, mismatch.Expected)
+	require.NotEqual(t, hash, mismatch.Actual)
+	require.Equal(t, square.SquareVersionOne, mismatch.Version)
+	require.Equal(t, len(txs)-1, mismatch.NumTxs)
+
+	// the default version is used if none is set
+	defaultSquare, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	params.Version = 0
+	require.NoError(t, square.Reconstruct(txs, defaultSquare.Hash(), params))
+
+	params.MaxSquareSize = 3
+	require.ErrorIs(t, square.Reconstruct(txs, hash, params), square.ErrInvalidMaxSquareSize)
+}
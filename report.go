@@ -0,0 +1,187 @@
+package square
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/share"
+	"google.golang.org/protobuf/proto"
+)
+
+// RejectionReason describes why a transaction could not be added to a square.
+type RejectionReason uint8
+
+const (
+	// RejectionInvalidBlobTx indicates that the transaction looked like a blob
+	// tx but could not be unmarshalled.
+	RejectionInvalidBlobTx RejectionReason = iota + 1
+	// RejectionTxAfterBlobTx indicates that a normal transaction was ordered
+	// after a blob transaction.
+	RejectionTxAfterBlobTx
+	// RejectionSquareFull indicates that there was not enough space left in
+	// the square to fit the transaction.
+	RejectionSquareFull
+	// RejectionBlobLimitExceeded indicates that the blob transaction would
+	// exceed the blob count or blob bytes limit of the builder.
+	RejectionBlobLimitExceeded
+	// RejectionNamespaceDenied indicates that the namespace policy of the
+	// builder denied a namespace of the blob transaction.
+	RejectionNamespaceDenied
+)
+
+func (r RejectionReason) String() string {
+	switch r {
+	case RejectionInvalidBlobTx:
+		return "invalid blob tx"
+	case RejectionTxAfterBlobTx:
+		return "tx after blob tx"
+	case RejectionSquareFull:
+		return "square full"
+	case RejectionBlobLimitExceeded:
+		return "blob limit exceeded"
+	case RejectionNamespaceDenied:
+		return "namespace denied"
+	default:
+		return fmt.Sprintf("unknown rejection reason %d", uint8(r))
+	}
+}
+
+// TxRejection describes the first transaction that could not be added to a
+// square.
+type TxRejection struct {
+	// Index is the index of the transaction in the provided list.
+	Index int
+	// Reason is the reason the transaction was rejected.
+	Reason RejectionReason
+	// Err is the error that Construct would have returned.
+	Err error
+}
+
+// TxPlacement describes where a transaction was placed in the square.
+type TxPlacement struct {
+	// Index is the index of the transaction in the provided list.
+	Index int
+	// IsBlobTx is true if the transaction is a blob transaction.
+	IsBlobTx bool
+	// Size is the number of bytes written to the compact shares. For blob
+	// transactions this is the size of the wrapped PFB.
+	Size int
+	// ShareRange is the range of compact shares occupied by the transaction.
+	ShareRange share.Range
+	// BlobRanges are the ranges of shares occupied by the blobs of a blob
+	// transaction, in the order of the blobs in the transaction.
+	BlobRanges []share.Range
+}
+
+// NamespaceUsage describes the data written to the square for a namespace.
+type NamespaceUsage struct {
+	Namespace share.Namespace
+	// Bytes is the number of transaction or blob bytes written to the
+	// namespace, excluding share headers and padding.
+	Bytes int
+	// Shares is the number of shares of the namespace, excluding padding.
+	Shares int
+}
+
+// ConstructionReport contains details about how a square was constructed.
+type ConstructionReport struct {
+	// Txs contains the placement of every transaction that was added to the
+	// square in the order they were provided.
+	Txs []TxPlacement
+	// Namespaces contains the usage of each namespace ordered by namespace.
+	Namespaces []NamespaceUsage
+	// ReservedPaddingShares is the number of padding shares between the
+	// reserved namespaces and the first blob.
+	ReservedPaddingShares int
+	// NamespacePaddingShares is the number of padding shares between blobs.
+	NamespacePaddingShares int
+	// TailPaddingShares is the number of padding shares after the last blob.
+	TailPaddingShares int
+	// FirstFailure is the first transaction that could not be added to the
+	// square. It is nil if construction succeeded.
+	FirstFailure *TxRejection
+}
+
+// ConstructWithReport behaves like Construct but additionally returns a report
+// describing the placement of each transaction, the bytes used per namespace
+// and the amount of padding. If a transaction can not be added, the report is
+// returned alongside the error and contains the first failing transaction.
+func ConstructWithReport(txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Square, *ConstructionReport, error) {
+	builder, err := NewBuilder(maxSquareSize, subtreeRootThreshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	report := &ConstructionReport{}
+	if rejection := builder.appendOrderedTxs(txs); rejection != nil {
+		report.FirstFailure = rejection
+		return nil, report, rejection.Err
+	}
+	square, err := builder.Export()
+	if err != nil {
+		return nil, report, err
+	}
+
+	report.Txs = make([]TxPlacement, builder.NumTxs())
+	for i := range report.Txs {
+		shareRange, err := builder.FindTxShareRange(i)
+		if err != nil {
+			return nil, report, err
+		}
+		placement := TxPlacement{Index: i, ShareRange: shareRange}
+		if i < len(builder.Txs) {
+			placement.Size = len(builder.Txs[i])
+		} else {
+			wpfb := builder.Pfbs[i-len(builder.Txs)]
+			placement.IsBlobTx = true
+			placement.Size = proto.Size(wpfb)
+			placement.BlobRanges = make([]share.Range, len(wpfb.ShareIndexes))
+			for j, shareIndex := range wpfb.ShareIndexes {
+				blobLen, err := builder.BlobShareLength(i, j)
+				if err != nil {
+					return nil, report, err
+				}
+				placement.BlobRanges[j] = share.NewRange(int(shareIndex), int(shareIndex)+blobLen)
+			}
+		}
+		report.Txs[i] = placement
+	}
+
+	namespaceBytes := make(map[string]int)
+	for _, placement := range report.Txs {
+		if placement.IsBlobTx {
+			namespaceBytes[string(builder.namespaces.PayForBlob.Bytes())] += placement.Size
+		} else {
+			namespaceBytes[string(builder.namespaces.Tx.Bytes())] += placement.Size
+		}
+	}
+	for _, isr := range builder.Isrs {
+		namespaceBytes[string(builder.namespaces.IntermediateStateRoots.Bytes())] += len(isr)
+	}
+	for _, element := range builder.Blobs {
+		namespaceBytes[string(element.Blob.Namespace().Bytes())] += element.Blob.DataLen()
+	}
+
+	for _, sh := range square {
+		ns := sh.Namespace()
+		switch {
+		case ns.IsPrimaryReservedPadding():
+			report.ReservedPaddingShares++
+		case ns.IsTailPadding():
+			report.TailPaddingShares++
+		case sh.IsPadding():
+			report.NamespacePaddingShares++
+		default:
+			last := len(report.Namespaces) - 1
+			if last < 0 || !bytes.Equal(report.Namespaces[last].Namespace.Bytes(), ns.Bytes()) {
+				report.Namespaces = append(report.Namespaces, NamespaceUsage{
+					Namespace: ns,
+					Bytes:     namespaceBytes[string(ns.Bytes())],
+				})
+				last++
+			}
+			report.Namespaces[last].Shares++
+		}
+	}
+
+	return square, report, nil
+}
@@ -0,0 +1,69 @@
+package square_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstructWithReport(t *testing.T) {
+	normalTxs := test.GenerateTxs(250, 250, 5)
+	blobTxs := test.GenerateBlobTxs(3, 2, 1000)
+	txs := append(append([][]byte{}, normalTxs...), blobTxs...)
+
+	s, report, err := square.ConstructWithReport(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	expected, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.True(t, expected.Equals(s))
+
+	require.Nil(t, report.FirstFailure)
+	require.Len(t, report.Txs, len(txs))
+	for i, placement := range report.Txs {
+		require.Equal(t, i, placement.Index)
+		require.Equal(t, i >= len(normalTxs), placement.IsBlobTx)
+		expectedRange, err := square.TxShareRange(txs, i, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		require.Equal(t, expectedRange, placement.ShareRange)
+		if placement.IsBlobTx {
+			require.Len(t, placement.BlobRanges, 2)
+			for j, blobRange := range placement.BlobRanges {
+				expectedRange, err := square.BlobShareRange(txs, i, j, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+				require.NoError(t, err)
+				require.Equal(t, expectedRange, blobRange)
+			}
+		}
+	}
+
+	// all generated blobs share the same namespace
+	require.Len(t, report.Namespaces, 3)
+	require.Equal(t, share.TxNamespace, report.Namespaces[0].Namespace)
+	require.Equal(t, share.PayForBlobNamespace, report.Namespaces[1].Namespace)
+	require.Equal(t, 6*1000, report.Namespaces[2].Bytes)
+	require.Equal(t, 6*share.SparseSharesNeeded(1000), report.Namespaces[2].Shares)
+
+	shareCount := report.ReservedPaddingShares + report.NamespacePaddingShares + report.TailPaddingShares
+	for _, usage := range report.Namespaces {
+		shareCount += usage.Shares
+	}
+	require.Equal(t, len(s), shareCount)
+}
+
+func TestConstructWithReportRejection(t *testing.T) {
+	txs := append(test.GenerateBlobTxs(1, 1, 100), test.GenerateTxs(250, 250, 1)...)
+	_, report, err := square.ConstructWithReport(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+	require.NotNil(t, report.FirstFailure)
+	require.Equal(t, 1, report.FirstFailure.Index)
+	require.Equal(t, square.RejectionTxAfterBlobTx, report.FirstFailure.Reason)
+
+	txs = test.GenerateBlobTxs(2, 1, 2*mebibyte)
+	_, report, err = square.ConstructWithReport(txs, 64, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+	require.Equal(t, 0, report.FirstFailure.Index)
+	require.Equal(t, square.RejectionSquareFull, report.FirstFailure.Reason)
+	require.Equal(t, "square full", report.FirstFailure.Reason.String())
+}
@@ -0,0 +1,81 @@
+package square
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+// appendKind is the kind of transaction recorded by an appendRecord.
+type appendKind uint8
+
+const (
+	// appendTx is a normal transaction, including fibre transactions which
+	// are appended as normal transactions.
+	appendTx appendKind = iota
+	appendBlobTx
+)
+
+// appendRecord describes a single append to the builder so that it can be
+// undone.
+type appendRecord struct {
+	kind appendKind
+	// sizeDelta is the number of shares the append added to currentSize
+	sizeDelta int
+	// elements is the number of transactions or blobs that were added
+	elements int
+	// blobBytes is the number of blob bytes that were added
+	blobBytes int
+	// counter is the state of the compact share counter of the kind before
+	// the append
+	counter share.CompactShareCounter
+}
+
+// RevertLastN undoes the last n appends to the builder in reverse order,
+// regardless of how transactions and blob transactions were interleaved. Only
+// appends made with AppendTx, TryAppendTx, AppendBlobTx, TryAppendBlobTx and
+// AppendFibreTxs are recorded, where every fibre transaction appended with
+// AppendFibreTxs counts as one append. Calls that rewrite the builder's state, such as
+// SetIntermediateStateRoots, InsertBlobTx, RemoveBlobTx and Reset, discard all
+// prior records. An error matching ErrNothingToRevert is returned, and the
+// builder left untouched, if fewer than n appends are recorded.
+func (b *Builder) RevertLastN(n int) error {
+	if n < 0 || n > len(b.appends) {
+		return fmt.Errorf("%w: requested %d, %d recorded", ErrNothingToRevert, n, len(b.appends))
+	}
+	for i := 0; i < n; i++ {
+		record := b.appends[len(b.appends)-1]
+		b.appends = b.appends[:len(b.appends)-1]
+		switch record.kind {
+		case appendTx:
+			clear(b.Txs[len(b.Txs)-record.elements:])
+			b.Txs = b.Txs[:len(b.Txs)-record.elements]
+			*b.TxCounter = record.counter
+		case appendBlobTx:
+			b.revertLastBlobTx(record)
+		}
+		b.currentSize -= record.sizeDelta
+	}
+	if n > 0 {
+		b.done = false
+	}
+	return nil
+}
+
+// revertLastBlobTx removes the last PFB and its blobs. The blobs are filtered
+// by PfbIndex since Export may have sorted them by namespace.
+func (b *Builder) revertLastBlobTx(record appendRecord) {
+	pfbIndex := len(b.Pfbs) - 1
+	b.Pfbs[pfbIndex] = nil
+	b.Pfbs = b.Pfbs[:pfbIndex]
+	blobs := b.Blobs[:0]
+	for _, element := range b.Blobs {
+		if element.PfbIndex != pfbIndex {
+			blobs = append(blobs, element)
+		}
+	}
+	clear(b.Blobs[len(blobs):])
+	b.Blobs = blobs
+	*b.PfbCounter = record.counter
+	b.blobBytes -= record.blobBytes
+}
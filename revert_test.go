@@ -0,0 +1,91 @@
+package square_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderRevertLastN(t *testing.T) {
+	normalTxs := test.GenerateTxs(200, 400, 6)
+	blobTxs := test.GenerateBlobTxs(6, 2, 1000)
+
+	type step struct {
+		txBytes  []byte
+		isBlobTx bool
+	}
+	// interleave the appends of normal and blob transactions
+	var steps []step
+	for i := range normalTxs {
+		steps = append(steps, step{normalTxs[i], false}, step{blobTxs[i], true})
+	}
+
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	appendStep := func(s step) {
+		if s.isBlobTx {
+			blobTx, isBlobTx, err := tx.UnmarshalBlobTx(s.txBytes)
+			require.NoError(t, err)
+			require.True(t, isBlobTx)
+			require.True(t, builder.AppendBlobTx(blobTx))
+		} else {
+			require.True(t, builder.AppendTx(s.txBytes))
+		}
+	}
+
+	// the square built from the first half of the appends
+	half := len(steps) / 2
+	var expectedTxs [][]byte
+	for _, s := range steps[:half] {
+		if !s.isBlobTx {
+			expectedTxs = append(expectedTxs, s.txBytes)
+		}
+	}
+	for _, s := range steps[:half] {
+		if s.isBlobTx {
+			expectedTxs = append(expectedTxs, s.txBytes)
+		}
+	}
+	expected, err := square.Construct(expectedTxs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	for _, s := range steps[:half] {
+		appendStep(s)
+	}
+	sizeAtHalf := builder.CurrentSize()
+	for _, s := range steps[half:] {
+		appendStep(s)
+	}
+	// exporting sorts the blobs which must not affect reverting
+	_, err = builder.Export()
+	require.NoError(t, err)
+
+	require.NoError(t, builder.RevertLastN(len(steps)-half))
+	require.Equal(t, sizeAtHalf, builder.CurrentSize())
+	dataSquare, err := builder.Export()
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+
+	// reverted appends can be appended again
+	for _, s := range steps[half:] {
+		appendStep(s)
+	}
+	require.NoError(t, builder.RevertLastN(0))
+
+	require.ErrorIs(t, builder.RevertLastN(len(steps)+1), square.ErrNothingToRevert)
+	require.NoError(t, builder.RevertLastN(len(steps)))
+	require.True(t, builder.IsEmpty())
+	require.Zero(t, builder.CurrentSize())
+	require.Empty(t, builder.Blobs)
+	require.ErrorIs(t, builder.RevertLastN(1), square.ErrNothingToRevert)
+}
+
+func TestBuilderRevertAfterRewrite(t *testing.T) {
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, generateOrderedTxs(2, 2, 1, 500)...)
+	require.NoError(t, err)
+	require.NoError(t, builder.RemoveBlobTx(0))
+	require.ErrorIs(t, builder.RevertLastN(1), square.ErrNothingToRevert)
+}
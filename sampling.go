@@ -0,0 +1,58 @@
+package square
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+// Coordinate is the row and column of a share in a square.
+type Coordinate struct {
+	Row int
+	Col int
+}
+
+// SampleCoordinates returns the coordinates of samples distinct shares of a
+// square of the given size, as sampled by a light node performing data
+// availability sampling. The coordinates are derived from the seed so the
+// same seed always yields the same coordinates. If samples is larger than the
+// number of shares in the square, the coordinates of every share are
+// returned.
+func SampleCoordinates(size int, samples int, seed []byte) []Coordinate {
+	if size <= 0 || samples <= 0 {
+		return nil
+	}
+	total := size * size
+	samples = min(samples, total)
+
+	coords := make([]Coordinate, 0, samples)
+	seen := make(map[int]struct{}, samples)
+	buf := make([]byte, len(seed)+8)
+	copy(buf, seed)
+	for counter := uint64(0); len(coords) < samples; counter++ {
+		binary.BigEndian.PutUint64(buf[len(seed):], counter)
+		hash := sha256.Sum256(buf)
+		index := int(binary.BigEndian.Uint64(hash[:8]) % uint64(total))
+		if _, ok := seen[index]; ok {
+			continue
+		}
+		seen[index] = struct{}{}
+		coords = append(coords, Coordinate{Row: index / size, Col: index % size})
+	}
+	return coords
+}
+
+// SharesAt returns the shares at the provided coordinates in the order of
+// the coordinates.
+func (s Square) SharesAt(coords []Coordinate) ([]share.Share, error) {
+	shares := make([]share.Share, len(coords))
+	for i, coord := range coords {
+		sh, err := s.ShareAt(coord.Row, coord.Col)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = sh
+	}
+	return shares, nil
+}
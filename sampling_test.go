@@ -0,0 +1,46 @@
+package square_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleCoordinates(t *testing.T) {
+	seed := []byte("seed")
+	coords := square.SampleCoordinates(8, 16, seed)
+	require.Len(t, coords, 16)
+	require.Equal(t, coords, square.SampleCoordinates(8, 16, seed))
+	require.NotEqual(t, coords, square.SampleCoordinates(8, 16, []byte("other seed")))
+
+	seen := make(map[square.Coordinate]bool)
+	for _, coord := range coords {
+		require.False(t, seen[coord], "duplicate coordinate %v", coord)
+		seen[coord] = true
+		require.True(t, coord.Row >= 0 && coord.Row < 8)
+		require.True(t, coord.Col >= 0 && coord.Col < 8)
+	}
+
+	require.Len(t, square.SampleCoordinates(4, 100, seed), 16)
+	require.Empty(t, square.SampleCoordinates(0, 10, seed))
+	require.Empty(t, square.SampleCoordinates(4, 0, seed))
+}
+
+func TestSquareSharesAt(t *testing.T) {
+	txs := generateOrderedTxs(10, 10, 2, 1000)
+	dataSquare, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	size := dataSquare.Size()
+
+	coords := square.SampleCoordinates(size, 10, []byte("seed"))
+	shares, err := dataSquare.SharesAt(coords)
+	require.NoError(t, err)
+	require.Len(t, shares, len(coords))
+	for i, coord := range coords {
+		require.Equal(t, dataSquare[coord.Row*size+coord.Col], shares[i])
+	}
+
+	_, err = dataSquare.SharesAt([]square.Coordinate{{Row: size, Col: 0}})
+	require.Error(t, err)
+}
@@ -0,0 +1,188 @@
+// Package service contains a reference implementation of the Square service
+// defined in proto/square/v1/service.proto. It exposes square construction,
+// share ranges and commitment generation to implementations in other
+// languages.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	square "github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	blobv1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
+	squarev1 "github.com/celestiaorg/go-square/v2/proto/square/v1"
+	"github.com/celestiaorg/go-square/v2/share"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// MaxRequestSize is the maximum size of a request body accepted by the HTTP
+// handler.
+const MaxRequestSize = 128 << 20
+
+// Server implements the Square service. Its methods have the signatures of a
+// gRPC service implementation so that it can be registered with generated
+// gRPC code.
+type Server struct {
+	merkleRootFn inclusion.MerkleRootFn
+}
+
+// NewServer returns a server computing share commitments with merkleRootFn.
+// If merkleRootFn is nil, inclusion.MerkleRoot is used.
+func NewServer(merkleRootFn inclusion.MerkleRootFn) *Server {
+	if merkleRootFn == nil {
+		merkleRootFn = inclusion.MerkleRoot
+	}
+	return &Server{merkleRootFn: merkleRootFn}
+}
+
+// Construct builds a square from the ordered transactions of the request.
+func (s *Server) Construct(_ context.Context, req *squarev1.ConstructRequest) (*squarev1.ConstructResponse, error) {
+	if err := validateSquareParams(req.MaxSquareSize, req.SubtreeRootThreshold); err != nil {
+		return nil, err
+	}
+	dataSquare, err := square.Construct(req.Txs, int(req.MaxSquareSize), int(req.SubtreeRootThreshold))
+	if err != nil {
+		return nil, err
+	}
+	return &squarev1.ConstructResponse{
+		Shares:     share.ToBytes(dataSquare),
+		SquareSize: uint32(dataSquare.Size()),
+	}, nil
+}
+
+// TxShareRange returns the range of shares occupied by a transaction.
+func (s *Server) TxShareRange(_ context.Context, req *squarev1.TxShareRangeRequest) (*squarev1.ShareRangeResponse, error) {
+	if err := validateSquareParams(req.MaxSquareSize, req.SubtreeRootThreshold); err != nil {
+		return nil, err
+	}
+	shareRange, err := square.TxShareRange(req.Txs, int(req.TxIndex), int(req.MaxSquareSize), int(req.SubtreeRootThreshold))
+	if err != nil {
+		return nil, err
+	}
+	return toShareRangeResponse(shareRange), nil
+}
+
+// BlobShareRange returns the range of shares occupied by a blob.
+func (s *Server) BlobShareRange(_ context.Context, req *squarev1.BlobShareRangeRequest) (*squarev1.ShareRangeResponse, error) {
+	if err := validateSquareParams(req.MaxSquareSize, req.SubtreeRootThreshold); err != nil {
+		return nil, err
+	}
+	shareRange, err := square.BlobShareRange(req.Txs, int(req.TxIndex), int(req.BlobIndex), int(req.MaxSquareSize), int(req.SubtreeRootThreshold))
+	if err != nil {
+		return nil, err
+	}
+	return toShareRangeResponse(shareRange), nil
+}
+
+// Commitment returns the share commitment of a blob.
+func (s *Server) Commitment(_ context.Context, req *squarev1.CommitmentRequest) (*squarev1.CommitmentResponse, error) {
+	if err := validateSubtreeRootThreshold(req.SubtreeRootThreshold); err != nil {
+		return nil, err
+	}
+	pb := &blobv1.BlobProto{}
+	if err := proto.Unmarshal(req.Blob, pb); err != nil {
+		return nil, fmt.Errorf("decoding blob: %w", err)
+	}
+	blob, err := share.NewBlobFromProto(pb)
+	if err != nil {
+		return nil, err
+	}
+	commitment, err := inclusion.CreateCommitment(blob, s.merkleRootFn, int(req.SubtreeRootThreshold))
+	if err != nil {
+		return nil, err
+	}
+	return &squarev1.CommitmentResponse{Commitment: commitment}, nil
+}
+
+// validateSquareParams returns an error if the max square size is not a
+// strictly positive power of two or the subtree root threshold is zero.
+func validateSquareParams(maxSquareSize, subtreeRootThreshold uint32) error {
+	if maxSquareSize == 0 || !square.IsPowerOfTwo(maxSquareSize) {
+		return fmt.Errorf("%w: max square size must be a strictly positive power of two, got %d", square.ErrInvalidMaxSquareSize, maxSquareSize)
+	}
+	return validateSubtreeRootThreshold(subtreeRootThreshold)
+}
+
+// validateSubtreeRootThreshold returns an error if the subtree root threshold
+// is zero, as it is used as a divisor when laying out blobs.
+func validateSubtreeRootThreshold(subtreeRootThreshold uint32) error {
+	if subtreeRootThreshold == 0 {
+		return errors.New("subtree root threshold must be strictly positive")
+	}
+	return nil
+}
+
+func toShareRangeResponse(shareRange share.Range) *squarev1.ShareRangeResponse {
+	return &squarev1.ShareRangeResponse{Start: uint32(shareRange.Start), End: uint32(shareRange.End)}
+}
+
+// Handler returns an HTTP handler serving the methods of the server at the
+// gRPC method paths, e.g. POST /proto.square.v1.Square/Construct. Requests
+// and responses are protobuf encoded unless the request has the content type
+// application/json, in which case the protobuf JSON mapping is used. Errors
+// are returned as plain text with status 400.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	handle(mux, "Construct", s.Construct)
+	handle(mux, "TxShareRange", s.TxShareRange)
+	handle(mux, "BlobShareRange", s.BlobShareRange)
+	handle(mux, "Commitment", s.Commitment)
+	return mux
+}
+
+// MethodPath returns the path at which the handler serves a method.
+func MethodPath(method string) string {
+	return "/proto.square.v1.Square/" + method
+}
+
+func handle[Req any, Resp proto.Message, ReqPtr interface {
+	*Req
+	proto.Message
+}](mux *http.ServeMux, method string, fn func(context.Context, ReqPtr) (Resp, error)) {
+	mux.HandleFunc(MethodPath(method), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, MaxRequestSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		isJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+		req := ReqPtr(new(Req))
+		if isJSON {
+			err = protojson.Unmarshal(body, req)
+		} else {
+			err = proto.Unmarshal(body, req)
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("decoding request: %v", err), http.StatusBadRequest)
+			return
+		}
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var out []byte
+		if isJSON {
+			w.Header().Set("Content-Type", "application/json")
+			out, err = protojson.Marshal(resp)
+		} else {
+			w.Header().Set("Content-Type", "application/protobuf")
+			out, err = proto.Marshal(resp)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(out)
+	})
+}
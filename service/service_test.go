@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	square "github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	squarev1 "github.com/celestiaorg/go-square/v2/proto/square/v1"
+	"github.com/celestiaorg/go-square/v2/service"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/squaretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	maxSquareSize        = 64
+	subtreeRootThreshold = 64
+)
+
+func TestServer(t *testing.T) {
+	gen := squaretest.NewGenerator(1)
+	txs := append(gen.Txs(100, 200, 3), gen.BlobTxs(2, 2, 1000)...)
+	server := service.NewServer(nil)
+	ctx := context.Background()
+
+	resp, err := server.Construct(ctx, &squarev1.ConstructRequest{Txs: txs, MaxSquareSize: maxSquareSize, SubtreeRootThreshold: subtreeRootThreshold})
+	require.NoError(t, err)
+	expected, err := square.Construct(txs, maxSquareSize, subtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, share.ToBytes(expected), resp.Shares)
+	require.Equal(t, uint32(expected.Size()), resp.SquareSize)
+
+	txRange, err := server.TxShareRange(ctx, &squarev1.TxShareRangeRequest{Txs: txs, TxIndex: 3, MaxSquareSize: maxSquareSize, SubtreeRootThreshold: subtreeRootThreshold})
+	require.NoError(t, err)
+	expectedRange, err := square.TxShareRange(txs, 3, maxSquareSize, subtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, uint32(expectedRange.Start), txRange.Start)
+	require.Equal(t, uint32(expectedRange.End), txRange.End)
+
+	blobRange, err := server.BlobShareRange(ctx, &squarev1.BlobShareRangeRequest{Txs: txs, TxIndex: 4, BlobIndex: 1, MaxSquareSize: maxSquareSize, SubtreeRootThreshold: subtreeRootThreshold})
+	require.NoError(t, err)
+	expectedRange, err = square.BlobShareRange(txs, 4, 1, maxSquareSize, subtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, uint32(expectedRange.Start), blobRange.Start)
+	require.Equal(t, uint32(expectedRange.End), blobRange.End)
+
+	blob := gen.Blobs(2000)[0]
+	blobBytes, err := blob.Marshal()
+	require.NoError(t, err)
+	commitment, err := server.Commitment(ctx, &squarev1.CommitmentRequest{Blob: blobBytes, SubtreeRootThreshold: subtreeRootThreshold})
+	require.NoError(t, err)
+	expectedCommitment, err := inclusion.CreateCommitment(blob, inclusion.MerkleRoot, subtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expectedCommitment, commitment.Commitment)
+
+	_, err = server.TxShareRange(ctx, &squarev1.TxShareRangeRequest{Txs: txs, TxIndex: 10, MaxSquareSize: maxSquareSize, SubtreeRootThreshold: subtreeRootThreshold})
+	require.Error(t, err)
+	_, err = server.Commitment(ctx, &squarev1.CommitmentRequest{Blob: []byte{0xff}})
+	require.Error(t, err)
+}
+
+func TestServerInvalidParams(t *testing.T) {
+	gen := squaretest.NewGenerator(1)
+	txs := append(gen.Txs(100, 200, 1), gen.BlobTxs(1, 1, 1000)...)
+	server := service.NewServer(nil)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name                 string
+		maxSquareSize        uint32
+		subtreeRootThreshold uint32
+	}{
+		{"zero threshold", maxSquareSize, 0},
+		{"zero max square size", 0, subtreeRootThreshold},
+		{"max square size not a power of two", 48, subtreeRootThreshold},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := server.Construct(ctx, &squarev1.ConstructRequest{Txs: txs, MaxSquareSize: tc.maxSquareSize, SubtreeRootThreshold: tc.subtreeRootThreshold})
+			require.Error(t, err)
+			_, err = server.TxShareRange(ctx, &squarev1.TxShareRangeRequest{Txs: txs, TxIndex: 0, MaxSquareSize: tc.maxSquareSize, SubtreeRootThreshold: tc.subtreeRootThreshold})
+			require.Error(t, err)
+			_, err = server.BlobShareRange(ctx, &squarev1.BlobShareRangeRequest{Txs: txs, TxIndex: 1, MaxSquareSize: tc.maxSquareSize, SubtreeRootThreshold: tc.subtreeRootThreshold})
+			require.Error(t, err)
+		})
+	}
+
+	blobBytes, err := gen.Blobs(2000)[0].Marshal()
+	require.NoError(t, err)
+	_, err = server.Commitment(ctx, &squarev1.CommitmentRequest{Blob: blobBytes})
+	require.Error(t, err)
+}
+
+func TestHandler(t *testing.T) {
+	txs := squaretest.NewGenerator(1).Txs(100, 200, 3)
+	srv := httptest.NewServer(service.NewServer(nil).Handler())
+	defer srv.Close()
+	req := &squarev1.TxShareRangeRequest{Txs: txs, TxIndex: 1, MaxSquareSize: maxSquareSize, SubtreeRootThreshold: subtreeRootThreshold}
+	expected, err := square.TxShareRange(txs, 1, maxSquareSize, subtreeRootThreshold)
+	require.NoError(t, err)
+
+	post := func(contentType string, body []byte) (*http.Response, []byte) {
+		resp, err := http.Post(srv.URL+service.MethodPath("TxShareRange"), contentType, bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		out, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, out
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		body, err := proto.Marshal(req)
+		require.NoError(t, err)
+		resp, out := post("application/protobuf", body)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		shareRange := &squarev1.ShareRangeResponse{}
+		require.NoError(t, proto.Unmarshal(out, shareRange))
+		require.Equal(t, uint32(expected.Start), shareRange.Start)
+		require.Equal(t, uint32(expected.End), shareRange.End)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		body, err := protojson.Marshal(req)
+		require.NoError(t, err)
+		resp, out := post("application/json", body)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		shareRange := &squarev1.ShareRangeResponse{}
+		require.NoError(t, protojson.Unmarshal(out, shareRange))
+		require.Equal(t, uint32(expected.End), shareRange.End)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		resp, _ := post("application/json", []byte("{"))
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp, err := http.Get(srv.URL + service.MethodPath("TxShareRange"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
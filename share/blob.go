@@ -1,9 +1,10 @@
 package share
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"sort"
 
 	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
@@ -12,25 +13,43 @@ import (
 
 // Blob (stands for binary large object) is a core type that represents data
 // to be submitted to the Celestia network alongside an accompanying namespace
-// and optional signer (for proving the signer of the blob)
+// and optional signer (for proving the signer of the blob), metadata (for
+// tagging the content of the blob) or codec (for compressed data)
 type Blob struct {
 	namespace    Namespace
 	data         []byte
 	shareVersion uint8
 	signer       []byte
+	metadata     []byte
+	codec        uint8
 }
 
 // New creates a new coretypes.Blob from the provided data after performing
-// basic stateless checks over it.
-func NewBlob(ns Namespace, data []byte, shareVersion uint8, signer []byte) (*Blob, error) {
+// basic stateless checks over it. Only version 0 namespaces are accepted
+// unless overridden using AllowedNamespaceVersions.
+func NewBlob(ns Namespace, data []byte, shareVersion uint8, signer []byte, opts ...NamespaceOption) (*Blob, error) {
+	return newBlob(ns, data, shareVersion, signer, nil, 0, opts...)
+}
+
+func newBlob(ns Namespace, data []byte, shareVersion uint8, signer, metadata []byte, codec uint8, opts ...NamespaceOption) (*Blob, error) {
 	if len(data) == 0 {
 		return nil, errors.New("data can not be empty")
 	}
 	if ns.IsEmpty() {
 		return nil, errors.New("namespace can not be empty")
 	}
-	if ns.Version() != NamespaceVersionZero {
-		return nil, fmt.Errorf("namespace version must be %d got %d", NamespaceVersionZero, ns.Version())
+	cfg := newNamespaceConfig([]uint8{NamespaceVersionZero}, opts)
+	if !slices.Contains(cfg.allowedVersions, ns.Version()) {
+		if len(cfg.allowedVersions) == 1 {
+			return nil, fmt.Errorf("namespace version must be %d got %d", cfg.allowedVersions[0], ns.Version())
+		}
+		return nil, fmt.Errorf("namespace version must be one of %v got %d", cfg.allowedVersions, ns.Version())
+	}
+	if shareVersion != ShareVersionThree && len(metadata) != 0 {
+		return nil, fmt.Errorf("share version %d does not support metadata", shareVersion)
+	}
+	if shareVersion != ShareVersionFour && codec != 0 {
+		return nil, fmt.Errorf("share version %d does not support codecs", shareVersion)
 	}
 	switch shareVersion {
 	case ShareVersionZero:
@@ -39,18 +58,34 @@ func NewBlob(ns Namespace, data []byte, shareVersion uint8, signer []byte) (*Blo
 		}
 	case ShareVersionOne:
 		if len(signer) != SignerSize {
-			return nil, fmt.Errorf("share version 1 requires signer of size %d bytes", SignerSize)
+			return nil, fmt.Errorf("share version 1 requires signer of size %d bytes, got %d", SignerSize, len(signer))
+		}
+	case ShareVersionThree:
+		if signer != nil {
+			return nil, errors.New("share version 3 does not support signer")
+		}
+		if len(metadata) > MaxBlobMetadataSize {
+			return nil, fmt.Errorf("share version 3 supports metadata of at most %d bytes, got %d", MaxBlobMetadataSize, len(metadata))
+		}
+	case ShareVersionFour:
+		if signer != nil {
+			return nil, errors.New("share version 4 does not support signer")
+		}
+		if codec == 0 {
+			return nil, errors.New("share version 4 requires a codec")
 		}
 	// Note that we don't specifically check that shareVersion is less than 128 as this is caught
 	// by the default case
 	default:
-		return nil, fmt.Errorf("share version %d not supported. Please use 0 or 1", shareVersion)
+		return nil, fmt.Errorf("share version %d not supported. Please use 0, 1, 3 or 4", shareVersion)
 	}
 	return &Blob{
 		namespace:    ns,
 		data:         data,
 		shareVersion: shareVersion,
 		signer:       signer,
+		metadata:     metadata,
+		codec:        codec,
 	}, nil
 }
 
@@ -64,14 +99,28 @@ func NewV1Blob(ns Namespace, data []byte, signer []byte) (*Blob, error) {
 	return NewBlob(ns, data, 1, signer)
 }
 
-// UnmarshalBlob unmarshals a blob from the proto encoded bytes
-func UnmarshalBlob(blob []byte) (*Blob, error) {
+// NewV1BlobWithSigner creates a new blob with share version 1 signed by
+// signer. It is equivalent to NewV1Blob but the size of the signer is
+// enforced at compile time.
+func NewV1BlobWithSigner(ns Namespace, data []byte, signer Signer) (*Blob, error) {
+	return NewBlob(ns, data, ShareVersionOne, signer.Bytes())
+}
+
+// NewV3Blob creates a new blob with share version 3 carrying the provided
+// metadata. The metadata must not exceed MaxBlobMetadataSize bytes.
+func NewV3Blob(ns Namespace, data []byte, metadata []byte) (*Blob, error) {
+	return newBlob(ns, data, ShareVersionThree, nil, metadata, 0)
+}
+
+// UnmarshalBlob unmarshals a blob from the proto encoded bytes. Only version
+// 0 namespaces are accepted unless overridden using AllowedNamespaceVersions.
+func UnmarshalBlob(blob []byte, opts ...NamespaceOption) (*Blob, error) {
 	pb := &v1.BlobProto{}
 	err := proto.Unmarshal(blob, pb)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal blob: %w", err)
 	}
-	return NewBlobFromProto(pb)
+	return NewBlobFromProto(pb, opts...)
 }
 
 // Marshal marshals the blob to the proto encoded bytes
@@ -82,56 +131,37 @@ func (b *Blob) Marshal() ([]byte, error) {
 		ShareVersion:     uint32(b.shareVersion),
 		Data:             b.data,
 		Signer:           b.signer,
+		Metadata:         b.metadata,
+		Codec:            uint32(b.codec),
 	}
 	return proto.Marshal(pb)
 }
 
-// MarshalJSON converts blob's data to the json encoded bytes
-func (b *Blob) MarshalJSON() ([]byte, error) {
-	pb := &v1.BlobProto{
-		NamespaceId:      b.namespace.ID(),
-		NamespaceVersion: uint32(b.namespace.Version()),
-		ShareVersion:     uint32(b.shareVersion),
-		Data:             b.data,
-		Signer:           b.signer,
-	}
-	return json.Marshal(pb)
-}
-
-// UnmarshalJSON converts json encoded data to the blob
-func (b *Blob) UnmarshalJSON(bb []byte) error {
-	pb := &v1.BlobProto{}
-	err := json.Unmarshal(bb, pb)
-	if err != nil {
-		return err
-	}
-
-	blob, err := NewBlobFromProto(pb)
-	if err != nil {
-		return err
-	}
-
-	*b = *blob
-	return nil
-}
-
-// NewBlobFromProto creates a new blob from the proto generated type
-func NewBlobFromProto(pb *v1.BlobProto) (*Blob, error) {
+// NewBlobFromProto creates a new blob from the proto generated type. Only
+// version 0 namespaces are accepted unless overridden using
+// AllowedNamespaceVersions.
+func NewBlobFromProto(pb *v1.BlobProto, opts ...NamespaceOption) (*Blob, error) {
 	if pb.NamespaceVersion > NamespaceVersionMax {
 		return nil, errors.New("namespace version can not be greater than MaxNamespaceVersion")
 	}
+	if pb.Codec > math.MaxUint8 {
+		return nil, errors.New("codec can not be greater than MaxUint8")
+	}
 	if pb.ShareVersion > MaxShareVersion {
 		return nil, fmt.Errorf("share version can not be greater than MaxShareVersion %d", MaxShareVersion)
 	}
-	ns, err := NewNamespace(uint8(pb.NamespaceVersion), pb.NamespaceId)
+	ns, err := NewNamespace(uint8(pb.NamespaceVersion), pb.NamespaceId, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("invalid namespace: %w", err)
 	}
-	return NewBlob(
+	return newBlob(
 		ns,
 		pb.Data,
 		uint8(pb.ShareVersion),
 		pb.Signer,
+		pb.Metadata,
+		uint8(pb.Codec),
+		opts...,
 	)
 }
 
@@ -150,6 +180,22 @@ func (b *Blob) Signer() []byte {
 	return b.signer
 }
 
+// TypedSigner returns the signer of the blob as a Signer. The second return
+// value is false if the blob has no signer.
+func (b *Blob) TypedSigner() (Signer, bool) {
+	signer, err := ParseSigner(b.signer)
+	if err != nil {
+		return Signer{}, false
+	}
+	return signer, true
+}
+
+// Metadata returns the metadata of the blob. It is only set for share version
+// 3 blobs.
+func (b *Blob) Metadata() []byte {
+	return b.metadata
+}
+
 // Data returns the data of the blob
 func (b *Blob) Data() []byte {
 	return b.data
@@ -160,6 +206,32 @@ func (b *Blob) DataLen() int {
 	return len(b.data)
 }
 
+// Codec returns the id of the codec used to compress the data of the blob. It
+// is only set for share version 4 blobs.
+func (b *Blob) Codec() uint8 {
+	return b.codec
+}
+
+// SequenceLen returns the sequence length that is written to the first share
+// of the blob. For share version 3 and 4 blobs, this includes the header that
+// prefixes the data.
+func (b *Blob) SequenceLen() uint32 {
+	return uint32(len(b.sequenceHeader()) + len(b.data))
+}
+
+// sequenceHeader returns the bytes that prefix the data of the blob in its
+// share sequence. Only share version 3 and 4 blobs have a header.
+func (b *Blob) sequenceHeader() []byte {
+	switch b.shareVersion {
+	case ShareVersionThree:
+		return marshalBlobMetadata(b.metadata)
+	case ShareVersionFour:
+		return []byte{b.codec}
+	default:
+		return nil
+	}
+}
+
 // Compare is used to order two blobs based on their namespace
 func (b *Blob) Compare(other *Blob) int {
 	return b.namespace.Compare(other.namespace)
@@ -0,0 +1,82 @@
+package share
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// BlobBatch groups multiple blobs destined for the same namespace into a
+// single physical blob. Submitting many small blobs as one batch avoids
+// paying for the padding of each individual blob. Each item is prefixed with
+// its length encoded as a uvarint, the same framing used for units in compact
+// shares.
+type BlobBatch struct {
+	namespace Namespace
+	items     [][]byte
+	size      int
+}
+
+// NewBlobBatch returns an empty batch of blobs for the namespace.
+func NewBlobBatch(ns Namespace) *BlobBatch {
+	return &BlobBatch{namespace: ns}
+}
+
+// Add appends the data of a blob to the batch.
+func (b *BlobBatch) Add(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("batched blob data can not be empty")
+	}
+	b.items = append(b.items, data)
+	b.size += DelimLen(uint64(len(data))) + len(data)
+	return nil
+}
+
+// Namespace returns the namespace of the batch.
+func (b *BlobBatch) Namespace() Namespace {
+	return b.namespace
+}
+
+// Len returns the number of blobs in the batch.
+func (b *BlobBatch) Len() int {
+	return len(b.items)
+}
+
+// Size returns the size of the framed data of the batch.
+func (b *BlobBatch) Size() int {
+	return b.size
+}
+
+// Blob returns the physical blob containing the framed data of every blob in
+// the batch.
+func (b *BlobBatch) Blob() (*Blob, error) {
+	if len(b.items) == 0 {
+		return nil, errors.New("blob batch is empty")
+	}
+	data := make([]byte, 0, b.size)
+	for _, item := range b.items {
+		data = binary.AppendUvarint(data, uint64(len(item)))
+		data = append(data, item...)
+	}
+	return NewV0Blob(b.namespace, data)
+}
+
+// UnpackBlobBatch returns the data of each blob in a physical blob created by
+// BlobBatch.Blob. The returned slices are views into the data of the blob.
+func UnpackBlobBatch(blob *Blob) ([][]byte, error) {
+	data := blob.Data()
+	items := make([][]byte, 0)
+	for len(data) > 0 {
+		itemLen, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid length prefix of batched blob %d", len(items))
+		}
+		data = data[n:]
+		if itemLen == 0 || itemLen > uint64(len(data)) {
+			return nil, fmt.Errorf("batched blob %d has invalid length %d", len(items), itemLen)
+		}
+		items = append(items, data[:itemLen])
+		data = data[itemLen:]
+	}
+	return items, nil
+}
@@ -0,0 +1,54 @@
+package share
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobBatch(t *testing.T) {
+	ns := RandomBlobNamespace()
+	items := [][]byte{
+		{1},
+		bytes.Repeat([]byte{2}, 127),
+		bytes.Repeat([]byte{3}, 128),
+		bytes.Repeat([]byte{4}, 1000),
+	}
+
+	batch := NewBlobBatch(ns)
+	_, err := batch.Blob()
+	require.Error(t, err)
+	require.Error(t, batch.Add(nil))
+	for _, item := range items {
+		require.NoError(t, batch.Add(item))
+	}
+	require.Equal(t, len(items), batch.Len())
+	require.Equal(t, ns, batch.Namespace())
+
+	blob, err := batch.Blob()
+	require.NoError(t, err)
+	require.Equal(t, ns, blob.Namespace())
+	require.Equal(t, batch.Size(), blob.DataLen())
+	// the batch occupies fewer shares than the individual blobs
+	require.Less(t, SparseSharesNeeded(blob.SequenceLen()), len(items)+SparseSharesNeeded(uint32(1000)))
+
+	unpacked, err := UnpackBlobBatch(blob)
+	require.NoError(t, err)
+	require.Equal(t, items, unpacked)
+}
+
+func TestUnpackBlobBatchInvalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"truncated item":   {5, 1, 2},
+		"zero length item": {0},
+		"invalid prefix":   {0xff},
+	} {
+		t.Run(name, func(t *testing.T) {
+			blob, err := NewV0Blob(RandomBlobNamespace(), data)
+			require.NoError(t, err)
+			_, err = UnpackBlobBatch(blob)
+			require.Error(t, err)
+		})
+	}
+}
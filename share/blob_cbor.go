@@ -0,0 +1,209 @@
+package share
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// CBOR major types used to encode blobs.
+const (
+	cborUint   = 0
+	cborBytes  = 2
+	cborText   = 3
+	cborMap    = 5
+	cborMaxLen = math.MaxUint32
+)
+
+// CBOR map keys of an encoded blob. They are listed in the order mandated by
+// the core deterministic encoding rules of RFC 8949 (shorter keys first).
+const (
+	cborKeyData         = "data"
+	cborKeyCodec        = "codec"
+	cborKeySigner       = "signer"
+	cborKeyMetadata     = "metadata"
+	cborKeyNamespace    = "namespace"
+	cborKeyShareVersion = "share_version"
+)
+
+// MarshalCBOR encodes the blob as a CBOR map using the core deterministic
+// encoding of RFC 8949. Optional fields (signer, metadata and codec) are
+// omitted when empty.
+func (b *Blob) MarshalCBOR() ([]byte, error) {
+	if b.IsEmpty() {
+		return nil, errors.New("can not encode an empty blob")
+	}
+	fields := 3
+	for _, present := range []bool{b.codec != 0, len(b.signer) > 0, len(b.metadata) > 0} {
+		if present {
+			fields++
+		}
+	}
+
+	out := appendCBORHead(nil, cborMap, uint64(fields))
+	out = appendCBORText(out, cborKeyData)
+	out = appendCBORBytes(out, b.data)
+	if b.codec != 0 {
+		out = appendCBORText(out, cborKeyCodec)
+		out = appendCBORHead(out, cborUint, uint64(b.codec))
+	}
+	if len(b.signer) > 0 {
+		out = appendCBORText(out, cborKeySigner)
+		out = appendCBORBytes(out, b.signer)
+	}
+	if len(b.metadata) > 0 {
+		out = appendCBORText(out, cborKeyMetadata)
+		out = appendCBORBytes(out, b.metadata)
+	}
+	out = appendCBORText(out, cborKeyNamespace)
+	out = appendCBORBytes(out, b.namespace.Bytes())
+	out = appendCBORText(out, cborKeyShareVersion)
+	out = appendCBORHead(out, cborUint, uint64(b.shareVersion))
+	return out, nil
+}
+
+// UnmarshalCBOR decodes a blob encoded with MarshalCBOR. Fields may appear in
+// any order but unknown and duplicate fields are rejected.
+func (b *Blob) UnmarshalCBOR(data []byte) error {
+	r := &cborReader{data: data}
+	fields, err := r.readHead(cborMap)
+	if err != nil {
+		return err
+	}
+	var (
+		nsBytes, blobData, signer, metadata []byte
+		shareVersion, codec                 uint64
+		seen                                = make(map[string]bool)
+	)
+	for i := uint64(0); i < fields; i++ {
+		key, err := r.readBytes(cborText)
+		if err != nil {
+			return err
+		}
+		if seen[string(key)] {
+			return fmt.Errorf("duplicate field %q", key)
+		}
+		seen[string(key)] = true
+		switch string(key) {
+		case cborKeyData:
+			blobData, err = r.readBytes(cborBytes)
+		case cborKeyCodec:
+			codec, err = r.readHead(cborUint)
+		case cborKeySigner:
+			signer, err = r.readBytes(cborBytes)
+		case cborKeyMetadata:
+			metadata, err = r.readBytes(cborBytes)
+		case cborKeyNamespace:
+			nsBytes, err = r.readBytes(cborBytes)
+		case cborKeyShareVersion:
+			shareVersion, err = r.readHead(cborUint)
+		default:
+			return fmt.Errorf("unknown field %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("decoding field %q: %w", key, err)
+		}
+	}
+	if len(r.data) != r.offset {
+		return fmt.Errorf("%d trailing bytes after the encoded blob", len(r.data)-r.offset)
+	}
+	if shareVersion > uint64(MaxShareVersion) {
+		return fmt.Errorf("share version can not be greater than MaxShareVersion %d", MaxShareVersion)
+	}
+	if codec > math.MaxUint8 {
+		return errors.New("codec can not be greater than MaxUint8")
+	}
+	ns, err := NewNamespaceFromBytes(nsBytes)
+	if err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	blob, err := newBlob(ns, blobData, uint8(shareVersion), signer, metadata, uint8(codec))
+	if err != nil {
+		return err
+	}
+	*b = *blob
+	return nil
+}
+
+func appendCBORHead(out []byte, majorType byte, value uint64) []byte {
+	major := majorType << 5
+	switch {
+	case value < 24:
+		return append(out, major|byte(value))
+	case value <= math.MaxUint8:
+		return append(out, major|24, byte(value))
+	case value <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(out, major|25), uint16(value))
+	case value <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(out, major|26), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(out, major|27), value)
+	}
+}
+
+func appendCBORBytes(out []byte, data []byte) []byte {
+	return append(appendCBORHead(out, cborBytes, uint64(len(data))), data...)
+}
+
+func appendCBORText(out []byte, text string) []byte {
+	return append(appendCBORHead(out, cborText, uint64(len(text))), text...)
+}
+
+type cborReader struct {
+	data   []byte
+	offset int
+}
+
+// readHead reads the head of a data item of the expected major type and
+// returns its argument. Indefinite lengths are not supported.
+func (r *cborReader) readHead(majorType byte) (uint64, error) {
+	if r.offset >= len(r.data) {
+		return 0, errors.New("unexpected end of CBOR data")
+	}
+	initial := r.data[r.offset]
+	r.offset++
+	if initial>>5 != majorType {
+		return 0, fmt.Errorf("expected CBOR major type %d, got %d", majorType, initial>>5)
+	}
+	info := initial & 0x1f
+	if info < 24 {
+		return uint64(info), nil
+	}
+	if info > 27 {
+		return 0, fmt.Errorf("unsupported CBOR additional information %d", info)
+	}
+	size := 1 << (info - 24)
+	if len(r.data)-r.offset < size {
+		return 0, errors.New("unexpected end of CBOR data")
+	}
+	raw := r.data[r.offset : r.offset+size]
+	r.offset += size
+	switch size {
+	case 1:
+		return uint64(raw[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(raw)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(raw)), nil
+	default:
+		return binary.BigEndian.Uint64(raw), nil
+	}
+}
+
+// readBytes reads a byte or text string of the expected major type.
+func (r *cborReader) readBytes(majorType byte) ([]byte, error) {
+	length, err := r.readHead(majorType)
+	if err != nil {
+		return nil, err
+	}
+	if length > cborMaxLen || length > uint64(len(r.data)-r.offset) {
+		return nil, errors.New("unexpected end of CBOR data")
+	}
+	value := r.data[r.offset : r.offset+int(length)]
+	r.offset += int(length)
+	if len(value) == 0 {
+		return nil, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,75 @@
+package share
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encodingTestBlobs(t *testing.T) []*Blob {
+	ns := MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize))
+	v0, err := NewV0Blob(ns, []byte("data"))
+	require.NoError(t, err)
+	v1, err := NewV1Blob(ns, bytes.Repeat([]byte{2}, 300), bytes.Repeat([]byte{1}, SignerSize))
+	require.NoError(t, err)
+	v3, err := NewV3Blob(ns, bytes.Repeat([]byte{3}, 70_000), []byte("text/plain"))
+	require.NoError(t, err)
+	v4, err := NewCompressedBlob(ns, bytes.Repeat([]byte{4}, 1000), CodecFlate)
+	require.NoError(t, err)
+	return []*Blob{v0, v1, v3, v4}
+}
+
+func TestBlobCBOR(t *testing.T) {
+	for _, blob := range encodingTestBlobs(t) {
+		encoded, err := blob.MarshalCBOR()
+		require.NoError(t, err)
+		decoded := &Blob{}
+		require.NoError(t, decoded.UnmarshalCBOR(encoded))
+		require.Equal(t, blob, decoded)
+	}
+
+	blob := encodingTestBlobs(t)[0]
+	encoded, err := blob.MarshalCBOR()
+	require.NoError(t, err)
+	// a map of 3 fields starting with the 4 byte text key "data"
+	require.Equal(t, []byte{0xa3, 0x64, 'd', 'a', 't', 'a', 0x44, 'd', 'a', 't', 'a'}, encoded[:11])
+
+	decoded := &Blob{}
+	require.Error(t, decoded.UnmarshalCBOR(encoded[:len(encoded)-1]))
+	require.Error(t, decoded.UnmarshalCBOR(append(encoded, 0)))
+	require.Error(t, decoded.UnmarshalCBOR([]byte{0xa1, 0x63, 'f', 'o', 'o', 0x00}))
+	require.Error(t, decoded.UnmarshalCBOR([]byte{0x80}))
+
+	_, err = (&Blob{}).MarshalCBOR()
+	require.Error(t, err)
+}
+
+func TestBlobSSZ(t *testing.T) {
+	for _, blob := range encodingTestBlobs(t) {
+		encoded, err := blob.MarshalSSZ()
+		require.NoError(t, err)
+		require.Len(t, encoded, blob.SizeSSZ())
+		decoded := &Blob{}
+		require.NoError(t, decoded.UnmarshalSSZ(encoded))
+		require.Equal(t, blob, decoded)
+	}
+
+	blob := encodingTestBlobs(t)[1]
+	encoded, err := blob.MarshalSSZ()
+	require.NoError(t, err)
+	require.Equal(t, blob.Namespace().Bytes(), encoded[:NamespaceSize])
+	require.Equal(t, []byte{ShareVersionOne, 0}, encoded[NamespaceSize:NamespaceSize+2])
+
+	decoded := &Blob{}
+	require.Error(t, decoded.UnmarshalSSZ(encoded[:blobSSZFixedSize-1]))
+	corrupted := bytes.Clone(encoded)
+	corrupted[NamespaceSize+2] = 0
+	require.Error(t, decoded.UnmarshalSSZ(corrupted))
+	corrupted = bytes.Clone(encoded)
+	corrupted[NamespaceSize+2+sszOffsetSize] = 0xff
+	require.Error(t, decoded.UnmarshalSSZ(corrupted))
+
+	_, err = (&Blob{}).MarshalSSZ()
+	require.Error(t, err)
+}
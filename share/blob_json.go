@@ -0,0 +1,118 @@
+package share
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
+)
+
+// blobJSON is the canonical JSON representation of a blob:
+//
+//	{
+//	  "namespace":    hex encoded 29 byte namespace,
+//	  "data":         base64url (unpadded) encoded data,
+//	  "shareVersion": share version,
+//	  "signer":       base64url (unpadded) encoded signer, omitted if empty,
+//	  "metadata":     base64url (unpadded) encoded metadata, omitted if empty,
+//	  "codec":        codec id, omitted if zero
+//	}
+//
+// The schema is independent of the protobuf definitions and must not change.
+type blobJSON struct {
+	Namespace    string `json:"namespace"`
+	Data         string `json:"data"`
+	ShareVersion uint8  `json:"shareVersion"`
+	Signer       string `json:"signer,omitempty"`
+	Metadata     string `json:"metadata,omitempty"`
+	Codec        uint8  `json:"codec,omitempty"`
+}
+
+// MarshalJSON encodes the blob using its canonical JSON representation.
+func (b *Blob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(blobJSON{
+		Namespace:    hex.EncodeToString(b.namespace.Bytes()),
+		Data:         EncodeJSONBytes(b.data),
+		ShareVersion: b.shareVersion,
+		Signer:       EncodeJSONBytes(b.signer),
+		Metadata:     EncodeJSONBytes(b.metadata),
+		Codec:        b.codec,
+	})
+}
+
+// legacyBlobJSON detects the JSON representation used before the canonical
+// one, which was the default encoding of v1.BlobProto.
+type legacyBlobJSON struct {
+	NamespaceID json.RawMessage `json:"namespace_id"`
+}
+
+// UnmarshalJSON decodes a blob from its canonical JSON representation. Blobs
+// encoded with the legacy protobuf field names, such as "namespace_id" and
+// "share_version", are also accepted.
+func (b *Blob) UnmarshalJSON(bb []byte) error {
+	var legacy legacyBlobJSON
+	if err := json.Unmarshal(bb, &legacy); err != nil {
+		return err
+	}
+	if legacy.NamespaceID != nil {
+		return b.unmarshalLegacyJSON(bb)
+	}
+	var bj blobJSON
+	if err := json.Unmarshal(bb, &bj); err != nil {
+		return err
+	}
+	nsBytes, err := hex.DecodeString(bj.Namespace)
+	if err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	ns, err := NewNamespaceFromBytes(nsBytes)
+	if err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	fields := make([][]byte, 3)
+	for i, field := range []string{bj.Data, bj.Signer, bj.Metadata} {
+		if fields[i], err = DecodeJSONBytes(field); err != nil {
+			return err
+		}
+	}
+	blob, err := newBlob(ns, fields[0], bj.ShareVersion, fields[1], fields[2], bj.Codec)
+	if err != nil {
+		return err
+	}
+	*b = *blob
+	return nil
+}
+
+func (b *Blob) unmarshalLegacyJSON(bb []byte) error {
+	pb := &v1.BlobProto{}
+	if err := json.Unmarshal(bb, pb); err != nil {
+		return err
+	}
+	blob, err := NewBlobFromProto(pb)
+	if err != nil {
+		return err
+	}
+	*b = *blob
+	return nil
+}
+
+// EncodeJSONBytes encodes bytes as unpadded base64url, the encoding used for
+// all byte fields of the canonical JSON representations in go-square.
+func EncodeJSONBytes(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeJSONBytes decodes bytes encoded with EncodeJSONBytes. An empty string
+// decodes to nil.
+func DecodeJSONBytes(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64url encoding: %w", err)
+	}
+	return b, nil
+}
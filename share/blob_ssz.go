@@ -0,0 +1,97 @@
+package share
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// blobSSZFixedSize is the size of the fixed part of an SSZ encoded blob: the
+// namespace, share version, codec and the offsets of the three variable size
+// fields.
+const blobSSZFixedSize = NamespaceSize + 1 + 1 + 3*sszOffsetSize
+
+const sszOffsetSize = 4
+
+// SizeSSZ returns the size of the SSZ encoding of the blob.
+func (b *Blob) SizeSSZ() int {
+	return blobSSZFixedSize + len(b.data) + len(b.signer) + len(b.metadata)
+}
+
+// MarshalSSZ encodes the blob as the following SSZ container:
+//
+//	class Blob(Container):
+//	    namespace: ByteVector[29]
+//	    share_version: uint8
+//	    codec: uint8
+//	    data: ByteList[2**32 - 1]
+//	    signer: ByteList[20]
+//	    metadata: ByteList[64]
+func (b *Blob) MarshalSSZ() ([]byte, error) {
+	if b.IsEmpty() {
+		return nil, errors.New("can not encode an empty blob")
+	}
+	return b.MarshalSSZTo(make([]byte, 0, b.SizeSSZ()))
+}
+
+// MarshalSSZTo appends the SSZ encoding of the blob to dst.
+func (b *Blob) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if uint64(b.SizeSSZ()) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("blob of %d bytes is too large to encode", len(b.data))
+	}
+	dst = append(dst, b.namespace.Bytes()...)
+	dst = append(dst, b.shareVersion, b.codec)
+	offset := blobSSZFixedSize
+	for _, field := range [][]byte{b.data, b.signer, b.metadata} {
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(offset))
+		offset += len(field)
+	}
+	dst = append(dst, b.data...)
+	dst = append(dst, b.signer...)
+	dst = append(dst, b.metadata...)
+	return dst, nil
+}
+
+// UnmarshalSSZ decodes a blob encoded with MarshalSSZ.
+func (b *Blob) UnmarshalSSZ(data []byte) error {
+	if len(data) < blobSSZFixedSize {
+		return fmt.Errorf("SSZ encoded blob must be at least %d bytes, got %d", blobSSZFixedSize, len(data))
+	}
+	ns, err := NewNamespaceFromBytes(data[:NamespaceSize])
+	if err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	shareVersion, codec := data[NamespaceSize], data[NamespaceSize+1]
+
+	offsets := make([]int, 0, 4)
+	for i := 0; i < 3; i++ {
+		start := NamespaceSize + 2 + i*sszOffsetSize
+		offsets = append(offsets, int(binary.LittleEndian.Uint32(data[start:start+sszOffsetSize])))
+	}
+	offsets = append(offsets, len(data))
+	if offsets[0] != blobSSZFixedSize {
+		return fmt.Errorf("first offset must be %d, got %d", blobSSZFixedSize, offsets[0])
+	}
+	fields := make([][]byte, 3)
+	for i := range fields {
+		if offsets[i] > offsets[i+1] || offsets[i+1] > len(data) {
+			return fmt.Errorf("invalid offset %d", offsets[i+1])
+		}
+		if offsets[i] < offsets[i+1] {
+			fields[i] = data[offsets[i]:offsets[i+1]]
+		}
+	}
+	if len(fields[1]) > SignerSize {
+		return fmt.Errorf("signer can not be larger than %d bytes", SignerSize)
+	}
+	if len(fields[2]) > MaxBlobMetadataSize {
+		return fmt.Errorf("metadata can not be larger than %d bytes", MaxBlobMetadataSize)
+	}
+
+	blob, err := newBlob(ns, fields[0], shareVersion, fields[1], fields[2], codec)
+	if err != nil {
+		return err
+	}
+	*b = *blob
+	return nil
+}
@@ -26,6 +26,19 @@ func TestProtoEncoding(t *testing.T) {
 	require.Equal(t, blob, newBlob)
 }
 
+func TestProtoEncodingWithMetadata(t *testing.T) {
+	blob, err := NewV3Blob(RandomNamespace(), []byte{1, 2, 3, 4, 5}, []byte("application/json"))
+	require.NoError(t, err)
+
+	blobBytes, err := blob.Marshal()
+	require.NoError(t, err)
+
+	newBlob, err := UnmarshalBlob(blobBytes)
+	require.NoError(t, err)
+
+	require.Equal(t, blob, newBlob)
+}
+
 func TestJSONEncoding(t *testing.T) {
 	signer := make([]byte, 20)
 	_, err := rand.Read(signer)
@@ -43,6 +56,29 @@ func TestJSONEncoding(t *testing.T) {
 	require.Equal(t, blob, b)
 }
 
+func TestLegacyJSONEncoding(t *testing.T) {
+	signer := make([]byte, SignerSize)
+	_, err := rand.Read(signer)
+	require.NoError(t, err)
+	blob, err := NewBlob(RandomNamespace(), []byte{1, 2, 3, 4, 5}, 1, signer)
+	require.NoError(t, err)
+
+	// blobs used to be encoded as their protobuf type
+	data, err := json.Marshal(&v1.BlobProto{
+		NamespaceId:      blob.Namespace().ID(),
+		NamespaceVersion: uint32(blob.Namespace().Version()),
+		ShareVersion:     uint32(blob.ShareVersion()),
+		Data:             blob.Data(),
+		Signer:           blob.Signer(),
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"namespace_id"`)
+
+	b := &Blob{}
+	require.NoError(t, json.Unmarshal(data, b))
+	require.Equal(t, blob, b)
+}
+
 func TestBlobConstructor(t *testing.T) {
 	signer := make([]byte, 20)
 	_, err := rand.Read(signer)
@@ -82,6 +118,14 @@ func TestBlobConstructor(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "namespace version must be 0")
 
+	_, err = NewV3Blob(ns, data, bytes.Repeat([]byte{1}, MaxBlobMetadataSize+1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "share version 3 supports metadata of at most")
+
+	_, err = NewBlob(ns, data, 3, signer)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "share version 3 does not support signer")
+
 	blob, err := NewBlob(ns, data, 0, nil)
 	require.NoError(t, err)
 	shares, err := blob.ToShares()
@@ -150,6 +194,28 @@ func TestNewBlobFromProto(t *testing.T) {
 			},
 			expectedErr: "",
 		},
+		{
+			name: "valid blob with metadata",
+			proto: &v1.BlobProto{
+				NamespaceId:      namespace.ID(),
+				NamespaceVersion: 0,
+				ShareVersion:     3,
+				Data:             []byte{1, 2, 3, 4, 5},
+				Metadata:         []byte("application/json"),
+			},
+			expectedErr: "",
+		},
+		{
+			name: "metadata on share version 0",
+			proto: &v1.BlobProto{
+				NamespaceId:      namespace.ID(),
+				NamespaceVersion: 0,
+				ShareVersion:     0,
+				Data:             []byte{1, 2, 3, 4, 5},
+				Metadata:         []byte("application/json"),
+			},
+			expectedErr: "share version 0 does not support metadata",
+		},
 		{
 			name: "invalid signer length",
 			proto: &v1.BlobProto{
@@ -177,6 +243,7 @@ func TestNewBlobFromProto(t *testing.T) {
 				require.Equal(t, uint8(tc.proto.ShareVersion), blob.ShareVersion())
 				require.Equal(t, tc.proto.Data, blob.Data())
 				require.Equal(t, tc.proto.Signer, blob.Signer())
+				require.Equal(t, tc.proto.Metadata, blob.Metadata())
 			}
 		})
 	}
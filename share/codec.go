@@ -0,0 +1,136 @@
+package share
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// CodecFlate is the id of the built-in codec that uses DEFLATE (RFC 1951) at
+// the best compression level.
+const CodecFlate = uint8(1)
+
+// MaxDecompressedDataSize is the maximum size in bytes of the decompressed
+// data of a blob compressed with CodecFlate. It is larger than the largest
+// blob that fits in a 512x512 square and guards against decompression bombs.
+const MaxDecompressedDataSize = 128 << 20
+
+// Codec compresses and decompresses the data of share version 4 blobs.
+// Implementations must be deterministic: compressing the same data must always
+// produce the same output.
+type Codec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[uint8]Codec{
+		CodecFlate: flateCodec{},
+	}
+)
+
+// RegisterCodec registers a codec under the provided id so that it can be used
+// by NewCompressedBlob and Blob.DecompressedData. The id 0 is reserved for
+// uncompressed data and ids can not be registered twice.
+func RegisterCodec(id uint8, codec Codec) error {
+	if id == 0 {
+		return errors.New("codec id 0 is reserved")
+	}
+	if codec == nil {
+		return errors.New("codec can not be nil")
+	}
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	if _, ok := codecs[id]; ok {
+		return fmt.Errorf("codec with id %d is already registered", id)
+	}
+	codecs[id] = codec
+	return nil
+}
+
+// unregisterCodec removes the codec registered under the provided id. It
+// exists so that tests can clean up the codecs they register.
+func unregisterCodec(id uint8) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	delete(codecs, id)
+}
+
+// GetCodec returns the codec registered under the provided id.
+func GetCodec(id uint8) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[id]
+	return codec, ok
+}
+
+// NewCompressedBlob creates a new blob with share version 4 whose data is the
+// provided data compressed using the codec registered under the provided id.
+func NewCompressedBlob(ns Namespace, data []byte, codec uint8) (*Blob, error) {
+	if len(data) == 0 {
+		return nil, errors.New("data can not be empty")
+	}
+	c, ok := GetCodec(codec)
+	if !ok {
+		return nil, fmt.Errorf("codec with id %d is not registered", codec)
+	}
+	compressed, err := c.Compress(data)
+	if err != nil {
+		return nil, fmt.Errorf("compressing data: %w", err)
+	}
+	return newBlob(ns, compressed, ShareVersionFour, nil, nil, codec)
+}
+
+// DecompressedData returns the data of the blob after decompressing it with
+// the codec the blob was compressed with. Blobs that are not compressed
+// return their data unmodified.
+func (b *Blob) DecompressedData() ([]byte, error) {
+	if b.shareVersion != ShareVersionFour {
+		return b.data, nil
+	}
+	c, ok := GetCodec(b.codec)
+	if !ok {
+		return nil, fmt.Errorf("codec with id %d is not registered", b.codec)
+	}
+	return c.Decompress(b.data)
+}
+
+type flateCodec struct{}
+
+func (flateCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (flateCodec) Decompress(data []byte) ([]byte, error) {
+	return decompressFlate(data, MaxDecompressedDataSize)
+}
+
+// decompressFlate inflates data and returns an error if the result exceeds
+// limit bytes.
+func decompressFlate(data []byte, limit int64) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	decompressed, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(decompressed)) > limit {
+		return nil, fmt.Errorf("decompressed data exceeds %d bytes", limit)
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,95 @@
+package share
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingCodec struct{}
+
+func (failingCodec) Compress([]byte) ([]byte, error)   { return nil, errors.New("compress") }
+func (failingCodec) Decompress([]byte) ([]byte, error) { return nil, errors.New("decompress") }
+
+func TestRegisterCodec(t *testing.T) {
+	require.Error(t, RegisterCodec(0, flateCodec{}))
+	require.Error(t, RegisterCodec(CodecFlate, flateCodec{}))
+	require.Error(t, RegisterCodec(200, nil))
+
+	require.NoError(t, RegisterCodec(200, failingCodec{}))
+	t.Cleanup(func() { unregisterCodec(200) })
+	codec, ok := GetCodec(200)
+	require.True(t, ok)
+	require.Equal(t, failingCodec{}, codec)
+
+	_, err := NewCompressedBlob(RandomBlobNamespace(), []byte("data"), 200)
+	require.Error(t, err)
+}
+
+func TestCompressedBlobRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte(`{"height":1,"txs":[]}`), 1000)
+	blob, err := NewCompressedBlob(RandomBlobNamespace(), data, CodecFlate)
+	require.NoError(t, err)
+	require.Equal(t, ShareVersionFour, blob.ShareVersion())
+	require.Equal(t, CodecFlate, blob.Codec())
+	require.Less(t, blob.DataLen(), len(data))
+
+	shares, err := blob.ToShares()
+	require.NoError(t, err)
+	require.Len(t, shares, SparseSharesNeeded(blob.SequenceLen()))
+	require.Less(t, len(shares), SparseSharesNeeded(uint32(len(data))))
+
+	parsedBlobs, err := ParseBlobs(shares)
+	require.NoError(t, err)
+	require.Len(t, parsedBlobs, 1)
+	require.Equal(t, blob, parsedBlobs[0])
+
+	decompressed, err := parsedBlobs[0].DecompressedData()
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+
+	blobBytes, err := blob.Marshal()
+	require.NoError(t, err)
+	unmarshalled, err := UnmarshalBlob(blobBytes)
+	require.NoError(t, err)
+	require.Equal(t, blob, unmarshalled)
+}
+
+func TestDecompressFlateLimit(t *testing.T) {
+	data := make([]byte, 1024)
+	compressed, err := flateCodec{}.Compress(data)
+	require.NoError(t, err)
+
+	decompressed, err := decompressFlate(compressed, int64(len(data)))
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+
+	_, err = decompressFlate(compressed, int64(len(data)-1))
+	require.Error(t, err)
+}
+
+func TestCompressedBlobConstructor(t *testing.T) {
+	ns := RandomBlobNamespace()
+
+	_, err := NewCompressedBlob(ns, nil, CodecFlate)
+	require.Error(t, err)
+
+	_, err = NewCompressedBlob(ns, []byte("data"), 250)
+	require.Error(t, err)
+
+	_, err = newBlob(ns, []byte("data"), ShareVersionFour, nil, nil, 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "share version 4 requires a codec")
+
+	_, err = newBlob(ns, []byte("data"), ShareVersionZero, nil, nil, CodecFlate)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "share version 0 does not support codecs")
+
+	uncompressed, err := NewV0Blob(ns, []byte("data"))
+	require.NoError(t, err)
+	data, err := uncompressed.DecompressedData()
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+}
@@ -24,6 +24,17 @@ const (
 	// It requires that a signer is included in the first share in the sequence.
 	ShareVersionOne = uint8(1)
 
+	// ShareVersionThree is the share version format for blobs carrying inline
+	// metadata. It requires that a metadata header (a length byte followed by
+	// up to MaxBlobMetadataSize bytes) prefixes the data of the sequence. Share
+	// version 2 is reserved.
+	ShareVersionThree = uint8(3)
+
+	// ShareVersionFour is the share version format for compressed blobs. It
+	// requires that the id of the codec used to compress the data prefixes the
+	// data of the sequence.
+	ShareVersionFour = uint8(4)
+
 	// DefaultShareVersion is the defacto share version. Use this if you are
 	// unsure of which version to use.
 	DefaultShareVersion = ShareVersionZero
@@ -65,10 +76,22 @@ const (
 
 	// SignerSize is the size of the signer in bytes.
 	SignerSize = 20
+
+	// BlobMetadataLenBytes is the number of bytes used to encode the length of
+	// the metadata header of a share version 3 blob.
+	BlobMetadataLenBytes = 1
+
+	// MaxBlobMetadataSize is the maximum size of the metadata of a share
+	// version 3 blob in bytes.
+	MaxBlobMetadataSize = 64
+
+	// BlobCodecBytes is the number of bytes used to encode the codec id of a
+	// share version 4 blob.
+	BlobCodecBytes = 1
 )
 
 // SupportedShareVersions is a list of supported share versions.
-var SupportedShareVersions = []uint8{ShareVersionZero, ShareVersionOne}
+var SupportedShareVersions = []uint8{ShareVersionZero, ShareVersionOne, ShareVersionThree, ShareVersionFour}
 
 const (
 	// NamespaceVersionSize is the size of a namespace version in bytes.
@@ -87,6 +110,11 @@ const (
 	// NamespaceVersionZero is the first namespace version.
 	NamespaceVersionZero = uint8(0)
 
+	// NamespaceVersionOne is the second namespace version. It is not enabled by
+	// default and must be explicitly allowed using AllowedNamespaceVersions.
+	// Unlike version 0, the entire namespace ID is available to users.
+	NamespaceVersionOne = uint8(1)
+
 	// NamespaceVersionMax is the max namespace version.
 	NamespaceVersionMax = math.MaxUint8
 
@@ -97,6 +125,10 @@ const (
 	// NamespaceVersionZeroIDSize is the number of bytes available for
 	// user-specified namespace ID in a namespace ID for version 0.
 	NamespaceVersionZeroIDSize = NamespaceIDSize - NamespaceVersionZeroPrefixSize
+
+	// NamespaceVersionOneIDSize is the number of bytes available for
+	// user-specified namespace ID in a namespace ID for version 1.
+	NamespaceVersionOneIDSize = NamespaceIDSize
 )
 
 var (
@@ -1,5 +1,10 @@
 package share
 
+import (
+	"slices"
+	"sort"
+)
+
 type CompactShareCounter struct {
 	lastShares    int
 	lastRemainder int
@@ -18,7 +23,7 @@ func NewCompactShareCounter() *CompactShareCounter {
 // the counter has been increased by.
 func (c *CompactShareCounter) Add(dataLen int) int {
 	// Increment the data len by the varint that will prefix the data.
-	dataLen += delimLen(uint64(dataLen))
+	dataLen += DelimLen(uint64(dataLen))
 
 	// save a copy of the previous state
 	c.lastRemainder = c.remainder
@@ -81,3 +86,82 @@ func (c *CompactShareCounter) Size() int {
 func (c *CompactShareCounter) Remainder() int {
 	return c.remainder
 }
+
+// MultiCounter tracks the compact shares of multiple streams, such as the
+// transactions, intermediate state roots and PFBs of a square, each written
+// to its own namespace. The streams are laid out in ascending namespace order
+// like they are in a square.
+type MultiCounter struct {
+	// namespaces are the namespaces of the streams in ascending order
+	namespaces []Namespace
+	counters   map[string]*CompactShareCounter
+}
+
+// NewMultiCounter returns a counter with an empty stream for each of the
+// namespaces.
+func NewMultiCounter(namespaces ...Namespace) *MultiCounter {
+	m := &MultiCounter{counters: make(map[string]*CompactShareCounter, len(namespaces))}
+	for _, ns := range namespaces {
+		m.counter(ns)
+	}
+	return m
+}
+
+// counter returns the counter of the namespace's stream, creating it if it
+// does not exist.
+func (m *MultiCounter) counter(ns Namespace) *CompactShareCounter {
+	key := string(ns.Bytes())
+	if counter, ok := m.counters[key]; ok {
+		return counter
+	}
+	counter := NewCompactShareCounter()
+	m.counters[key] = counter
+	idx := sort.Search(len(m.namespaces), func(i int) bool { return ns.IsLessThan(m.namespaces[i]) })
+	m.namespaces = slices.Insert(m.namespaces, idx, ns)
+	return counter
+}
+
+// Add adds the length of the data to the stream of the namespace and returns
+// the amount of shares the stream has been increased by.
+func (m *MultiCounter) Add(ns Namespace, dataLen int) int {
+	return m.counter(ns).Add(dataLen)
+}
+
+// Size returns the amount of shares of the namespace's stream.
+func (m *MultiCounter) Size(ns Namespace) int {
+	if counter, ok := m.counters[string(ns.Bytes())]; ok {
+		return counter.Size()
+	}
+	return 0
+}
+
+// Remainder returns the amount of bytes used for data in the last share of
+// the namespace's stream.
+func (m *MultiCounter) Remainder(ns Namespace) int {
+	if counter, ok := m.counters[string(ns.Bytes())]; ok {
+		return counter.Remainder()
+	}
+	return 0
+}
+
+// TotalShares returns the amount of shares of all streams.
+func (m *MultiCounter) TotalShares() int {
+	total := 0
+	for _, counter := range m.counters {
+		total += counter.Size()
+	}
+	return total
+}
+
+// StartOf returns the index of the first share of the namespace's stream,
+// i.e. the amount of shares of all streams with a lesser namespace.
+func (m *MultiCounter) StartOf(ns Namespace) int {
+	start := 0
+	for _, other := range m.namespaces {
+		if !other.IsLessThan(ns) {
+			break
+		}
+		start += m.counters[string(other.Bytes())].Size()
+	}
+	return start
+}
@@ -72,3 +72,29 @@ func newTxs(n int, len int) [][]byte {
 	}
 	return txs
 }
+
+func TestMultiCounter(t *testing.T) {
+	counter := share.NewMultiCounter(share.PayForBlobNamespace, share.TxNamespace)
+	require.Zero(t, counter.TotalShares())
+	require.Zero(t, counter.StartOf(share.PayForBlobNamespace))
+
+	txCounter := share.NewCompactShareCounter()
+	pfbCounter := share.NewCompactShareCounter()
+	for i := 0; i < 10; i++ {
+		require.Equal(t, txCounter.Add(300), counter.Add(share.TxNamespace, 300))
+		require.Equal(t, pfbCounter.Add(200), counter.Add(share.PayForBlobNamespace, 200))
+	}
+	require.Equal(t, txCounter.Size(), counter.Size(share.TxNamespace))
+	require.Equal(t, txCounter.Remainder(), counter.Remainder(share.TxNamespace))
+	require.Equal(t, pfbCounter.Size(), counter.Size(share.PayForBlobNamespace))
+	require.Equal(t, txCounter.Size()+pfbCounter.Size(), counter.TotalShares())
+	require.Zero(t, counter.StartOf(share.TxNamespace))
+	require.Equal(t, txCounter.Size(), counter.StartOf(share.PayForBlobNamespace))
+
+	// streams are added on demand and ordered by namespace
+	isrCounter := share.NewCompactShareCounter()
+	require.Equal(t, isrCounter.Add(1000), counter.Add(share.IntermediateStateRootsNamespace, 1000))
+	require.Equal(t, txCounter.Size()+isrCounter.Size(), counter.StartOf(share.PayForBlobNamespace))
+	require.Equal(t, txCounter.Size(), counter.StartOf(share.IntermediateStateRootsNamespace))
+	require.Zero(t, counter.Size(share.PrimaryReservedPaddingNamespace))
+}
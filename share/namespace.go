@@ -2,6 +2,7 @@ package share
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
@@ -34,11 +35,40 @@ func (n *Namespace) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// NamespaceOption configures which namespaces are accepted when validating.
+type NamespaceOption func(*namespaceConfig)
+
+type namespaceConfig struct {
+	allowedVersions []uint8
+}
+
+// AllowedNamespaceVersions overrides the namespace versions that are accepted.
+// By default, NewNamespace accepts versions 0 and 255 and NewBlob only accepts
+// version 0. This can be used to experiment with namespace versions that are
+// not yet enabled such as NamespaceVersionOne.
+func AllowedNamespaceVersions(versions ...uint8) NamespaceOption {
+	return func(cfg *namespaceConfig) {
+		cfg.allowedVersions = versions
+	}
+}
+
+func newNamespaceConfig(defaultVersions []uint8, opts []NamespaceOption) *namespaceConfig {
+	cfg := &namespaceConfig{allowedVersions: defaultVersions}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
 // NewNamespace validates the provided version and id and returns a new namespace.
 // This should be used for user specified namespaces.
-func NewNamespace(version uint8, id []byte) (Namespace, error) {
+func NewNamespace(version uint8, id []byte, opts ...NamespaceOption) (Namespace, error) {
 	ns := newNamespace(version, id)
-	if err := ns.validate(); err != nil {
+	cfg := newNamespaceConfig([]uint8{NamespaceVersionZero, NamespaceVersionMax}, opts)
+	if err := ns.validateVersionAllowed(cfg.allowedVersions); err != nil {
+		return Namespace{}, err
+	}
+	if err := ns.validateID(); err != nil {
 		return Namespace{}, err
 	}
 	return ns, nil
@@ -163,7 +193,13 @@ func (n Namespace) ValidateForBlob() error {
 
 // validateVersionSupported returns an error if the version is not supported.
 func (n Namespace) validateVersionSupported() error {
-	if n.Version() != NamespaceVersionZero && n.Version() != NamespaceVersionMax {
+	return n.validateVersionAllowed([]uint8{NamespaceVersionZero, NamespaceVersionMax})
+}
+
+// validateVersionAllowed returns an error if the version is not one of the
+// allowed versions.
+func (n Namespace) validateVersionAllowed(allowedVersions []uint8) error {
+	if !slices.Contains(allowedVersions, n.Version()) {
 		return fmt.Errorf("unsupported namespace version %v", n.Version())
 	}
 	return nil
@@ -224,6 +260,10 @@ func (n Namespace) IsTx() bool {
 	return n.Equals(TxNamespace)
 }
 
+func (n Namespace) IsIntermediateStateRoots() bool {
+	return n.Equals(IntermediateStateRootsNamespace)
+}
+
 func (n Namespace) IsPayForBlob() bool {
 	return n.Equals(PayForBlobNamespace)
 }
@@ -306,6 +346,42 @@ func (n Namespace) AddInt(val int) (Namespace, error) {
 	return Namespace{data: result}, nil
 }
 
+// DeriveNamespace deterministically derives a version 0 namespace from the
+// provided seed (e.g. a chain ID) by hashing it. The derived namespace is
+// always valid for blobs: in the unlikely case that the hash maps to a
+// reserved namespace, the hash is rehashed until it does not.
+func DeriveNamespace(seed []byte) Namespace {
+	hash := sha256.Sum256(seed)
+	for {
+		ns := MustNewV0Namespace(hash[:NamespaceVersionZeroIDSize])
+		if ns.ValidateForBlob() == nil {
+			return ns
+		}
+		hash = sha256.Sum256(hash[:])
+	}
+}
+
+// NamespaceRange returns count adjacent namespaces starting at, and including,
+// start. It returns an error if the range overflows or if any namespace in the
+// range is not a valid namespace.
+func NamespaceRange(start Namespace, count int) ([]Namespace, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count %d must not be negative", count)
+	}
+	namespaces := make([]Namespace, count)
+	for i := range namespaces {
+		ns, err := start.AddInt(i)
+		if err != nil {
+			return nil, fmt.Errorf("namespace %d of range: %w", i, err)
+		}
+		if err := ns.validate(); err != nil {
+			return nil, fmt.Errorf("namespace %d of range: %w", i, err)
+		}
+		namespaces[i] = ns
+	}
+	return namespaces, nil
+}
+
 // leftPad returns a new byte slice with the provided byte slice left-padded to the provided size.
 // If the provided byte slice is already larger than the provided size, the original byte slice is returned.
 func leftPad(b []byte, size int) []byte {
@@ -0,0 +1,169 @@
+package share
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NamespaceBech32Prefix is the human-readable part used for the bech32
+// encoding of namespaces.
+const NamespaceBech32Prefix = "ns"
+
+// MarshalText encodes the namespace as a hex string. This allows namespaces to
+// be used in text based formats such as YAML or TOML configs.
+func (n Namespace) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText decodes a namespace from either its hex or bech32 encoding.
+// See ParseNamespace.
+func (n *Namespace) UnmarshalText(text []byte) error {
+	ns, err := ParseNamespace(string(text))
+	if err != nil {
+		return err
+	}
+	*n = ns
+	return nil
+}
+
+// Bech32 returns the checksummed bech32 encoding of the namespace using
+// NamespaceBech32Prefix as the human-readable part.
+func (n Namespace) Bech32() string {
+	data, err := convertBits(n.data, 8, 5, true)
+	if err != nil {
+		// converting from 8 to 5 bits with padding can not fail
+		panic(err)
+	}
+	return bech32Encode(NamespaceBech32Prefix, data)
+}
+
+// ParseNamespace parses a namespace from a string. Both the hex encoding
+// (optionally prefixed with 0x), as returned by Namespace.String, and the
+// bech32 encoding, as returned by Namespace.Bech32, are accepted.
+func ParseNamespace(s string) (Namespace, error) {
+	if strings.HasPrefix(strings.ToLower(s), NamespaceBech32Prefix+"1") {
+		hrp, data, err := bech32Decode(s)
+		if err != nil {
+			return Namespace{}, fmt.Errorf("decoding bech32 namespace: %w", err)
+		}
+		if hrp != NamespaceBech32Prefix {
+			return Namespace{}, fmt.Errorf("invalid bech32 prefix %q, expected %q", hrp, NamespaceBech32Prefix)
+		}
+		nsBytes, err := convertBits(data, 5, 8, false)
+		if err != nil {
+			return Namespace{}, fmt.Errorf("decoding bech32 namespace: %w", err)
+		}
+		return NewNamespaceFromBytes(nsBytes)
+	}
+	nsBytes, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return Namespace{}, fmt.Errorf("decoding hex namespace: %w", err)
+	}
+	return NewNamespaceFromBytes(nsBytes)
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HrpExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func bech32Checksum(hrp string, data []byte) []byte {
+	values := append(bech32HrpExpand(hrp), data...)
+	values = append(values, make([]byte, 6)...)
+	polymod := bech32Polymod(values) ^ 1
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte((polymod >> (5 * (5 - i))) & 31)
+	}
+	return checksum
+}
+
+func bech32Encode(hrp string, data []byte) string {
+	combined := append(data, bech32Checksum(hrp, data)...)
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(combined))
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, b := range combined {
+		sb.WriteByte(bech32Charset[b])
+	}
+	return sb.String()
+}
+
+func bech32Decode(s string) (hrp string, data []byte, err error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errors.New("mixed case string")
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, errors.New("invalid separator position")
+	}
+	hrp = s[:sep]
+	data = make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		idx := strings.IndexByte(bech32Charset, s[i])
+		if idx < 0 {
+			return "", nil, fmt.Errorf("invalid character %q", s[i])
+		}
+		data = append(data, byte(idx))
+	}
+	if bech32Polymod(append(bech32HrpExpand(hrp), data...)) != 1 {
+		return "", nil, errors.New("invalid checksum")
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// convertBits regroups a slice of fromBits-bit values into toBits-bit values.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxv := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data range: %d", value)
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package share
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNamespace(t *testing.T) {
+	ns := RandomBlobNamespace()
+
+	testCases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"hex", ns.String(), false},
+		{"hex with 0x prefix", "0x" + ns.String(), false},
+		{"bech32", ns.Bech32(), false},
+		{"upper case bech32", strings.ToUpper(ns.Bech32()), false},
+		{"invalid hex", "zz", true},
+		{"hex of wrong length", "0001", true},
+		{"bech32 with bad checksum", flipLastChar(ns.Bech32()), true},
+		{"mixed case bech32", "N" + ns.Bech32()[1:], true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseNamespace(tc.input)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, ns, got)
+		})
+	}
+}
+
+func TestNamespaceText(t *testing.T) {
+	ns := RandomBlobNamespace()
+	text, err := ns.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, ns.String(), string(text))
+
+	var decoded Namespace
+	require.NoError(t, decoded.UnmarshalText(text))
+	require.Equal(t, ns, decoded)
+
+	require.NoError(t, decoded.UnmarshalText([]byte(TxNamespace.Bech32())))
+	require.Equal(t, TxNamespace, decoded)
+
+	require.Error(t, decoded.UnmarshalText([]byte("not a namespace")))
+}
+
+func TestBech32(t *testing.T) {
+	// test vectors from BIP-173
+	for _, valid := range []string{
+		"A12UEL5L",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+	} {
+		_, _, err := bech32Decode(valid)
+		require.NoError(t, err, valid)
+	}
+	for _, invalid := range []string{
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"A1G7SGD8",
+		"li1dgmt3",
+	} {
+		_, _, err := bech32Decode(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func flipLastChar(s string) string {
+	last := s[len(s)-1]
+	replacement := byte('q')
+	if last == 'q' {
+		replacement = 'p'
+	}
+	return s[:len(s)-1] + string(replacement)
+}
@@ -0,0 +1,175 @@
+package share
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// NamespaceRegistry maps human-readable aliases to namespaces. It is useful
+// for explorers and command line tools that want to display well-known
+// namespaces by name. A NamespaceRegistry is not safe for concurrent writes.
+type NamespaceRegistry struct {
+	namespaces map[string]Namespace
+	aliases    map[string]string
+}
+
+// NewNamespaceRegistry returns a registry pre-populated with aliases for the
+// reserved namespaces.
+func NewNamespaceRegistry() *NamespaceRegistry {
+	reg := &NamespaceRegistry{
+		namespaces: make(map[string]Namespace),
+		aliases:    make(map[string]string),
+	}
+	for alias, ns := range map[string]Namespace{
+		"tx":                       TxNamespace,
+		"intermediate-state-roots": IntermediateStateRootsNamespace,
+		"pay-for-blob":             PayForBlobNamespace,
+		"primary-reserved-padding": PrimaryReservedPaddingNamespace,
+		"tail-padding":             TailPaddingNamespace,
+		"parity-shares":            ParitySharesNamespace,
+	} {
+		if err := reg.Register(alias, ns); err != nil {
+			panic(err)
+		}
+	}
+	return reg
+}
+
+// Register adds an alias for the provided namespace. Aliases must be unique
+// and a namespace can only have a single alias.
+func (r *NamespaceRegistry) Register(alias string, ns Namespace) error {
+	if err := validateRegistryEntry(alias, ns); err != nil {
+		return err
+	}
+	if _, ok := r.namespaces[alias]; ok {
+		return fmt.Errorf("alias %q is already registered", alias)
+	}
+	if existing, ok := r.aliases[string(ns.Bytes())]; ok {
+		return fmt.Errorf("namespace %s is already registered as %q", ns, existing)
+	}
+	r.namespaces[alias] = ns
+	r.aliases[string(ns.Bytes())] = alias
+	return nil
+}
+
+func validateRegistryEntry(alias string, ns Namespace) error {
+	if alias == "" {
+		return errors.New("alias can not be empty")
+	}
+	if ns.IsEmpty() {
+		return errors.New("namespace can not be empty")
+	}
+	return nil
+}
+
+// Lookup returns the namespace registered under the provided alias.
+func (r *NamespaceRegistry) Lookup(alias string) (Namespace, bool) {
+	ns, ok := r.namespaces[alias]
+	return ns, ok
+}
+
+// Alias returns the alias of the provided namespace.
+func (r *NamespaceRegistry) Alias(ns Namespace) (string, bool) {
+	alias, ok := r.aliases[string(ns.Bytes())]
+	return alias, ok
+}
+
+// Aliases returns all registered aliases in lexicographical order.
+func (r *NamespaceRegistry) Aliases() []string {
+	aliases := make([]string, 0, len(r.namespaces))
+	for alias := range r.namespaces {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// MarshalJSON encodes the registry as a JSON object mapping each alias to the
+// hex encoding of its namespace.
+func (r *NamespaceRegistry) MarshalJSON() ([]byte, error) {
+	entries := make(map[string]string, len(r.namespaces))
+	for alias, ns := range r.namespaces {
+		entries[alias] = ns.String()
+	}
+	return json.Marshal(entries)
+}
+
+// LoadJSON registers all aliases contained in a JSON object mapping aliases to
+// hex encoded namespaces, such as the output of MarshalJSON. Aliases that are
+// already registered for the same namespace are skipped, so the output of
+// MarshalJSON can be loaded into a registry returned by NewNamespaceRegistry.
+// All entries are validated before any is registered: if an error is
+// returned, the registry is left unchanged.
+func (r *NamespaceRegistry) LoadJSON(data []byte) error {
+	entries := make(map[string]string)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	// validate in a deterministic order so that errors are reproducible
+	aliases := make([]string, 0, len(entries))
+	for alias := range entries {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	pending := make(map[string]Namespace, len(entries))
+	pendingAliases := make(map[string]string, len(entries))
+	for _, alias := range aliases {
+		ns, err := parseRegistryNamespace(alias, entries[alias])
+		if err != nil {
+			return err
+		}
+		if err := validateRegistryEntry(alias, ns); err != nil {
+			return err
+		}
+		if existing, ok := r.namespaces[alias]; ok {
+			if existing.Equals(ns) {
+				continue
+			}
+			return fmt.Errorf("alias %q is already registered", alias)
+		}
+		key := string(ns.Bytes())
+		if existing, ok := r.aliases[key]; ok {
+			return fmt.Errorf("namespace %s is already registered as %q", ns, existing)
+		}
+		if existing, ok := pendingAliases[key]; ok {
+			return fmt.Errorf("namespace %s is listed as both %q and %q", ns, existing, alias)
+		}
+		pending[alias] = ns
+		pendingAliases[key] = alias
+	}
+
+	for alias, ns := range pending {
+		r.namespaces[alias] = ns
+	}
+	for key, alias := range pendingAliases {
+		r.aliases[key] = alias
+	}
+	return nil
+}
+
+func parseRegistryNamespace(alias, hexNs string) (Namespace, error) {
+	nsBytes, err := hex.DecodeString(hexNs)
+	if err != nil {
+		return Namespace{}, fmt.Errorf("decoding namespace of alias %q: %w", alias, err)
+	}
+	ns, err := NewNamespaceFromBytes(nsBytes)
+	if err != nil {
+		return Namespace{}, fmt.Errorf("invalid namespace of alias %q: %w", alias, err)
+	}
+	return ns, nil
+}
+
+// Pretty returns the alias of the namespace if it is registered in the
+// provided registry and falls back to the hex encoding otherwise.
+func (n Namespace) Pretty(reg *NamespaceRegistry) string {
+	if reg != nil {
+		if alias, ok := reg.Alias(n); ok {
+			return alias
+		}
+	}
+	return n.String()
+}
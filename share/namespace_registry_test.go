@@ -0,0 +1,80 @@
+package share
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceRegistry(t *testing.T) {
+	reg := NewNamespaceRegistry()
+	require.Equal(t, "tx", TxNamespace.Pretty(reg))
+	require.Equal(t, "pay-for-blob", PayForBlobNamespace.Pretty(reg))
+
+	rollup := MustNewV0Namespace([]byte("rollup"))
+	require.Equal(t, rollup.String(), rollup.Pretty(reg))
+	require.Equal(t, rollup.String(), rollup.Pretty(nil))
+
+	require.NoError(t, reg.Register("rollup", rollup))
+	require.Equal(t, "rollup", rollup.Pretty(reg))
+	ns, ok := reg.Lookup("rollup")
+	require.True(t, ok)
+	require.Equal(t, rollup, ns)
+
+	// aliases and namespaces must be unique
+	require.Error(t, reg.Register("rollup", MustNewV0Namespace([]byte("other"))))
+	require.Error(t, reg.Register("other", rollup))
+	require.Error(t, reg.Register("", MustNewV0Namespace([]byte("other"))))
+	require.Error(t, reg.Register("other", Namespace{}))
+}
+
+func TestNamespaceRegistryJSON(t *testing.T) {
+	reg := NewNamespaceRegistry()
+	rollup := MustNewV0Namespace([]byte("rollup"))
+	require.NoError(t, reg.Register("rollup", rollup))
+
+	data, err := json.Marshal(reg)
+	require.NoError(t, err)
+
+	// the reserved aliases of a new registry are already registered for the
+	// same namespaces and are skipped
+	loaded := NewNamespaceRegistry()
+	require.NoError(t, loaded.LoadJSON(data))
+	require.Equal(t, reg.Aliases(), loaded.Aliases())
+	require.Equal(t, "rollup", rollup.Pretty(loaded))
+	require.Equal(t, "tx", TxNamespace.Pretty(loaded))
+
+	// loading the same entries again is a no-op
+	require.NoError(t, loaded.LoadJSON(data))
+	require.Equal(t, reg.Aliases(), loaded.Aliases())
+
+	require.Error(t, loaded.LoadJSON([]byte(`{"bad": "zz"}`)))
+	require.Error(t, loaded.LoadJSON([]byte(`{"short": "0001"}`)))
+	require.Error(t, loaded.LoadJSON([]byte(`{"rollup": "`+MustNewV0Namespace([]byte("other")).String()+`"}`)))
+	require.Error(t, loaded.LoadJSON([]byte(`{"other": "`+rollup.String()+`"}`)))
+}
+
+func TestNamespaceRegistryLoadJSONAtomic(t *testing.T) {
+	reg := NewNamespaceRegistry()
+	aliases := reg.Aliases()
+	first := MustNewV0Namespace([]byte("first"))
+	second := MustNewV0Namespace([]byte("second"))
+
+	// "a-first" is valid but "b-invalid" is not, so neither is registered
+	data := []byte(`{"a-first": "` + first.String() + `", "b-invalid": "zz"}`)
+	require.Error(t, reg.LoadJSON(data))
+	require.Equal(t, aliases, reg.Aliases())
+	_, ok := reg.Alias(first)
+	require.False(t, ok)
+
+	// the same namespace can not be listed under two aliases
+	data = []byte(`{"a-first": "` + first.String() + `", "b-first": "` + first.String() + `"}`)
+	require.Error(t, reg.LoadJSON(data))
+	require.Equal(t, aliases, reg.Aliases())
+
+	data = []byte(`{"a-first": "` + first.String() + `", "b-second": "` + second.String() + `"}`)
+	require.NoError(t, reg.LoadJSON(data))
+	require.Equal(t, "a-first", first.Pretty(reg))
+	require.Equal(t, "b-second", second.Pretty(reg))
+}
@@ -362,6 +362,37 @@ func TestMarshalNamespace(t *testing.T) {
 	require.Equal(t, ns, newNs)
 }
 
+func TestDeriveNamespace(t *testing.T) {
+	ns := DeriveNamespace([]byte("celestia"))
+	require.NoError(t, ns.ValidateForBlob())
+	require.Equal(t, NamespaceVersionZero, ns.Version())
+	require.Equal(t, ns, DeriveNamespace([]byte("celestia")))
+	require.NotEqual(t, ns, DeriveNamespace([]byte("mocha-4")))
+}
+
+func TestNamespaceRange(t *testing.T) {
+	start := MustNewV0Namespace([]byte{1, 0xFE})
+	namespaces, err := NamespaceRange(start, 3)
+	require.NoError(t, err)
+	require.Equal(t, []Namespace{
+		start,
+		MustNewV0Namespace([]byte{1, 0xFF}),
+		MustNewV0Namespace([]byte{2, 0x00}),
+	}, namespaces)
+
+	namespaces, err = NamespaceRange(start, 0)
+	require.NoError(t, err)
+	require.Empty(t, namespaces)
+
+	_, err = NamespaceRange(start, -1)
+	require.Error(t, err)
+
+	// the range can not leave the version 0 ID space
+	last := MustNewV0Namespace(bytes.Repeat([]byte{0xFF}, NamespaceVersionZeroIDSize))
+	_, err = NamespaceRange(last, 2)
+	require.Error(t, err)
+}
+
 func BenchmarkEqual(b *testing.B) {
 	n1 := RandomNamespace()
 	n2 := RandomNamespace()
@@ -397,3 +428,42 @@ func BenchmarkCompare(b *testing.B) {
 		}
 	}
 }
+
+func TestNamespaceVersionOne(t *testing.T) {
+	id := bytes.Repeat([]byte{0xab}, NamespaceVersionOneIDSize)
+
+	_, err := NewNamespace(NamespaceVersionOne, id)
+	require.Error(t, err)
+
+	ns, err := NewNamespace(NamespaceVersionOne, id, AllowedNamespaceVersions(NamespaceVersionZero, NamespaceVersionOne))
+	require.NoError(t, err)
+	require.Equal(t, NamespaceVersionOne, ns.Version())
+	require.Equal(t, id, ns.ID())
+
+	// the version 0 prefix rules still apply when version 1 is allowed
+	_, err = NewNamespace(NamespaceVersionZero, id, AllowedNamespaceVersions(NamespaceVersionZero, NamespaceVersionOne))
+	require.Error(t, err)
+
+	_, err = NewBlob(ns, []byte("data"), ShareVersionZero, nil)
+	require.Error(t, err)
+
+	blob, err := NewBlob(ns, []byte("data"), ShareVersionZero, nil, AllowedNamespaceVersions(NamespaceVersionOne))
+	require.NoError(t, err)
+	shares, err := blob.ToShares()
+	require.NoError(t, err)
+	require.Equal(t, ns, shares[0].Namespace())
+
+	_, err = ParseBlobs(shares)
+	require.Error(t, err)
+	parsed, err := ParseBlobs(shares, AllowedNamespaceVersions(NamespaceVersionOne))
+	require.NoError(t, err)
+	require.Equal(t, []*Blob{blob}, parsed)
+
+	encoded, err := blob.Marshal()
+	require.NoError(t, err)
+	_, err = UnmarshalBlob(encoded)
+	require.Error(t, err)
+	decoded, err := UnmarshalBlob(encoded, AllowedNamespaceVersions(NamespaceVersionOne))
+	require.NoError(t, err)
+	require.Equal(t, blob, decoded)
+}
@@ -2,15 +2,27 @@ package share
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 )
 
 // NamespacePaddingShare returns a share that acts as padding. Namespace padding
 // shares follow a blob so that the next blob may start at an index that
 // conforms to blob share commitment rules. The ns and shareVersion parameters
 // provided should be the namespace and shareVersion of the blob that precedes
-// this padding in the data square.
+// this padding in the data square. An error is returned if shareVersion is not
+// one of the SupportedShareVersions.
 func NamespacePaddingShare(ns Namespace, shareVersion uint8) (Share, error) {
-	b, err := newBuilder(ns, shareVersion, true)
+	return namespacePaddingShare(ns, shareVersion, nil)
+}
+
+// namespacePaddingShare behaves like NamespacePaddingShare but builds the share
+// in buf if it is not nil. See newBuilderWithBuffer.
+func namespacePaddingShare(ns Namespace, shareVersion uint8, buf []byte) (Share, error) {
+	if !slices.Contains(SupportedShareVersions, shareVersion) {
+		return Share{}, fmt.Errorf("unsupported share version: %d", shareVersion)
+	}
+	b, err := newBuilderWithBuffer(ns, shareVersion, true, buf)
 	if err != nil {
 		return Share{}, err
 	}
@@ -82,3 +82,41 @@ func TestTailPaddingShares(t *testing.T) {
 		}
 	})
 }
+
+func TestNamespacePaddingShareVersions(t *testing.T) {
+	signer := bytes.Repeat([]byte{1}, SignerSize)
+	blobs := map[uint8]*Blob{}
+	var err error
+	blobs[ShareVersionZero], err = NewV0Blob(ns1, []byte("data"))
+	require.NoError(t, err)
+	blobs[ShareVersionOne], err = NewV1Blob(ns1, []byte("data"), signer)
+	require.NoError(t, err)
+	blobs[ShareVersionThree], err = NewV3Blob(ns1, []byte("data"), []byte("text/plain"))
+	require.NoError(t, err)
+
+	for version, blob := range blobs {
+		padding, err := NamespacePaddingShare(ns1, version)
+		require.NoError(t, err)
+		assert.Equal(t, version, padding.Version())
+		assert.True(t, padding.IsPadding())
+
+		// the splitter pads with the version of the preceding blob
+		sss := NewSparseShareSplitter()
+		require.NoError(t, sss.Write(blob))
+		require.NoError(t, sss.WriteNamespacePaddingShares(2))
+		require.NoError(t, sss.Write(blob))
+		shares := sss.Export()
+		for _, share := range shares[1:3] {
+			assert.Equal(t, padding, share)
+		}
+		require.NoError(t, ValidateShares(shares))
+		parsed, err := ParseBlobs(shares)
+		require.NoError(t, err)
+		require.Len(t, parsed, 2)
+	}
+
+	_, err = NamespacePaddingShare(ns1, 2)
+	require.Error(t, err)
+	_, err = NamespacePaddingShares(ns1, MaxShareVersion+1, 1)
+	require.Error(t, err)
+}
@@ -0,0 +1,8 @@
+//go:build shareparanoid
+
+package share
+
+// paranoid is set by the shareparanoid build tag. It makes NewShareUnsafe copy
+// the data it wraps to help find callers violating the ownership rules of
+// Share.
+const paranoid = true
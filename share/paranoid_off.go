@@ -0,0 +1,6 @@
+//go:build !shareparanoid
+
+package share
+
+// paranoid is unset unless built with the shareparanoid build tag.
+const paranoid = false
@@ -2,7 +2,10 @@ package share
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"sync"
 )
 
 // ParseTxs collects all of the transactions from the shares provided
@@ -16,9 +19,80 @@ func ParseTxs(shares []Share) ([][]byte, error) {
 	return rawTxs, nil
 }
 
-// ParseBlobs collects all blobs from the shares provided
-func ParseBlobs(shares []Share) ([]*Blob, error) {
-	blobList, err := parseSparseShares(shares)
+// ParseTxsLenient collects all complete transactions from the shares
+// provided. Unlike ParseTxs, it tolerates a truncated trailing transaction,
+// which happens when only a prefix of a namespace's shares was fetched, and
+// returns the number of leftover bytes belonging to it instead.
+func ParseTxsLenient(shares []Share) (txs [][]byte, leftover int, err error) {
+	if len(shares) == 0 {
+		return nil, 0, nil
+	}
+	for _, share := range shares {
+		if share.Version() != ShareVersionZero {
+			return nil, 0, fmt.Errorf("unsupported share version for compact shares %v", share.Version())
+		}
+	}
+	rawData, err := extractRawData(shares)
+	if err != nil {
+		return nil, 0, err
+	}
+	return parseRawDataWithLeftover(rawData)
+}
+
+// ParseTxsUntil collects the transactions from the shares provided until stop
+// returns true for one of them. The returned transactions include the one
+// stop returned true for. Shares are decoded lazily so the shares following
+// the share containing the end of that transaction are never read.
+func ParseTxsUntil(shares []Share, stop func(tx []byte) bool) ([][]byte, error) {
+	txs := make([][]byte, 0)
+	var rawData []byte
+	for i := range shares {
+		if shares[i].Version() != ShareVersionZero {
+			return nil, fmt.Errorf("unsupported share version for compact shares %v", shares[i].Version())
+		}
+		raw := shares[i].RawData()
+		if i == 0 {
+			var err error
+			raw, err = shares[i].RawDataUsingReserved()
+			if err != nil {
+				return nil, err
+			}
+		}
+		rawData = append(rawData, raw...)
+
+		for len(rawData) > 0 {
+			// the rest of raw data ends in the middle of a unit length delimiter
+			if _, n := binary.Uvarint(rawData); n == 0 {
+				break
+			}
+			actualData, txLen, err := parseDelimiter(rawData)
+			if err != nil {
+				return nil, err
+			}
+			// the rest of raw data is padding
+			if txLen == 0 {
+				return txs, nil
+			}
+			// the transaction continues in the next share
+			if txLen > uint64(len(actualData)) {
+				break
+			}
+			tx := actualData[:txLen]
+			rawData = actualData[txLen:]
+			txs = append(txs, tx)
+			if stop(tx) {
+				return txs, nil
+			}
+		}
+	}
+	return txs, nil
+}
+
+// ParseBlobs collects all blobs from the shares provided. Only blobs with
+// version 0 namespaces are accepted unless overridden using
+// AllowedNamespaceVersions.
+func ParseBlobs(shares []Share, opts ...NamespaceOption) ([]*Blob, error) {
+	blobList, err := parseSparseShares(shares, opts...)
 	if err != nil {
 		return []*Blob{}, err
 	}
@@ -26,6 +100,114 @@ func ParseBlobs(shares []Share) ([]*Blob, error) {
 	return blobList, nil
 }
 
+// BlobFromShares reassembles the single blob stored in the shares. It returns
+// an error if the shares do not form exactly one valid sparse share sequence,
+// for example if they contain padding or more than one blob.
+func BlobFromShares(shares []Share, opts ...NamespaceOption) (*Blob, error) {
+	sequences, err := ParseShares(shares, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(sequences) != 1 {
+		return nil, fmt.Errorf("shares contain %d sequences, expected 1", len(sequences))
+	}
+	sequence := sequences[0]
+	if err := sequence.Validate(); err != nil {
+		return nil, err
+	}
+	if sequence.isPadding() {
+		return nil, errors.New("shares contain padding instead of a blob")
+	}
+	if sequence.Shares[0].IsCompactShare() {
+		return nil, fmt.Errorf("shares of namespace %s are compact shares and do not contain a blob", sequence.Namespace)
+	}
+	return sequence.blob(opts...)
+}
+
+// ParseBlobsParallel behaves like ParseBlobs but splits the shares into up to
+// workers partitions, each starting at the beginning of a sequence, and
+// parses them concurrently. The blobs are returned in the order of the
+// shares. If more than one partition fails to parse, the error of the first
+// one is returned.
+func ParseBlobsParallel(shares []Share, workers int, opts ...NamespaceOption) ([]*Blob, error) {
+	partitions := partitionSparseShares(shares, workers)
+	if len(partitions) <= 1 {
+		return ParseBlobs(shares, opts...)
+	}
+
+	results := make([][]*Blob, len(partitions))
+	errs := make([]error, len(partitions))
+	var wg sync.WaitGroup
+	for i, partition := range partitions {
+		wg.Add(1)
+		go func(i int, partition []Share) {
+			defer wg.Done()
+			results[i], errs[i] = parseSparseShares(partition, opts...)
+		}(i, partition)
+	}
+	wg.Wait()
+
+	blobs := make([]*Blob, 0)
+	for i, result := range results {
+		if errs[i] != nil {
+			return []*Blob{}, errs[i]
+		}
+		blobs = append(blobs, result...)
+	}
+	return blobs, nil
+}
+
+// partitionSparseShares splits the shares into at most n partitions of
+// roughly equal size. Every partition but the first starts with a sequence
+// start share so that no blob spans two partitions.
+func partitionSparseShares(shares []Share, n int) [][]Share {
+	if n <= 1 || len(shares) == 0 {
+		return [][]Share{shares}
+	}
+	target := (len(shares) + n - 1) / n
+	partitions := make([][]Share, 0, n)
+	start := 0
+	for start < len(shares) {
+		end := min(start+target, len(shares))
+		for end < len(shares) && !shares[end].IsSequenceStart() {
+			end++
+		}
+		partitions = append(partitions, shares[start:end])
+		start = end
+	}
+	return partitions
+}
+
+// ParseBlobsWithRanges collects all blobs from the shares provided along with
+// the range of shares that each blob was parsed from. Ranges are end exclusive
+// and relative to the provided shares so callers parsing a subset of a square
+// need to offset them by the index of the first share.
+func ParseBlobsWithRanges(shares []Share, opts ...NamespaceOption) ([]*Blob, []Range, error) {
+	blobList, ranges, err := parseSparseSharesWithRanges(shares, opts...)
+	if err != nil {
+		return []*Blob{}, []Range{}, err
+	}
+
+	return blobList, ranges, nil
+}
+
+// ParseSequences parses the shares into the sequences they contain, skipping
+// padding, and validates each of them. The data of each sequence can be
+// retrieved with Sequence.Data regardless of whether it is a compact or sparse
+// sequence.
+func ParseSequences(shares []Share) ([]Sequence, error) {
+	sequences, err := ParseShares(shares, true)
+	if err != nil {
+		return nil, err
+	}
+	for i, sequence := range sequences {
+		if err := sequence.Validate(); err != nil {
+			return nil, fmt.Errorf("sequence %d: %w", i, err)
+		}
+	}
+	return sequences, nil
+}
+
 // ParseShares parses the shares provided and returns a list of Sequences.
 // If ignorePadding is true then the returned Sequences will not contain
 // any padding sequences.
@@ -1,6 +1,9 @@
 package share
 
-import "fmt"
+import (
+	"encoding/binary"
+	"fmt"
+)
 
 // parseCompactShares returns data (transactions or intermediate state roots
 // based on the contents of rawShares and supportedShareVersions. If rawShares
@@ -36,20 +39,32 @@ func parseCompactShares(shares []Share) (data [][]byte, err error) {
 // state roots) contained in raw data by parsing the unit length delimiter
 // prefixed to each unit.
 func parseRawData(rawData []byte) (units [][]byte, err error) {
+	units, _, err = parseRawDataWithLeftover(rawData)
+	return units, err
+}
+
+// parseRawDataWithLeftover behaves like parseRawData but additionally returns
+// the number of trailing bytes that belong to a unit that is only partially
+// contained in raw data. Leftover bytes include the unit length delimiter.
+func parseRawDataWithLeftover(rawData []byte) (units [][]byte, leftover int, err error) {
 	units = make([][]byte, 0)
 	for {
+		// the rest of raw data ends in the middle of a unit length delimiter
+		if _, n := binary.Uvarint(rawData); n == 0 && len(rawData) > 0 {
+			return units, len(rawData), nil
+		}
 		actualData, unitLen, err := parseDelimiter(rawData)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		// the rest of raw data is padding
 		if unitLen == 0 {
-			return units, nil
+			return units, 0, nil
 		}
 		// the rest of actual data contains only part of the next transaction so
 		// we stop parsing raw data
 		if unitLen > uint64(len(actualData)) {
-			return units, nil
+			return units, len(rawData), nil
 		}
 		rawData = actualData[unitLen:]
 		units = append(units, actualData[:unitLen])
@@ -2,6 +2,7 @@ package share
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 )
 
@@ -11,21 +12,31 @@ type sequence struct {
 	data         []byte
 	sequenceLen  uint32
 	signer       []byte
+	// shareRange is the range of shares, relative to the shares being
+	// parsed, that this sequence occupies.
+	shareRange Range
 }
 
 // parseSparseShares iterates through rawShares and parses out individual
 // blobs. It returns an error if a rawShare contains a share version that
 // isn't present in supportedShareVersions.
-func parseSparseShares(shares []Share) (blobs []*Blob, err error) {
+func parseSparseShares(shares []Share, opts ...NamespaceOption) (blobs []*Blob, err error) {
+	blobs, _, err = parseSparseSharesWithRanges(shares, opts...)
+	return blobs, err
+}
+
+// parseSparseSharesWithRanges behaves like parseSparseShares but additionally
+// returns the range of shares that each blob was parsed from.
+func parseSparseSharesWithRanges(shares []Share, opts ...NamespaceOption) (blobs []*Blob, ranges []Range, err error) {
 	if len(shares) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 	sequences := make([]sequence, 0)
 
-	for _, share := range shares {
+	for idx, share := range shares {
 		version := share.Version()
 		if !bytes.Contains(SupportedShareVersions, []byte{version}) {
-			return nil, fmt.Errorf("unsupported share version %v is not present in supported share versions %v", version, SupportedShareVersions)
+			return nil, nil, fmt.Errorf("unsupported share version %v is not present in supported share versions %v", version, SupportedShareVersions)
 		}
 
 		if share.IsPadding() {
@@ -39,25 +50,76 @@ func parseSparseShares(shares []Share) (blobs []*Blob, err error) {
 				data:         share.RawData(),
 				sequenceLen:  share.SequenceLen(),
 				signer:       GetSigner(share),
+				shareRange:   NewRange(idx, idx+1),
 			})
 		} else { // continuation share
 			if len(sequences) == 0 {
-				return nil, fmt.Errorf("continuation share %v without a sequence start share", share)
+				return nil, nil, fmt.Errorf("continuation share %v without a sequence start share", share)
 			}
 			// FIXME: it doesn't look like we check whether all the shares belong to the same namespace.
 			prev := &sequences[len(sequences)-1]
 			prev.data = append(prev.data, share.RawData()...)
+			prev.shareRange.End = idx + 1
 		}
 	}
 	for _, sequence := range sequences {
 		// trim any padding from the end of the sequence
 		sequence.data = sequence.data[:sequence.sequenceLen]
-		blob, err := NewBlob(sequence.ns, sequence.data, sequence.shareVersion, sequence.signer)
+		var (
+			metadata []byte
+			codec    uint8
+		)
+		switch sequence.shareVersion {
+		case ShareVersionThree:
+			metadata, sequence.data, err = parseBlobMetadata(sequence.data)
+		case ShareVersionFour:
+			codec, sequence.data, err = parseBlobCodec(sequence.data)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		blob, err := newBlob(sequence.ns, sequence.data, sequence.shareVersion, sequence.signer, metadata, codec, opts...)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		blobs = append(blobs, blob)
+		ranges = append(ranges, sequence.shareRange)
+	}
+
+	return blobs, ranges, nil
+}
+
+// marshalBlobMetadata prefixes the metadata of a v3 blob with its length.
+func marshalBlobMetadata(metadata []byte) []byte {
+	header := make([]byte, 0, BlobMetadataLenBytes+len(metadata))
+	header = append(header, byte(len(metadata)))
+	return append(header, metadata...)
+}
+
+// parseBlobMetadata splits the raw data of a v3 blob sequence into the
+// metadata header and the data of the blob.
+func parseBlobMetadata(rawData []byte) (metadata, data []byte, err error) {
+	if len(rawData) < BlobMetadataLenBytes {
+		return nil, nil, errors.New("sequence is too short to contain blob metadata")
+	}
+	metadataLen := int(rawData[0])
+	if metadataLen > MaxBlobMetadataSize {
+		return nil, nil, fmt.Errorf("blob metadata of %d bytes exceeds max size %d", metadataLen, MaxBlobMetadataSize)
 	}
+	if len(rawData) < BlobMetadataLenBytes+metadataLen {
+		return nil, nil, fmt.Errorf("sequence is too short to contain %d bytes of blob metadata", metadataLen)
+	}
+	if metadataLen > 0 {
+		metadata = rawData[BlobMetadataLenBytes : BlobMetadataLenBytes+metadataLen]
+	}
+	return metadata, rawData[BlobMetadataLenBytes+metadataLen:], nil
+}
 
-	return blobs, nil
+// parseBlobCodec splits the raw data of a v4 blob sequence into the codec id
+// and the compressed data of the blob.
+func parseBlobCodec(rawData []byte) (codec uint8, data []byte, err error) {
+	if len(rawData) < BlobCodecBytes {
+		return 0, nil, errors.New("sequence is too short to contain a blob codec")
+	}
+	return rawData[0], rawData[BlobCodecBytes:], nil
 }
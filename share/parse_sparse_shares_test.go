@@ -142,6 +142,38 @@ func Test_parseSparseSharesWithNamespacedPadding(t *testing.T) {
 	require.Equal(t, blobs, pblobs)
 }
 
+func TestParseBlobsWithRanges(t *testing.T) {
+	sss := NewSparseShareSplitter()
+	blobs := []*Blob{
+		generateRandomBlob(ContinuationSparseShareContentSize / 2),
+		generateRandomBlob(ContinuationSparseShareContentSize * 4),
+	}
+	SortBlobs(blobs)
+
+	require.NoError(t, sss.Write(blobs[0]))
+	require.NoError(t, sss.WriteNamespacePaddingShares(4))
+	require.NoError(t, sss.Write(blobs[1]))
+	require.NoError(t, sss.WriteNamespacePaddingShares(2))
+
+	shares := sss.Export()
+	firstLen := SparseSharesNeeded(blobs[0].SequenceLen())
+	secondLen := SparseSharesNeeded(blobs[1].SequenceLen())
+	want := []Range{
+		NewRange(0, firstLen),
+		NewRange(firstLen+4, firstLen+4+secondLen),
+	}
+
+	parsedBlobs, ranges, err := ParseBlobsWithRanges(shares)
+	require.NoError(t, err)
+	require.Equal(t, blobs, parsedBlobs)
+	require.Equal(t, want, ranges)
+	for i, r := range ranges {
+		reparsed, err := ParseBlobs(shares[r.Start:r.End])
+		require.NoError(t, err)
+		require.Equal(t, []*Blob{blobs[i]}, reparsed)
+	}
+}
+
 func Test_parseShareVersionOne(t *testing.T) {
 	v1blob, err := NewV1Blob(MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize)), []byte("data"), bytes.Repeat([]byte{1}, SignerSize))
 	require.NoError(t, err)
@@ -154,6 +186,54 @@ func Test_parseShareVersionOne(t *testing.T) {
 	require.Len(t, parsedBlobs, 1)
 }
 
+func Test_parseShareVersionOneMultipleShares(t *testing.T) {
+	data := make([]byte, 2000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	v1blob, err := NewV1Blob(MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize)), data, bytes.Repeat([]byte{1}, SignerSize))
+	require.NoError(t, err)
+	v1shares, err := splitBlobs(v1blob)
+	require.NoError(t, err)
+	require.Greater(t, len(v1shares), 1)
+
+	// the signer is only included in the first share
+	parsedBlobs, err := parseSparseShares(v1shares)
+	require.NoError(t, err)
+	require.Len(t, parsedBlobs, 1)
+	require.Equal(t, data, parsedBlobs[0].Data())
+}
+
+func Test_parseShareVersionThree(t *testing.T) {
+	ns := MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize))
+	testCases := []struct {
+		name     string
+		dataSize int
+		metadata []byte
+	}{
+		{"small blob", 4, []byte("text/plain")},
+		{"small blob without metadata", 4, nil},
+		{"blob filling the first share", FirstSparseShareContentSize - BlobMetadataLenBytes - MaxBlobMetadataSize, bytes.Repeat([]byte{2}, MaxBlobMetadataSize)},
+		{"blob spilling into a second share", FirstSparseShareContentSize - BlobMetadataLenBytes - MaxBlobMetadataSize + 1, bytes.Repeat([]byte{2}, MaxBlobMetadataSize)},
+		{"large blob", 10_000, []byte{1}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v3blob, err := NewV3Blob(ns, bytes.Repeat([]byte{3}, tc.dataSize), tc.metadata)
+			require.NoError(t, err)
+			v3shares, err := splitBlobs(v3blob)
+			require.NoError(t, err)
+			require.Len(t, v3shares, SparseSharesNeeded(v3blob.SequenceLen()))
+			require.Equal(t, tc.metadata, GetMetadata(v3shares[0]))
+
+			parsedBlobs, err := parseSparseShares(v3shares)
+			require.NoError(t, err)
+			require.Len(t, parsedBlobs, 1)
+			require.Equal(t, v3blob, parsedBlobs[0])
+		})
+	}
+}
+
 func splitBlobs(blobs ...*Blob) ([]Share, error) {
 	writer := NewSparseShareSplitter()
 	for _, blob := range blobs {
@@ -163,3 +243,41 @@ func splitBlobs(blobs ...*Blob) ([]Share, error) {
 	}
 	return writer.Export(), nil
 }
+
+func TestParseBlobsParallel(t *testing.T) {
+	blobs := make([]*Blob, 0, 20)
+	for i := 0; i < 20; i++ {
+		blobs = append(blobs, generateRandomBlob((i%5+1)*ContinuationSparseShareContentSize))
+	}
+	SortBlobs(blobs)
+
+	sss := NewSparseShareSplitter()
+	for _, blob := range blobs {
+		require.NoError(t, sss.Write(blob))
+		require.NoError(t, sss.WriteNamespacePaddingShares(1))
+	}
+	shares := sss.Export()
+
+	for _, workers := range []int{0, 1, 2, 3, 8, len(shares), 2 * len(shares)} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			parsed, err := ParseBlobsParallel(shares, workers)
+			require.NoError(t, err)
+			require.Equal(t, blobs, parsed)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		parsed, err := ParseBlobsParallel(nil, 4)
+		require.NoError(t, err)
+		require.Empty(t, parsed)
+	})
+
+	t.Run("unsupported share version", func(t *testing.T) {
+		invalid := append([]Share{}, shares...)
+		last := invalid[len(invalid)-1].Clone()
+		last.data[NamespaceSize] = 0xff
+		invalid[len(invalid)-1] = last
+		_, err := ParseBlobsParallel(invalid, 4)
+		require.Error(t, err)
+	})
+}
@@ -229,3 +229,116 @@ func generateRandomTxs(count, size int) [][]byte {
 	}
 	return txs
 }
+
+func TestParseTxsLenient(t *testing.T) {
+	txs := generateRandomTxs(3, 600)
+	shares, _, err := splitTxs(txs)
+	require.NoError(t, err)
+
+	parsed, leftover, err := ParseTxsLenient(shares)
+	require.NoError(t, err)
+	assert.Equal(t, txs, parsed)
+	assert.Equal(t, 0, leftover)
+
+	// only the first tx is contained in the first two shares
+	unitLen := len(txs[0]) + DelimLen(uint64(len(txs[0])))
+	parsed, leftover, err = ParseTxsLenient(shares[:2])
+	require.NoError(t, err)
+	assert.Equal(t, txs[:1], parsed)
+	assert.Equal(t, FirstCompactShareContentSize+ContinuationCompactShareContentSize-unitLen, leftover)
+
+	// the prefix ends in the middle of the second tx's delimiter
+	tx := bytes.Repeat([]byte{1}, FirstCompactShareContentSize-3)
+	shares, _, err = splitTxs([][]byte{tx, bytes.Repeat([]byte{2}, 1000)})
+	require.NoError(t, err)
+	parsed, leftover, err = ParseTxsLenient(shares[:1])
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{tx}, parsed)
+	assert.Equal(t, 1, leftover)
+
+	v1blob, err := NewV1Blob(RandomBlobNamespace(), []byte("data"), bytes.Repeat([]byte{1}, SignerSize))
+	require.NoError(t, err)
+	v1shares, err := v1blob.ToShares()
+	require.NoError(t, err)
+	_, _, err = ParseTxsLenient(v1shares)
+	require.Error(t, err)
+}
+
+func TestParseTxsUntil(t *testing.T) {
+	txs := generateRandomTxs(10, 300)
+	shares, _, err := splitTxs(txs)
+	require.NoError(t, err)
+
+	parsed, err := ParseTxsUntil(shares, func([]byte) bool { return false })
+	require.NoError(t, err)
+	assert.Equal(t, txs, parsed)
+
+	for i, want := range txs {
+		parsed, err := ParseTxsUntil(shares, func(tx []byte) bool { return bytes.Equal(tx, want) })
+		require.NoError(t, err)
+		assert.Equal(t, txs[:i+1], parsed)
+	}
+
+	// shares following the one containing the found tx are not decoded
+	v1blob, err := NewV1Blob(RandomBlobNamespace(), []byte("data"), bytes.Repeat([]byte{1}, SignerSize))
+	require.NoError(t, err)
+	v1shares, err := v1blob.ToShares()
+	require.NoError(t, err)
+	invalid := append(append([]Share{}, shares[:1]...), v1shares...)
+	parsed, err = ParseTxsUntil(invalid, func(tx []byte) bool { return bytes.Equal(tx, txs[0]) })
+	require.NoError(t, err)
+	assert.Equal(t, txs[:1], parsed)
+	_, err = ParseTxsUntil(invalid, func([]byte) bool { return false })
+	require.Error(t, err)
+
+	parsed, err = ParseTxsUntil(nil, func([]byte) bool { return true })
+	require.NoError(t, err)
+	assert.Empty(t, parsed)
+}
+
+func TestBlobFromShares(t *testing.T) {
+	ns := MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize))
+	blob := generateRandomBlobWithNamespace(ns, 2000)
+	shares, err := splitBlobs(blob)
+	require.NoError(t, err)
+
+	got, err := BlobFromShares(shares)
+	require.NoError(t, err)
+	assert.Equal(t, blob, got)
+
+	signer := bytes.Repeat([]byte{2}, SignerSize)
+	v1Blob, err := NewV1Blob(ns, bytes.Repeat([]byte{3}, 1000), signer)
+	require.NoError(t, err)
+	v1Shares, err := splitBlobs(v1Blob)
+	require.NoError(t, err)
+	got, err = BlobFromShares(v1Shares)
+	require.NoError(t, err)
+	assert.Equal(t, v1Blob, got)
+
+	t.Run("more than one blob", func(t *testing.T) {
+		_, err := BlobFromShares(append(append([]Share{}, shares...), v1Shares...))
+		require.Error(t, err)
+	})
+	t.Run("missing shares", func(t *testing.T) {
+		_, err := BlobFromShares(shares[:len(shares)-1])
+		require.Error(t, err)
+		_, err = BlobFromShares(shares[1:])
+		require.Error(t, err)
+		_, err = BlobFromShares(nil)
+		require.Error(t, err)
+	})
+	t.Run("padding", func(t *testing.T) {
+		padding, err := NamespacePaddingShare(ns, ShareVersionZero)
+		require.NoError(t, err)
+		_, err = BlobFromShares([]Share{padding})
+		require.Error(t, err)
+		_, err = BlobFromShares(append(append([]Share{}, shares...), padding))
+		require.Error(t, err)
+	})
+	t.Run("compact shares", func(t *testing.T) {
+		txShares, _, err := splitTxs(generateRandomTxs(2, 100))
+		require.NoError(t, err)
+		_, err = BlobFromShares(txShares)
+		require.Error(t, err)
+	})
+}
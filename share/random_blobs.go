@@ -11,7 +11,7 @@ import (
 func GenerateV0Blobs(sizes []int, sameNamespace bool) ([]*Blob, error) {
 	blobs := make([]*Blob, 0, len(sizes))
 	for _, size := range sizes {
-		size := rawTxSize(FirstSparseShareContentSize * size)
+		size := RawTxSize(FirstSparseShareContentSize * size)
 		blob := generateRandomBlob(size)
 		if !sameNamespace {
 			ns := RandomBlobNamespace()
@@ -21,7 +21,7 @@ func RandShares(total int) ([]Share, error) {
 			panic(err)
 		}
 
-		sh, err := NewShare(shr)
+		sh, err := NewShareUnsafe(shr)
 		if err != nil {
 			panic(err)
 		}
@@ -59,7 +59,7 @@ func RandSharesWithNamespace(namespace Namespace, namespacedAmount, total int) (
 			panic(err)
 		}
 
-		sh, err := NewShare(shr)
+		sh, err := NewShareUnsafe(shr)
 		if err != nil {
 			panic(err)
 		}
@@ -1,5 +1,11 @@
 package share
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
 // Range is an end exclusive set of share indexes.
 type Range struct {
 	// Start is the index of the first share occupied by this range.
@@ -25,35 +31,156 @@ func (r *Range) Add(value int) {
 	r.End += value
 }
 
-// GetShareRangeForNamespace returns all shares that belong to a given
-// namespace. It will return an empty range if the namespace could not be
-// found. This assumes that the slice of shares are lexicographically
-// sorted by namespace. Ranges here are always end exclusive.
-func GetShareRangeForNamespace(shares []Share, ns Namespace) Range {
-	if len(shares) == 0 {
+// Len returns the number of shares in the range.
+func (r Range) Len() int {
+	return r.End - r.Start
+}
+
+// Contains returns true if the share index is within the range.
+func (r Range) Contains(index int) bool {
+	return r.Start <= index && index < r.End
+}
+
+// Shift returns a copy of the range with both ends incremented by offset.
+// Unlike Add, it does not modify the range.
+func (r Range) Shift(offset int) Range {
+	return Range{Start: r.Start + offset, End: r.End + offset}
+}
+
+// Intersect returns the shares that are in both ranges. It returns an empty
+// range if the ranges don't overlap.
+func (r Range) Intersect(other Range) Range {
+	start, end := max(r.Start, other.Start), min(r.End, other.End)
+	if start >= end {
 		return EmptyRange()
 	}
-	n0 := shares[0].Namespace()
-	if ns.IsLessThan(n0) {
-		return EmptyRange()
+	return Range{Start: start, End: end}
+}
+
+// Union returns the smallest range covering both ranges. It returns false if
+// the ranges neither overlap nor are adjacent, as their union would then not
+// be a single range.
+func (r Range) Union(other Range) (Range, bool) {
+	if r.Len() <= 0 {
+		return other, true
 	}
-	n1 := shares[len(shares)-1].Namespace()
-	if ns.IsGreaterThan(n1) {
-		return EmptyRange()
+	if other.Len() <= 0 {
+		return r, true
+	}
+	if r.End < other.Start || other.End < r.Start {
+		return EmptyRange(), false
+	}
+	return Range{Start: min(r.Start, other.Start), End: max(r.End, other.End)}, true
+}
+
+// UnmarshalJSON decodes a range and verifies that it is well formed. Field
+// names are matched case insensitively, so both {"Start":0,"End":1} and
+// {"start":0,"end":1} are accepted.
+func (r *Range) UnmarshalJSON(data []byte) error {
+	// use an alias type to avoid recursing into this method
+	type rangeAlias Range
+	var decoded rangeAlias
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Start < 0 || decoded.End < decoded.Start {
+		return fmt.Errorf("invalid range [%d, %d)", decoded.Start, decoded.End)
+	}
+	*r = Range(decoded)
+	return nil
+}
+
+// Ranges is a collection of share ranges.
+type Ranges []Range
+
+// NewRanges returns the normalized collection of the provided ranges.
+func NewRanges(ranges ...Range) Ranges {
+	return Ranges(ranges).Normalize()
+}
+
+// Normalize returns a new collection where ranges are sorted, empty ranges
+// are removed and overlapping or adjacent ranges are merged.
+func (rs Ranges) Normalize() Ranges {
+	sorted := make(Ranges, 0, len(rs))
+	for _, r := range rs {
+		if r.Len() > 0 {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+	normalized := make(Ranges, 0, len(sorted))
+	for _, r := range sorted {
+		last := len(normalized) - 1
+		if last >= 0 {
+			if union, ok := normalized[last].Union(r); ok {
+				normalized[last] = union
+				continue
+			}
+		}
+		normalized = append(normalized, r)
 	}
+	return normalized
+}
 
-	start := -1
-	for i, share := range shares {
-		shareNS := share.Namespace()
-		if shareNS.IsGreaterThan(ns) && start != -1 {
-			return Range{start, i}
+// Contains returns true if the share index is within any of the ranges.
+func (rs Ranges) Contains(index int) bool {
+	for _, r := range rs {
+		if r.Contains(index) {
+			return true
 		}
-		if ns.Equals(shareNS) && start == -1 {
-			start = i
+	}
+	return false
+}
+
+// Len returns the number of shares covered by the ranges. Shares covered by
+// multiple ranges are counted once.
+func (rs Ranges) Len() int {
+	total := 0
+	for _, r := range rs.Normalize() {
+		total += r.Len()
+	}
+	return total
+}
+
+// Intersect returns the normalized shares that are in both collections.
+func (rs Ranges) Intersect(other Ranges) Ranges {
+	var intersection Ranges
+	for _, r := range rs {
+		for _, o := range other {
+			if i := r.Intersect(o); i.Len() > 0 {
+				intersection = append(intersection, i)
+			}
 		}
 	}
-	if start == -1 {
-		return EmptyRange()
+	return intersection.Normalize()
+}
+
+// GetShareRangeForNamespace returns all shares that belong to a given
+// namespace. It will return an empty range if the namespace could not be
+// found. This assumes that the slice of shares are lexicographically
+// sorted by namespace. Ranges here are always end exclusive.
+func GetShareRangeForNamespace(shares []Share, ns Namespace) Range {
+	r, _ := FindNamespaceBounds(shares, ns)
+	return r
+}
+
+// FindNamespaceBounds uses binary search to find the range of shares that
+// belong to a given namespace. It returns false if the namespace could not be
+// found. This assumes that the slice of shares is sorted by namespace, which
+// holds for the whole data square: the reserved namespaces are smaller than
+// all blob namespaces, and tail padding is larger. Ranges here are always end
+// exclusive.
+func FindNamespaceBounds(shares []Share, ns Namespace) (Range, bool) {
+	start := sort.Search(len(shares), func(i int) bool {
+		return shares[i].Namespace().IsGreaterOrEqualThan(ns)
+	})
+	if start == len(shares) || !shares[start].Namespace().Equals(ns) {
+		return EmptyRange(), false
 	}
-	return Range{start, len(shares)}
+	end := start + sort.Search(len(shares)-start, func(i int) bool {
+		return shares[start+i].Namespace().IsGreaterThan(ns)
+	})
+	return Range{Start: start, End: end}, true
 }
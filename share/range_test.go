@@ -1,6 +1,7 @@
 package share_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,3 +63,110 @@ func TestGetShareRangeForNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestRangeArithmetic(t *testing.T) {
+	r := share.NewRange(2, 6)
+	assert.Equal(t, 4, r.Len())
+	assert.True(t, r.Contains(2))
+	assert.True(t, r.Contains(5))
+	assert.False(t, r.Contains(6))
+	assert.Equal(t, share.NewRange(5, 9), r.Shift(3))
+	assert.Equal(t, share.NewRange(2, 6), r, "Shift must not modify the range")
+
+	assert.Equal(t, share.NewRange(4, 6), r.Intersect(share.NewRange(4, 10)))
+	assert.Equal(t, share.NewRange(3, 4), r.Intersect(share.NewRange(3, 4)))
+	assert.True(t, r.Intersect(share.NewRange(6, 10)).IsEmpty())
+
+	union, ok := r.Union(share.NewRange(6, 8))
+	assert.True(t, ok)
+	assert.Equal(t, share.NewRange(2, 8), union)
+	union, ok = r.Union(share.NewRange(0, 3))
+	assert.True(t, ok)
+	assert.Equal(t, share.NewRange(0, 6), union)
+	_, ok = r.Union(share.NewRange(7, 8))
+	assert.False(t, ok)
+	union, ok = r.Union(share.EmptyRange())
+	assert.True(t, ok)
+	assert.Equal(t, r, union)
+}
+
+func TestRanges(t *testing.T) {
+	ranges := share.NewRanges(
+		share.NewRange(10, 12),
+		share.NewRange(0, 2),
+		share.NewRange(1, 4),
+		share.NewRange(4, 5),
+		share.NewRange(7, 7),
+	)
+	assert.Equal(t, share.Ranges{share.NewRange(0, 5), share.NewRange(10, 12)}, ranges)
+	assert.Equal(t, 7, ranges.Len())
+	assert.True(t, ranges.Contains(11))
+	assert.False(t, ranges.Contains(7))
+
+	assert.Equal(t, 3, share.Ranges{share.NewRange(0, 2), share.NewRange(1, 3)}.Len())
+
+	intersection := ranges.Intersect(share.Ranges{share.NewRange(3, 11)})
+	assert.Equal(t, share.Ranges{share.NewRange(3, 5), share.NewRange(10, 11)}, intersection)
+}
+
+func TestRangeJSON(t *testing.T) {
+	data, err := json.Marshal(share.Ranges{share.NewRange(1, 3)})
+	require.NoError(t, err)
+	assert.Equal(t, `[{"Start":1,"End":3}]`, string(data))
+
+	var decoded share.Ranges
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, share.Ranges{share.NewRange(1, 3)}, decoded)
+
+	var lower share.Range
+	require.NoError(t, json.Unmarshal([]byte(`{"start":1,"end":3}`), &lower))
+	assert.Equal(t, share.NewRange(1, 3), lower)
+
+	var r share.Range
+	require.Error(t, json.Unmarshal([]byte(`{"start":3,"end":1}`), &r))
+	require.Error(t, json.Unmarshal([]byte(`{"start":-1,"end":1}`), &r))
+}
+
+func TestFindNamespaceBounds(t *testing.T) {
+	blobs := test.GenerateBlobs(100, 2000, 300, 5000)
+	share.SortBlobs(blobs)
+	writer := share.NewSparseShareSplitter()
+	for _, blob := range blobs {
+		require.NoError(t, writer.Write(blob))
+	}
+	txShares, err := share.NewCompactShareSplitter(share.TxNamespace, share.ShareVersionZero).Export()
+	require.NoError(t, err)
+	shares := append(txShares, share.ReservedPaddingShares(3)...)
+	shares = append(shares, writer.Export()...)
+	shares = append(shares, share.TailPaddingShares(5)...)
+
+	namespaces := []share.Namespace{share.PrimaryReservedPaddingNamespace, share.TailPaddingNamespace, share.RandomBlobNamespace(), share.TxNamespace}
+	for _, blob := range blobs {
+		namespaces = append(namespaces, blob.Namespace())
+	}
+	for _, ns := range namespaces {
+		got, found := share.FindNamespaceBounds(shares, ns)
+		expected := linearNamespaceRange(shares, ns)
+		assert.Equal(t, expected, got, ns.String())
+		assert.Equal(t, !expected.IsEmpty(), found, ns.String())
+	}
+}
+
+func linearNamespaceRange(shares []share.Share, ns share.Namespace) share.Range {
+	start := -1
+	for i, sh := range shares {
+		if sh.Namespace().Equals(ns) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 {
+			return share.NewRange(start, i)
+		}
+	}
+	if start == -1 {
+		return share.EmptyRange()
+	}
+	return share.NewRange(start, len(shares))
+}
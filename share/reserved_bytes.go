@@ -2,6 +2,7 @@ package share
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 )
 
@@ -29,3 +30,71 @@ func ParseReservedBytes(reservedBytes []byte) (uint32, error) {
 	}
 	return byteIndex, nil
 }
+
+// CompactShareReservedOffsets decodes the reserved bytes of each of the
+// compact shares, which must form a complete sequence, and returns them. It
+// returns an error if the reserved bytes of a share do not point at the first
+// unit (i.e. transaction) that starts in that share, or are not zero if no
+// unit starts in it. This allows checking claims of malformed compact shares.
+func CompactShareReservedOffsets(shares []Share) ([]int, error) {
+	if len(shares) == 0 {
+		return nil, nil
+	}
+	if !shares[0].IsSequenceStart() {
+		return nil, errors.New("first compact share must be a sequence start")
+	}
+
+	offsets := make([]int, len(shares))
+	// contentStarts records the index of the first byte of content of each
+	// share within the raw data of the sequence
+	contentStarts := make([]int, len(shares))
+	var rawData []byte
+	for i := range shares {
+		sh := &shares[i]
+		if i > 0 && sh.IsSequenceStart() {
+			return nil, fmt.Errorf("share %d starts a new sequence", i)
+		}
+		if !sh.Namespace().Equals(shares[0].Namespace()) {
+			return nil, fmt.Errorf("share %d has namespace %s, expected %s", i, sh.Namespace(), shares[0].Namespace())
+		}
+		reserved, err := sh.ReservedBytes()
+		if err != nil {
+			return nil, fmt.Errorf("share %d: %w", i, err)
+		}
+		offsets[i] = int(reserved)
+		contentStarts[i] = len(rawData)
+		rawData = append(rawData, sh.RawData()...)
+	}
+
+	sequenceLen := int(shares[0].SequenceLen())
+	if sequenceLen > len(rawData) {
+		return nil, fmt.Errorf("sequence length %d exceeds the %d bytes of content of the shares", sequenceLen, len(rawData))
+	}
+
+	// expected holds the offset of the first unit starting in each share
+	expected := make([]int, len(shares))
+	shareIndex := 0
+	for pos := 0; pos < sequenceLen; {
+		for shareIndex+1 < len(shares) && contentStarts[shareIndex+1] <= pos {
+			shareIndex++
+		}
+		if expected[shareIndex] == 0 {
+			expected[shareIndex] = shares[shareIndex].rawDataStartIndex() + pos - contentStarts[shareIndex]
+		}
+		unitLen, n := binary.Uvarint(rawData[pos:sequenceLen])
+		if n <= 0 || unitLen == 0 {
+			return nil, fmt.Errorf("invalid unit length delimiter at byte %d of the sequence", pos)
+		}
+		if unitLen > uint64(sequenceLen-pos-n) {
+			return nil, fmt.Errorf("unit of %d bytes at byte %d exceeds the sequence length %d", unitLen, pos, sequenceLen)
+		}
+		pos += n + int(unitLen)
+	}
+
+	for i := range offsets {
+		if offsets[i] != expected[i] {
+			return nil, fmt.Errorf("share %d has reserved bytes pointing at byte %d but the first unit starts at byte %d", i, offsets[i], expected[i])
+		}
+	}
+	return offsets, nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseReservedBytes(t *testing.T) {
@@ -82,3 +83,49 @@ func TestNewReservedBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestCompactShareReservedOffsets(t *testing.T) {
+	txs := generateRandomTxs(5, 700)
+	shares, _, err := splitTxs(txs)
+	require.NoError(t, err)
+
+	offsets, err := CompactShareReservedOffsets(shares)
+	require.NoError(t, err)
+	require.Len(t, offsets, len(shares))
+	for i, sh := range shares {
+		start := sh.rawDataStartIndex()
+		reserved, err := ParseReservedBytes(sh.ToBytes()[start-ShareReservedBytes : start])
+		require.NoError(t, err)
+		require.Equal(t, int(reserved), offsets[i])
+	}
+	// the first unit starts right after the reserved bytes of the first share
+	require.Equal(t, shares[0].rawDataStartIndex(), offsets[0])
+
+	empty, err := CompactShareReservedOffsets(nil)
+	require.NoError(t, err)
+	require.Empty(t, empty)
+
+	t.Run("reserved bytes not pointing at a unit", func(t *testing.T) {
+		malformed := append([]Share{}, shares...)
+		sh := malformed[1].Clone()
+		start := sh.rawDataStartIndex()
+		reserved, err := NewReservedBytes(uint32(offsets[1] + 1))
+		require.NoError(t, err)
+		copy(sh.data[start-ShareReservedBytes:start], reserved)
+		malformed[1] = sh
+		_, err = CompactShareReservedOffsets(malformed)
+		require.Error(t, err)
+	})
+
+	t.Run("missing sequence start", func(t *testing.T) {
+		_, err := CompactShareReservedOffsets(shares[1:])
+		require.Error(t, err)
+	})
+
+	t.Run("sparse shares", func(t *testing.T) {
+		sparse, err := splitBlobs(generateRandomBlob(100))
+		require.NoError(t, err)
+		_, err = CompactShareReservedOffsets(sparse)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,39 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ToRSMT2D returns the shares in the format expected by rsmt2d when computing
+// the extended data square. Each share is copied as rsmt2d retains and may
+// modify the provided slices.
+func ToRSMT2D(shares []Share) [][]byte {
+	data := make([][]byte, len(shares))
+	for i, share := range shares {
+		data[i] = append([]byte(nil), share.data...)
+	}
+	return data
+}
+
+// FromRSMT2D converts a flattened (extended) data square as returned by rsmt2d
+// back to shares. It returns an error if the number of shares does not form a
+// square or if any share is malformed. Shares are copied.
+func FromRSMT2D(data [][]byte) ([]Share, error) {
+	if len(data) == 0 {
+		return nil, errors.New("no shares provided")
+	}
+	width := int(math.Sqrt(float64(len(data))))
+	if width*width != len(data) {
+		return nil, fmt.Errorf("%d shares do not form a square", len(data))
+	}
+	shares := make([]Share, len(data))
+	for i, d := range data {
+		if err := validateSize(d); err != nil {
+			return nil, fmt.Errorf("share %d: %w", i, err)
+		}
+		shares[i] = Share{data: append([]byte(nil), d...)}
+	}
+	return shares, nil
+}
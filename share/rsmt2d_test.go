@@ -0,0 +1,34 @@
+package share
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSMT2DRoundTrip(t *testing.T) {
+	shares := TailPaddingShares(4)
+	data := ToRSMT2D(shares)
+	require.Len(t, data, 4)
+	// modifying the returned data doesn't affect the shares
+	data[0][0] = 0xAB
+	assert.NotEqual(t, byte(0xAB), shares[0].ToBytes()[0])
+
+	roundTripped, err := FromRSMT2D(ToRSMT2D(shares))
+	require.NoError(t, err)
+	assert.Equal(t, shares, roundTripped)
+}
+
+func TestFromRSMT2DErrors(t *testing.T) {
+	_, err := FromRSMT2D(nil)
+	assert.Error(t, err)
+
+	_, err = FromRSMT2D(ToRSMT2D(TailPaddingShares(3)))
+	assert.Error(t, err)
+
+	data := ToRSMT2D(TailPaddingShares(4))
+	data[2] = data[2][:ShareSize-1]
+	_, err = FromRSMT2D(data)
+	assert.Error(t, err)
+}
@@ -14,6 +14,12 @@ import (
 )
 
 // Share contains the raw share data (including namespace ID).
+//
+// A Share wraps the slice it was created from without copying it, so the
+// caller must not modify that slice for as long as the share is in use.
+// Accessors such as ToBytes return a view into the share's data rather than a
+// copy so the returned slices must not be modified either. Use Clone to obtain
+// a share that can be modified independently.
 type Share struct {
 	data []byte
 }
@@ -43,6 +49,30 @@ func NewShare(data []byte) (*Share, error) {
 	return &Share{data}, nil
 }
 
+// NewShareUnsafe behaves like NewShare but makes explicit that the caller
+// hands ownership of data to the share and must not modify it afterwards.
+// When built with the shareparanoid build tag, the data is copied to help
+// find callers violating this rule.
+func NewShareUnsafe(data []byte) (*Share, error) {
+	if err := validateSize(data); err != nil {
+		return nil, err
+	}
+	if paranoid {
+		data = bytes.Clone(data)
+	}
+	return newShare(data), nil
+}
+
+// newShare wraps data, which must be owned by the share, without validation.
+func newShare(data []byte) *Share {
+	return &Share{data}
+}
+
+// Clone returns a deep copy of the share.
+func (s Share) Clone() Share {
+	return Share{data: bytes.Clone(s.data)}
+}
+
 func validateSize(data []byte) error {
 	if len(data) != ShareSize {
 		return fmt.Errorf("share data must be %d bytes, got %d", ShareSize, len(data))
@@ -85,8 +115,25 @@ func (s *Share) IsSequenceStart() bool {
 // IsCompactShare returns true if this is a compact share.
 func (s Share) IsCompactShare() bool {
 	ns := s.Namespace()
-	isCompact := ns.IsTx() || ns.IsPayForBlob()
-	return isCompact
+	return isCompactShare(ns)
+}
+
+// ReservedBytes returns the index of the byte, within the share, at which
+// the first unit (i.e. transaction) that starts in this compact share begins.
+// It is zero if no unit starts in the share. It returns an error if the share
+// is not a compact share or the reserved bytes are invalid.
+func (s *Share) ReservedBytes() (uint32, error) {
+	if !s.IsCompactShare() {
+		return 0, fmt.Errorf("share of namespace %s is not a compact share", s.Namespace())
+	}
+	if s.Version() != ShareVersionZero {
+		return 0, fmt.Errorf("unsupported share version for compact shares %v", s.Version())
+	}
+	start := NamespaceSize + ShareInfoBytes
+	if s.IsSequenceStart() {
+		start += SequenceLenBytes
+	}
+	return ParseReservedBytes(s.data[start : start+ShareReservedBytes])
 }
 
 // GetSigner returns the signer of the share, if the
@@ -105,6 +152,25 @@ func GetSigner(share Share) []byte {
 	return share.data[startIndex:endIndex]
 }
 
+// GetMetadata returns the metadata of the share, if the
+// share is not of type v3 and is not the first share in a sequence
+// it returns nil
+func GetMetadata(share Share) []byte {
+	infoByte := share.InfoByte()
+	if infoByte.Version() != ShareVersionThree {
+		return nil
+	}
+	if !infoByte.IsSequenceStart() {
+		return nil
+	}
+	startIndex := NamespaceSize + ShareInfoBytes + SequenceLenBytes
+	metadata, _, err := parseBlobMetadata(share.data[startIndex:])
+	if err != nil {
+		return nil
+	}
+	return metadata
+}
+
 // SequenceLen returns the sequence length of this share.
 // It returns 0 if this is a continuation share because then it doesn't contain a sequence length.
 func (s *Share) SequenceLen() uint32 {
@@ -162,7 +228,8 @@ func (s *Share) rawDataStartIndex() int {
 	if isCompact {
 		index += ShareReservedBytes
 	}
-	if s.Version() == ShareVersionOne {
+	// the signer is only included in the first share of a sequence
+	if s.Version() == ShareVersionOne && isStart {
 		index += SignerSize
 	}
 	return index
@@ -189,27 +256,28 @@ func (s *Share) RawDataUsingReserved() (rawData []byte, err error) {
 // rawDataStartIndexUsingReserved returns the start index of raw data while accounting for
 // reserved bytes, if it exists in the share.
 func (s *Share) rawDataStartIndexUsingReserved() (int, error) {
-	isStart := s.IsSequenceStart()
-	isCompact := s.IsCompactShare()
+	if s.IsCompactShare() {
+		reservedBytes, err := s.ReservedBytes()
+		if err != nil {
+			return 0, err
+		}
+		return int(reservedBytes), nil
+	}
 
 	index := NamespaceSize + ShareInfoBytes
+	isStart := s.IsSequenceStart()
 	if isStart {
 		index += SequenceLenBytes
 	}
-	if s.Version() == ShareVersionOne {
+	// the signer is only included in the first share of a sequence
+	if s.Version() == ShareVersionOne && isStart {
 		index += SignerSize
 	}
-
-	if isCompact {
-		reservedBytes, err := ParseReservedBytes(s.data[index : index+ShareReservedBytes])
-		if err != nil {
-			return 0, err
-		}
-		return int(reservedBytes), nil
-	}
 	return index, nil
 }
 
+// ToBytes returns the raw data of the shares. The returned slices are views
+// into the shares.
 func ToBytes(shares []Share) (bytes [][]byte) {
 	bytes = make([][]byte, len(shares))
 	for i, share := range shares {
@@ -218,6 +286,7 @@ func ToBytes(shares []Share) (bytes [][]byte) {
 	return bytes
 }
 
+// FromBytes creates shares that wrap the provided raw share data.
 func FromBytes(bytes [][]byte) (shares []Share, err error) {
 	for _, b := range bytes {
 		share, err := NewShare(b)
@@ -0,0 +1,17 @@
+package share
+
+// shareBuffer hands out share sized slices of a preallocated buffer so that
+// splitters can build their shares in place, for example directly in the
+// buffer backing a square. Shares beyond the end of the buffer, or all shares
+// if the buffer is nil, are allocated individually.
+type shareBuffer []byte
+
+// at returns an empty slice with a capacity of ShareSize for the share at
+// index i of the buffer, or nil if the buffer does not hold that share.
+func (buf shareBuffer) at(i int) []byte {
+	start := i * ShareSize
+	if start+ShareSize > len(buf) {
+		return nil
+	}
+	return buf[start : start : start+ShareSize]
+}
@@ -21,11 +21,19 @@ func newEmptyBuilder() *builder {
 
 // newBuilder returns a new share builder.
 func newBuilder(ns Namespace, shareVersion uint8, isFirstShare bool) (*builder, error) {
+	return newBuilderWithBuffer(ns, shareVersion, isFirstShare, nil)
+}
+
+// newBuilderWithBuffer returns a new share builder that builds the share in
+// buf, which must be empty and have a capacity of ShareSize. If buf is nil, a
+// new buffer is allocated.
+func newBuilderWithBuffer(ns Namespace, shareVersion uint8, isFirstShare bool, buf []byte) (*builder, error) {
 	b := builder{
 		namespace:      ns,
 		shareVersion:   shareVersion,
 		isFirstShare:   isFirstShare,
 		isCompactShare: isCompactShare(ns),
+		rawShareData:   buf,
 	}
 	if err := b.init(); err != nil {
 		return nil, err
@@ -72,7 +80,10 @@ func (b *builder) AddData(rawData []byte) (rawDataLeftOver []byte) {
 }
 
 func (b *builder) Build() (*Share, error) {
-	return NewShare(b.rawShareData)
+	if err := validateSize(b.rawShareData); err != nil {
+		return nil, err
+	}
+	return newShare(b.rawShareData), nil
 }
 
 // IsEmptyShare returns true if no data has been written to the share
@@ -187,7 +198,7 @@ func (b *builder) FlipSequenceStart() {
 }
 
 func (b *builder) prepareCompactShare() error {
-	shareData := make([]byte, 0, ShareSize)
+	shareData := b.emptyShareData()
 	infoByte, err := NewInfoByte(b.shareVersion, b.isFirstShare)
 	if err != nil {
 		return err
@@ -210,7 +221,7 @@ func (b *builder) prepareCompactShare() error {
 }
 
 func (b *builder) prepareSparseShare() error {
-	shareData := make([]byte, 0, ShareSize)
+	shareData := b.emptyShareData()
 	infoByte, err := NewInfoByte(b.shareVersion, b.isFirstShare)
 	if err != nil {
 		return err
@@ -228,6 +239,15 @@ func (b *builder) prepareSparseShare() error {
 	return nil
 }
 
+// emptyShareData returns the buffer passed to newBuilderWithBuffer or
+// allocates a new one.
+func (b *builder) emptyShareData() []byte {
+	if b.rawShareData != nil {
+		return b.rawShareData[:0]
+	}
+	return make([]byte, 0, ShareSize)
+}
+
 func isCompactShare(ns Namespace) bool {
-	return ns.IsTx() || ns.IsPayForBlob()
+	return !ns.IsEmpty() && ns.IsPrimaryReserved() && !ns.IsPrimaryReservedPadding()
 }
@@ -1,6 +1,7 @@
 package share
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -27,6 +28,77 @@ func (s Sequence) RawData() (data []byte, err error) {
 	return data[:sequenceLen], nil
 }
 
+// Version returns the share version of the sequence, as encoded in its first
+// share.
+func (s Sequence) Version() uint8 {
+	if len(s.Shares) == 0 {
+		return 0
+	}
+	return s.Shares[0].Version()
+}
+
+// Data returns the units of data stored in the sequence. For a compact
+// sequence these are the length delimited transactions or intermediate state
+// roots, for a sparse sequence it is the data of its single blob. Padding
+// sequences contain no data. An error is returned if the sequence is invalid.
+func (s Sequence) Data() ([][]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	if s.isPadding() {
+		return nil, nil
+	}
+	if s.Shares[0].IsCompactShare() {
+		return parseCompactShares(s.Shares)
+	}
+	blob, err := s.blob(AllowedNamespaceVersions(s.Namespace.Version()))
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{blob.Data()}, nil
+}
+
+// blob reassembles the blob of a valid, non padding, sparse share sequence.
+func (s Sequence) blob(opts ...NamespaceOption) (*Blob, error) {
+	blobs, err := parseSparseShares(s.Shares, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(blobs) != 1 {
+		return nil, fmt.Errorf("sparse share sequence contains %d blobs, expected 1", len(blobs))
+	}
+	return blobs[0], nil
+}
+
+// Validate returns an error if the shares of the sequence do not form a
+// single sequence: the first share must start the sequence, all shares must
+// have the sequence's namespace and share version and the number of shares
+// must match the sequence length.
+func (s Sequence) Validate() error {
+	if len(s.Shares) == 0 {
+		return errors.New("share sequence has no shares")
+	}
+	first := s.Shares[0]
+	if !first.IsSequenceStart() {
+		return errors.New("first share of sequence is not a sequence start")
+	}
+	if err := first.CheckVersionSupported(); err != nil {
+		return err
+	}
+	for i, share := range s.Shares {
+		if !share.Namespace().Equals(s.Namespace) {
+			return fmt.Errorf("share %d has namespace %s but sequence has namespace %s", i, share.Namespace(), s.Namespace)
+		}
+		if share.Version() != first.Version() {
+			return fmt.Errorf("share %d has version %d but sequence has version %d", i, share.Version(), first.Version())
+		}
+		if i > 0 && share.IsSequenceStart() {
+			return fmt.Errorf("share %d unexpectedly starts a new sequence", i)
+		}
+	}
+	return s.validSequenceLen()
+}
+
 func (s Sequence) SequenceLen() (uint32, error) {
 	if len(s.Shares) == 0 {
 		return 0, fmt.Errorf("invalid sequence length because share sequence %v has no shares", s)
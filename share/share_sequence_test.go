@@ -255,3 +255,59 @@ func FuzzValidSequenceLen(f *testing.F) {
 func padShare(share Share) (paddedShare Share) {
 	return fillShare(share, 0)
 }
+
+func TestParseSequences(t *testing.T) {
+	txs := generateRandomTxs(5, 300)
+	txShares, _, err := splitTxs(txs)
+	require.NoError(t, err)
+	ns1 := MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize))
+	ns2 := MustNewV0Namespace(bytes.Repeat([]byte{2}, NamespaceVersionZeroIDSize))
+	blob1 := generateRandomBlobWithNamespace(ns1, 1000)
+	blob2 := generateRandomBlobWithNamespace(ns2, 100)
+	blobShares, err := splitBlobs(blob1, blob2)
+	require.NoError(t, err)
+	padding, err := NamespacePaddingShares(ns2, ShareVersionZero, 2)
+	require.NoError(t, err)
+
+	shares := append(append(append([]Share{}, txShares...), blobShares...), padding...)
+	sequences, err := ParseSequences(shares)
+	require.NoError(t, err)
+	require.Len(t, sequences, 3)
+
+	data, err := sequences[0].Data()
+	require.NoError(t, err)
+	assert.Equal(t, txs, data)
+	assert.Equal(t, TxNamespace, sequences[0].Namespace)
+
+	for i, blob := range []*Blob{blob1, blob2} {
+		sequence := sequences[i+1]
+		assert.Equal(t, blob.Namespace(), sequence.Namespace)
+		assert.Equal(t, blob.ShareVersion(), sequence.Version())
+		data, err := sequence.Data()
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{blob.Data()}, data)
+	}
+}
+
+func TestSequenceValidate(t *testing.T) {
+	ns1 := MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize))
+	ns2 := MustNewV0Namespace(bytes.Repeat([]byte{2}, NamespaceVersionZeroIDSize))
+	shares1, err := splitBlobs(generateRandomBlobWithNamespace(ns1, 1000))
+	require.NoError(t, err)
+	shares2, err := splitBlobs(generateRandomBlobWithNamespace(ns2, 1000))
+	require.NoError(t, err)
+
+	require.NoError(t, Sequence{Namespace: ns1, Shares: shares1}.Validate())
+	require.Error(t, Sequence{Namespace: ns1}.Validate())
+	// missing sequence start
+	require.Error(t, Sequence{Namespace: ns1, Shares: shares1[1:]}.Validate())
+	// mismatching namespace
+	require.Error(t, Sequence{Namespace: ns2, Shares: shares1}.Validate())
+	require.Error(t, Sequence{Namespace: ns1, Shares: []Share{shares1[0], shares2[1]}}.Validate())
+	// two sequence starts
+	require.Error(t, Sequence{Namespace: ns1, Shares: []Share{shares1[0], shares1[0]}}.Validate())
+	// missing shares
+	require.Error(t, Sequence{Namespace: ns1, Shares: shares1[:1]}.Validate())
+	_, err = Sequence{Namespace: ns1, Shares: shares1[:1]}.Data()
+	require.Error(t, err)
+}
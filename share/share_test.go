@@ -87,6 +87,21 @@ func TestRawData(t *testing.T) {
 			0,                             // info byte
 			1, 2, 3, 4, 5, 6, 7, 8, 9, 10, // data
 		}...)
+	signer := bytes.Repeat([]byte{0xff}, SignerSize)
+	firstV1SparseShare := append(append(
+		sparseNamespaceID.Bytes(),
+		[]byte{
+			3,           // info byte
+			0, 0, 0, 10, // sequence len
+		}...),
+		append(signer, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)..., // signer and data
+	)
+	continuationV1SparseShare := append(
+		sparseNamespaceID.Bytes(),
+		[]byte{
+			2,                             // info byte
+			1, 2, 3, 4, 5, 6, 7, 8, 9, 10, // data
+		}...)
 	firstCompactShare := append(TxNamespace.Bytes(),
 		[]byte{
 			1,           // info byte
@@ -111,6 +126,16 @@ func TestRawData(t *testing.T) {
 			share: Share{data: continuationSparseShare},
 			want:  []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 		},
+		{
+			name:  "first share version 1 sparse share",
+			share: Share{data: firstV1SparseShare},
+			want:  []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		{
+			name:  "continuation share version 1 sparse share",
+			share: Share{data: continuationV1SparseShare},
+			want:  []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
 		{
 			name:  "first compact share",
 			share: Share{data: firstCompactShare},
@@ -126,6 +151,11 @@ func TestRawData(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			assert.Equal(t, tc.want, tc.share.RawData())
+			if !tc.share.IsCompactShare() {
+				rawData, err := tc.share.RawDataUsingReserved()
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, rawData)
+			}
 		})
 	}
 }
@@ -253,3 +283,57 @@ func TestMarshalShare(t *testing.T) {
 
 	require.Equal(t, sh[0], newShare)
 }
+
+func TestShareOwnership(t *testing.T) {
+	data := bytes.Repeat([]byte{1}, ShareSize)
+
+	shared, err := NewShare(data)
+	require.NoError(t, err)
+	wrapped, err := NewShareUnsafe(data)
+	require.NoError(t, err)
+	clone := wrapped.Clone()
+
+	data[0] = 2
+	assert.Equal(t, byte(2), shared.ToBytes()[0])
+	assert.Equal(t, byte(1), clone.ToBytes()[0])
+	if paranoid {
+		assert.Equal(t, byte(1), wrapped.ToBytes()[0])
+	} else {
+		assert.Equal(t, byte(2), wrapped.ToBytes()[0])
+	}
+
+	_, err = NewShareUnsafe(data[:ShareSize-1])
+	require.Error(t, err)
+}
+
+func TestShareReservedBytes(t *testing.T) {
+	txs := generateRandomTxs(5, 700)
+	shares, _, err := splitTxs(txs)
+	require.NoError(t, err)
+
+	for i, sh := range shares {
+		reserved, err := sh.ReservedBytes()
+		require.NoError(t, err)
+		start := NamespaceSize + ShareInfoBytes
+		if i == 0 {
+			start += SequenceLenBytes
+		}
+		expected, err := ParseReservedBytes(sh.ToBytes()[start : start+ShareReservedBytes])
+		require.NoError(t, err)
+		assert.Equal(t, expected, reserved)
+	}
+	// the first transaction starts right after the reserved bytes
+	reserved, err := shares[0].ReservedBytes()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(NamespaceSize+ShareInfoBytes+SequenceLenBytes+ShareReservedBytes), reserved)
+
+	sparse, err := splitBlobs(generateRandomBlob(100))
+	require.NoError(t, err)
+	_, err = sparse[0].ReservedBytes()
+	require.Error(t, err)
+
+	invalid := shares[0].Clone()
+	copy(invalid.data[NamespaceSize+ShareInfoBytes+SequenceLenBytes:], []byte{0xff, 0xff, 0xff, 0xff})
+	_, err = invalid.ReservedBytes()
+	require.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package share
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// Signer is the address of the account that signed a share version 1 blob.
+// Unlike a raw byte slice, a Signer is always exactly SignerSize bytes long.
+type Signer [SignerSize]byte
+
+// ParseSigner returns the Signer encoded in signer. It returns an error if
+// signer is not exactly SignerSize bytes long.
+func ParseSigner(signer []byte) (Signer, error) {
+	if len(signer) != SignerSize {
+		return Signer{}, fmt.Errorf("signer must be %d bytes, got %d", SignerSize, len(signer))
+	}
+	return Signer(signer), nil
+}
+
+// Bytes returns a copy of the signer as a byte slice.
+func (s Signer) Bytes() []byte {
+	return s[:]
+}
+
+// IsZero returns true if every byte of the signer is zero.
+func (s Signer) IsZero() bool {
+	return s == Signer{}
+}
+
+// String returns the hex encoding of the signer.
+func (s Signer) String() string {
+	return hex.EncodeToString(s[:])
+}
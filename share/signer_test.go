@@ -0,0 +1,45 @@
+package share
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSigner(t *testing.T) {
+	raw := bytes.Repeat([]byte{0xab}, SignerSize)
+	signer, err := ParseSigner(raw)
+	require.NoError(t, err)
+	require.Equal(t, raw, signer.Bytes())
+	require.Equal(t, "abababababababababababababababababababab", signer.String())
+	require.False(t, signer.IsZero())
+	require.True(t, Signer{}.IsZero())
+
+	// mutating the input must not affect the parsed signer
+	raw[0] = 0
+	require.Equal(t, byte(0xab), signer[0])
+
+	for _, size := range []int{0, SignerSize - 1, SignerSize + 1} {
+		_, err := ParseSigner(make([]byte, size))
+		require.Error(t, err)
+	}
+}
+
+func TestNewV1BlobWithSigner(t *testing.T) {
+	ns := RandomBlobNamespace()
+	signer := Signer(bytes.Repeat([]byte{1}, SignerSize))
+
+	blob, err := NewV1BlobWithSigner(ns, []byte{1, 2, 3}, signer)
+	require.NoError(t, err)
+	require.Equal(t, ShareVersionOne, blob.ShareVersion())
+	require.Equal(t, signer.Bytes(), blob.Signer())
+	typed, ok := blob.TypedSigner()
+	require.True(t, ok)
+	require.Equal(t, signer, typed)
+
+	blob, err = NewV0Blob(ns, []byte{1, 2, 3})
+	require.NoError(t, err)
+	_, ok = blob.TypedSigner()
+	require.False(t, ok)
+}
@@ -21,12 +21,24 @@ type CompactShareSplitter struct {
 	// thing in the data square (e.g. the range for the first tx starts at index
 	// 0).
 	shareRanges map[[sha256.Size]byte]Range
+	// buf, if set, holds the data of the shares written
+	buf shareBuffer
 }
 
 // NewCompactShareSplitter returns a CompactShareSplitter using the provided
 // namespace and shareVersion.
 func NewCompactShareSplitter(ns Namespace, shareVersion uint8) *CompactShareSplitter {
-	sb, err := newBuilder(ns, shareVersion, true)
+	return NewCompactShareSplitterWithBuffer(ns, shareVersion, nil)
+}
+
+// NewCompactShareSplitterWithBuffer returns a CompactShareSplitter that builds
+// its shares in place in buf, share after share, instead of allocating a
+// buffer per share. This allows writing compact shares directly into the
+// buffer backing a square. Shares that do not fit into buf are allocated
+// individually. The exported shares share their memory with buf so buf must
+// not be modified while they are in use.
+func NewCompactShareSplitterWithBuffer(ns Namespace, shareVersion uint8, buf []byte) *CompactShareSplitter {
+	sb, err := newBuilderWithBuffer(ns, shareVersion, true, shareBuffer(buf).at(0))
 	if err != nil {
 		panic(err)
 	}
@@ -37,6 +49,7 @@ func NewCompactShareSplitter(ns Namespace, shareVersion uint8) *CompactShareSpli
 		shareVersion: shareVersion,
 		shareRanges:  map[[sha256.Size]byte]Range{},
 		shareBuilder: sb,
+		buf:          buf,
 	}
 }
 
@@ -102,7 +115,7 @@ func (css *CompactShareSplitter) stackPending() error {
 	css.shares = append(css.shares, *pendingShare)
 
 	// Now we need to create a new builder
-	css.shareBuilder, err = newBuilder(css.namespace, css.shareVersion, false)
+	css.shareBuilder, err = newBuilderWithBuffer(css.namespace, css.shareVersion, false, css.buf.at(len(css.shares)))
 	return err
 }
 
@@ -159,11 +172,9 @@ func (css *CompactShareSplitter) writeSequenceLen(sequenceLen uint32) error {
 		return nil
 	}
 
-	// We may find a more efficient way to write seqLen
-	b, err := newBuilder(css.namespace, css.shareVersion, true)
-	if err != nil {
-		return err
-	}
+	// the sequence length is written in place so that the first share keeps
+	// using the buffer it was built in
+	b := &builder{isFirstShare: true}
 	b.ImportRawShare(css.shares[0].ToBytes())
 	if err := b.WriteSequenceLen(sequenceLen); err != nil {
 		return err
@@ -213,6 +224,23 @@ func (css *CompactShareSplitter) Count() int {
 	return len(css.shares)
 }
 
+// RemainderCapacity returns the number of bytes that can still be written to
+// the last, partially filled share before the splitter needs to start a new
+// share. It returns 0 if there is no partially filled share.
+func (css *CompactShareSplitter) RemainderCapacity() int {
+	if css.done || css.shareBuilder.IsEmptyShare() {
+		return 0
+	}
+	return css.shareBuilder.AvailableBytes()
+}
+
+// WouldOverflowIntoNewShare returns true if writing a tx of length txLen would
+// increase the number of shares returned by Count. The length delimiter that
+// is prefixed to the tx is taken into account.
+func (css *CompactShareSplitter) WouldOverflowIntoNewShare(txLen int) bool {
+	return txLen+DelimLen(uint64(txLen)) > css.RemainderCapacity()
+}
+
 // MarshalDelimitedTx prefixes a transaction with the length of the transaction
 // encoded as a varint.
 func MarshalDelimitedTx(tx []byte) ([]byte, error) {
@@ -221,3 +249,9 @@ func MarshalDelimitedTx(tx []byte) ([]byte, error) {
 	n := binary.PutUvarint(lenBuf, length)
 	return append(lenBuf[:n], tx...), nil
 }
+
+// Namespace returns the namespace of the shares written by this compact share
+// splitter.
+func (css *CompactShareSplitter) Namespace() Namespace {
+	return css.namespace
+}
@@ -19,7 +19,7 @@ func TestCount(t *testing.T) {
 		{transactions: [][]byte{{0}}, wantShareCount: 1},
 		{transactions: [][]byte{bytes.Repeat([]byte{1}, 100)}, wantShareCount: 1},
 		// Test with 1 byte over 1 share
-		{transactions: [][]byte{bytes.Repeat([]byte{1}, rawTxSize(FirstCompactShareContentSize+1))}, wantShareCount: 2},
+		{transactions: [][]byte{bytes.Repeat([]byte{1}, RawTxSize(FirstCompactShareContentSize+1))}, wantShareCount: 2},
 		{transactions: [][]byte{generateTx(1)}, wantShareCount: 1},
 		{transactions: [][]byte{generateTx(2)}, wantShareCount: 2},
 		{transactions: [][]byte{generateTx(20)}, wantShareCount: 20},
@@ -47,9 +47,9 @@ func generateTx(numShares int) []byte {
 		return []byte{}
 	}
 	if numShares == 1 {
-		return bytes.Repeat([]byte{1}, rawTxSize(FirstCompactShareContentSize))
+		return bytes.Repeat([]byte{1}, RawTxSize(FirstCompactShareContentSize))
 	}
-	return bytes.Repeat([]byte{2}, rawTxSize(FirstCompactShareContentSize+(numShares-1)*ContinuationCompactShareContentSize))
+	return bytes.Repeat([]byte{2}, RawTxSize(FirstCompactShareContentSize+(numShares-1)*ContinuationCompactShareContentSize))
 }
 
 func TestExport_write(t *testing.T) {
@@ -158,26 +158,26 @@ func TestWriteAndExportIdempotence(t *testing.T) {
 		{
 			name: "two txs that occupy exactly two shares",
 			txs: [][]byte{
-				bytes.Repeat([]byte{0xf}, rawTxSize(FirstCompactShareContentSize)),
-				bytes.Repeat([]byte{0xf}, rawTxSize(ContinuationCompactShareContentSize)),
+				bytes.Repeat([]byte{0xf}, RawTxSize(FirstCompactShareContentSize)),
+				bytes.Repeat([]byte{0xf}, RawTxSize(ContinuationCompactShareContentSize)),
 			},
 			wantLen: 2,
 		},
 		{
 			name: "three txs that occupy exactly three shares",
 			txs: [][]byte{
-				bytes.Repeat([]byte{0xf}, rawTxSize(FirstCompactShareContentSize)),
-				bytes.Repeat([]byte{0xf}, rawTxSize(ContinuationCompactShareContentSize)),
-				bytes.Repeat([]byte{0xf}, rawTxSize(ContinuationCompactShareContentSize)),
+				bytes.Repeat([]byte{0xf}, RawTxSize(FirstCompactShareContentSize)),
+				bytes.Repeat([]byte{0xf}, RawTxSize(ContinuationCompactShareContentSize)),
+				bytes.Repeat([]byte{0xf}, RawTxSize(ContinuationCompactShareContentSize)),
 			},
 			wantLen: 3,
 		},
 		{
 			name: "four txs that occupy three full shares and one partial share",
 			txs: [][]byte{
-				bytes.Repeat([]byte{0xf}, rawTxSize(FirstCompactShareContentSize)),
-				bytes.Repeat([]byte{0xf}, rawTxSize(ContinuationCompactShareContentSize)),
-				bytes.Repeat([]byte{0xf}, rawTxSize(ContinuationCompactShareContentSize)),
+				bytes.Repeat([]byte{0xf}, RawTxSize(FirstCompactShareContentSize)),
+				bytes.Repeat([]byte{0xf}, RawTxSize(ContinuationCompactShareContentSize)),
+				bytes.Repeat([]byte{0xf}, RawTxSize(ContinuationCompactShareContentSize)),
 				{0xf},
 			},
 			wantLen: 4,
@@ -211,8 +211,8 @@ func TestExport(t *testing.T) {
 	txOne := []byte{0x1}
 	txTwo := bytes.Repeat([]byte{2}, 600)
 	txThree := bytes.Repeat([]byte{3}, 1000)
-	exactlyOneShare := bytes.Repeat([]byte{4}, rawTxSize(FirstCompactShareContentSize))
-	exactlyTwoShares := bytes.Repeat([]byte{5}, rawTxSize(FirstCompactShareContentSize+ContinuationCompactShareContentSize))
+	exactlyOneShare := bytes.Repeat([]byte{4}, RawTxSize(FirstCompactShareContentSize))
+	exactlyTwoShares := bytes.Repeat([]byte{5}, RawTxSize(FirstCompactShareContentSize+ContinuationCompactShareContentSize))
 
 	testCases := []testCase{
 		{
@@ -331,9 +331,9 @@ func TestExport(t *testing.T) {
 }
 
 func TestWriteAfterExport(t *testing.T) {
-	a := bytes.Repeat([]byte{0xf}, rawTxSize(FirstCompactShareContentSize))
-	b := bytes.Repeat([]byte{0xf}, rawTxSize(ContinuationCompactShareContentSize*2))
-	c := bytes.Repeat([]byte{0xf}, rawTxSize(ContinuationCompactShareContentSize))
+	a := bytes.Repeat([]byte{0xf}, RawTxSize(FirstCompactShareContentSize))
+	b := bytes.Repeat([]byte{0xf}, RawTxSize(ContinuationCompactShareContentSize*2))
+	c := bytes.Repeat([]byte{0xf}, RawTxSize(ContinuationCompactShareContentSize))
 	d := []byte{0xf}
 
 	css := NewCompactShareSplitter(TxNamespace, ShareVersionZero)
@@ -379,3 +379,53 @@ func TestWriteAfterExport(t *testing.T) {
 func fillShare(share Share, filler byte) (paddedShare Share) {
 	return Share{data: append(share.data, bytes.Repeat([]byte{filler}, ShareSize-len(share.data))...)}
 }
+
+func TestRemainderCapacity(t *testing.T) {
+	css := NewCompactShareSplitter(TxNamespace, ShareVersionZero)
+	assert.Equal(t, 0, css.RemainderCapacity())
+	assert.True(t, css.WouldOverflowIntoNewShare(0))
+
+	require.NoError(t, css.WriteTx(bytes.Repeat([]byte{1}, 100)))
+	capacity := FirstCompactShareContentSize - 101
+	assert.Equal(t, capacity, css.RemainderCapacity())
+
+	// a tx filling the remainder exactly doesn't start a new share
+	fillingTx := bytes.Repeat([]byte{2}, capacity-DelimLen(uint64(capacity)))
+	assert.False(t, css.WouldOverflowIntoNewShare(len(fillingTx)))
+	assert.True(t, css.WouldOverflowIntoNewShare(len(fillingTx)+1))
+	require.NoError(t, css.WriteTx(fillingTx))
+	assert.Equal(t, 1, css.Count())
+	assert.Equal(t, 0, css.RemainderCapacity())
+
+	assert.True(t, css.WouldOverflowIntoNewShare(1))
+	require.NoError(t, css.WriteTx([]byte{3}))
+	assert.Equal(t, 2, css.Count())
+	assert.Equal(t, ContinuationCompactShareContentSize-2, css.RemainderCapacity())
+
+	// exported shares are padded so no further data fits in them
+	_, err := css.Export()
+	require.NoError(t, err)
+	assert.Equal(t, 0, css.RemainderCapacity())
+}
+
+func TestCompactShareSplitterWithBuffer(t *testing.T) {
+	txs := [][]byte{bytes.Repeat([]byte{1}, 300), bytes.Repeat([]byte{2}, 600), bytes.Repeat([]byte{3}, 400)}
+	write := func(css *CompactShareSplitter) []Share {
+		for _, tx := range txs {
+			require.NoError(t, css.WriteTx(tx))
+		}
+		shares, err := css.Export()
+		require.NoError(t, err)
+		return shares
+	}
+	expected := write(NewCompactShareSplitter(TxNamespace, ShareVersionZero))
+	require.Len(t, expected, 3)
+
+	// the last share does not fit into the buffer and is allocated
+	buf := make([]byte, 2*ShareSize)
+	got := write(NewCompactShareSplitterWithBuffer(TxNamespace, ShareVersionZero, buf))
+	assert.Equal(t, expected, got)
+	for i, sh := range got[:2] {
+		assert.Same(t, &buf[i*ShareSize], &sh.ToBytes()[0], i)
+	}
+}
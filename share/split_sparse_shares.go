@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/celestiaorg/go-square/v2/layout"
 	"golang.org/x/exp/slices"
 )
 
@@ -12,12 +13,42 @@ import (
 // how many shares the blobs written take up.
 type SparseShareSplitter struct {
 	shares []Share
+	// buf, if set, holds the data of the shares written
+	buf shareBuffer
+	// squareOffset is the index in the square of the first share and
+	// nextShareIndex the rule used by WriteAt to check blob start indexes.
+	// Both are set by SetPlacement.
+	squareOffset   int
+	nextShareIndex NextShareIndexFunc
+}
+
+// NextShareIndexFunc returns the first index at or after cursor in the square
+// at which a blob of blobShareLen shares may start.
+type NextShareIndexFunc func(cursor, blobShareLen int) int
+
+// SubtreeRootThresholdRule returns the NextShareIndexFunc of the blob share
+// commitment rules for the provided subtree root threshold. See
+// layout.NextShareIndex.
+func SubtreeRootThresholdRule(subtreeRootThreshold int) NextShareIndexFunc {
+	return func(cursor, blobShareLen int) int {
+		return layout.NextShareIndex(cursor, blobShareLen, subtreeRootThreshold)
+	}
 }
 
 func NewSparseShareSplitter() *SparseShareSplitter {
 	return &SparseShareSplitter{}
 }
 
+// NewSparseShareSplitterWithBuffer returns a SparseShareSplitter that builds
+// the shares it writes in place in buf, share after share, instead of
+// allocating a buffer per share. This allows writing blobs directly into the
+// buffer backing a square. Shares that do not fit into buf are allocated
+// individually. The exported shares share their memory with buf so buf must
+// not be modified while they are in use.
+func NewSparseShareSplitterWithBuffer(buf []byte) *SparseShareSplitter {
+	return &SparseShareSplitter{buf: buf}
+}
+
 // Write writes the provided blob to this sparse share splitter. It returns an
 // error or nil if no error is encountered.
 func (sss *SparseShareSplitter) Write(blob *Blob) error {
@@ -27,20 +58,29 @@ func (sss *SparseShareSplitter) Write(blob *Blob) error {
 
 	rawData := blob.Data()
 	blobNamespace := blob.Namespace()
-
-	b, err := newBuilder(blobNamespace, blob.ShareVersion(), true)
-	if err != nil {
-		return err
-	}
-	if err := b.WriteSequenceLen(uint32(len(rawData))); err != nil {
-		return err
-	}
-	// add the signer to the first share for v1 share versions only
-	if blob.ShareVersion() == ShareVersionOne {
-		b.WriteSigner(blob.Signer())
+	// the header of v3 and v4 share versions is part of the sequence
+	if header := blob.sequenceHeader(); header != nil {
+		rawData = append(header, rawData...)
 	}
 
+	sequenceLen := uint32(len(rawData))
+	isFirstShare := true
 	for rawData != nil {
+		b, err := newBuilderWithBuffer(blobNamespace, blob.ShareVersion(), isFirstShare, sss.buf.at(len(sss.shares)))
+		if err != nil {
+			return err
+		}
+		if isFirstShare {
+			if err := b.WriteSequenceLen(sequenceLen); err != nil {
+				return err
+			}
+			// add the signer to the first share for v1 share versions only
+			if blob.ShareVersion() == ShareVersionOne {
+				b.WriteSigner(blob.Signer())
+			}
+			isFirstShare = false
+		}
+
 		rawDataLeftOver := b.AddData(rawData)
 		if rawDataLeftOver == nil {
 			// Just call it on the latest share
@@ -52,21 +92,58 @@ func (sss *SparseShareSplitter) Write(blob *Blob) error {
 			return err
 		}
 		sss.shares = append(sss.shares, *share)
-
-		b, err = newBuilder(blobNamespace, blob.ShareVersion(), false)
-		if err != nil {
-			return err
-		}
 		rawData = rawDataLeftOver
 	}
 
 	return nil
 }
 
-// WriteNamespacePaddingShares adds padding shares with the namespace of the
-// last written share. This is useful to follow the non-interactive default
-// rules. This function assumes that at least one share has already been
-// written.
+// SetPlacement records that the first share of this splitter is placed at
+// squareOffset in the square and that blobs are placed according to
+// nextShareIndex. It must be called before WriteAt.
+func (sss *SparseShareSplitter) SetPlacement(squareOffset int, nextShareIndex NextShareIndexFunc) {
+	sss.squareOffset = squareOffset
+	sss.nextShareIndex = nextShareIndex
+}
+
+// WriteAt writes the provided blob so that its first share is at startIndex,
+// relative to the first share of this splitter. Any gap between the last
+// written share and startIndex is filled with namespace padding shares of the
+// last written share. It returns an error if the placement has not been set
+// using SetPlacement, if startIndex does not comply with the blob share
+// commitment rules, if it would overlap with already written shares or if the
+// splitter is empty and startIndex is not 0.
+func (sss *SparseShareSplitter) WriteAt(blob *Blob, startIndex int) error {
+	if sss.nextShareIndex == nil {
+		return errors.New("placement of the SparseShareSplitter is not set")
+	}
+	squareIndex := sss.squareOffset + startIndex
+	blobShareLen := SparseSharesNeeded(blob.SequenceLen())
+	if next := sss.nextShareIndex(squareIndex, blobShareLen); next != squareIndex {
+		return fmt.Errorf("start index %d at square index %d violates the blob share commitment rules, the next valid square index is %d", startIndex, squareIndex, next)
+	}
+	return sss.writeAt(blob, startIndex)
+}
+
+// writeAt behaves like WriteAt but does not check startIndex against the
+// blob share commitment rules.
+func (sss *SparseShareSplitter) writeAt(blob *Blob, startIndex int) error {
+	if startIndex < sss.Count() {
+		return fmt.Errorf("start index %d overlaps with the %d shares already written", startIndex, sss.Count())
+	}
+	if sss.Count() == 0 && startIndex != 0 {
+		return fmt.Errorf("start index %d can not be padded on an empty SparseShareSplitter", startIndex)
+	}
+	if err := sss.WriteNamespacePaddingShares(startIndex - sss.Count()); err != nil {
+		return err
+	}
+	return sss.Write(blob)
+}
+
+// WriteNamespacePaddingShares adds padding shares with the namespace and share
+// version of the last written share. This is useful to follow the
+// non-interactive default rules. This function assumes that at least one share
+// has already been written.
 func (sss *SparseShareSplitter) WriteNamespacePaddingShares(count int) error {
 	if count < 0 {
 		return errors.New("cannot write negative namespaced shares")
@@ -80,11 +157,13 @@ func (sss *SparseShareSplitter) WriteNamespacePaddingShares(count int) error {
 	lastBlob := sss.shares[len(sss.shares)-1]
 	lastBlobNs := lastBlob.Namespace()
 	lastBlobInfo := lastBlob.InfoByte()
-	nsPaddingShares, err := NamespacePaddingShares(lastBlobNs, lastBlobInfo.Version(), count)
-	if err != nil {
-		return err
+	for i := 0; i < count; i++ {
+		nsPaddingShare, err := namespacePaddingShare(lastBlobNs, lastBlobInfo.Version(), sss.buf.at(len(sss.shares)))
+		if err != nil {
+			return err
+		}
+		sss.shares = append(sss.shares, nsPaddingShare)
 	}
-	sss.shares = append(sss.shares, nsPaddingShares...)
 
 	return nil
 }
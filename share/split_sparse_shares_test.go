@@ -59,3 +59,79 @@ func TestWriteNamespacePaddingShares(t *testing.T) {
 	version := got[1].Version()
 	assert.Equal(t, version, ShareVersionZero)
 }
+
+func TestWriteAt(t *testing.T) {
+	ns1 := MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize))
+	ns2 := MustNewV0Namespace(bytes.Repeat([]byte{2}, NamespaceVersionZeroIDSize))
+	blob1, err := NewV0Blob(ns1, []byte("data1"))
+	require.NoError(t, err)
+	blob2, err := NewV0Blob(ns2, []byte("data2"))
+	require.NoError(t, err)
+
+	sss := NewSparseShareSplitter()
+	require.Error(t, sss.WriteAt(blob1, 0), "placement is not set")
+	sss.SetPlacement(0, SubtreeRootThresholdRule(64))
+	require.Error(t, sss.WriteAt(blob1, 1))
+	require.NoError(t, sss.WriteAt(blob1, 0))
+	require.Error(t, sss.WriteAt(blob2, 0))
+	require.NoError(t, sss.WriteAt(blob2, 4))
+
+	// got is expected to be [blob1, padding, padding, padding, blob2]
+	got := sss.Export()
+	require.Len(t, got, 5)
+	for _, padding := range got[1:4] {
+		assert.True(t, padding.IsPadding())
+		assert.Equal(t, ns1, padding.Namespace())
+	}
+
+	blobs, ranges, err := ParseBlobsWithRanges(got)
+	require.NoError(t, err)
+	assert.Equal(t, []*Blob{blob1, blob2}, blobs)
+	assert.Equal(t, []Range{NewRange(0, 1), NewRange(4, 5)}, ranges)
+}
+
+func TestWriteAtCommitmentRules(t *testing.T) {
+	ns1 := MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize))
+	ns2 := MustNewV0Namespace(bytes.Repeat([]byte{2}, NamespaceVersionZeroIDSize))
+	blob1, err := NewV0Blob(ns1, []byte("data1"))
+	require.NoError(t, err)
+	// a blob of 5 shares must start at a multiple of 4 with a subtree root
+	// threshold of 1
+	blob2, err := NewV0Blob(ns2, bytes.Repeat([]byte{2}, 4*ContinuationSparseShareContentSize))
+	require.NoError(t, err)
+	require.Equal(t, 5, SparseSharesNeeded(blob2.SequenceLen()))
+
+	// the first share of the splitter is at index 1 of the square
+	sss := NewSparseShareSplitter()
+	sss.SetPlacement(1, SubtreeRootThresholdRule(1))
+	require.NoError(t, sss.WriteAt(blob1, 0))
+	require.Error(t, sss.WriteAt(blob2, 1))
+	require.Error(t, sss.WriteAt(blob2, 2))
+	require.NoError(t, sss.WriteAt(blob2, 3))
+	require.Len(t, sss.Export(), 8)
+}
+
+func TestSparseShareSplitterWithBuffer(t *testing.T) {
+	ns1 := MustNewV0Namespace(bytes.Repeat([]byte{1}, NamespaceVersionZeroIDSize))
+	ns2 := MustNewV0Namespace(bytes.Repeat([]byte{2}, NamespaceVersionZeroIDSize))
+	blob1, err := NewV0Blob(ns1, bytes.Repeat([]byte{1}, 1000))
+	require.NoError(t, err)
+	blob2, err := NewV1Blob(ns2, []byte("data2"), bytes.Repeat([]byte{1}, SignerSize))
+	require.NoError(t, err)
+
+	write := func(sss *SparseShareSplitter) []Share {
+		require.NoError(t, sss.writeAt(blob1, 0))
+		require.NoError(t, sss.writeAt(blob2, 4))
+		return sss.Export()
+	}
+	expected := write(NewSparseShareSplitter())
+	require.Len(t, expected, 5)
+
+	// the last share does not fit into the buffer and is allocated
+	buf := make([]byte, 4*ShareSize)
+	got := write(NewSparseShareSplitterWithBuffer(buf))
+	assert.Equal(t, expected, got)
+	for i, sh := range got[:4] {
+		assert.Same(t, &buf[i*ShareSize], &sh.ToBytes()[0], i)
+	}
+}
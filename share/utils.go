@@ -3,20 +3,23 @@ package share
 import (
 	"bytes"
 	"encoding/binary"
+	"sort"
+
+	"github.com/celestiaorg/go-square/v2/layout"
 )
 
-// delimLen calculates the length of the delimiter for a given unit size
-func delimLen(size uint64) int {
+// DelimLen returns the length of the uvarint length delimiter that prefixes a
+// unit of size bytes, such as a transaction, in compact shares.
+func DelimLen(size uint64) int {
 	lenBuf := make([]byte, binary.MaxVarintLen64)
 	return binary.PutUvarint(lenBuf, size)
 }
 
-// rawTxSize returns the raw tx size that can be used to construct a
-// tx of desiredSize bytes. This function is useful in tests to account for
-// the length delimiter that is prefixed to a tx when it is converted into
-// a compact share
-func rawTxSize(desiredSize int) int {
-	return desiredSize - delimLen(uint64(desiredSize))
+// RawTxSize returns the raw tx size that can be used to construct a tx that
+// occupies totalLen bytes of compact shares once prefixed with its length
+// delimiter. This is useful to size transactions to fill shares exactly.
+func RawTxSize(totalLen int) int {
+	return totalLen - DelimLen(uint64(totalLen))
 }
 
 // zeroPadIfNecessary pads the share with trailing zero bytes if the provided
@@ -29,8 +32,7 @@ func zeroPadIfNecessary(share []byte, width int) (padded []byte, bytesOfPadding
 	}
 
 	missingBytes := width - oldLen
-	padding := make([]byte, missingBytes)
-	share = append(share, padding...)
+	share = append(share, make([]byte, missingBytes)...)
 	return share, missingBytes
 }
 
@@ -89,3 +91,50 @@ func AvailableBytesFromSparseShares(n int) int {
 	}
 	return (n-1)*ContinuationSparseShareContentSize + FirstSparseShareContentSize
 }
+
+// MaxBlobSize returns the size of the largest share version 0 blob that can be
+// added to an otherwise empty square of maxSquareSize, taking into account the
+// worst-case padding the builder reserves for it. Blobs of other share versions
+// carry additional headers and can hold correspondingly less data.
+func MaxBlobSize(maxSquareSize, subtreeRootThreshold int) int {
+	return maxBlobSize(maxSquareSize*maxSquareSize, subtreeRootThreshold)
+}
+
+// MaxBlobSizeWithMinimalPFB behaves like MaxBlobSize but reserves a compact
+// share for the PFB paying for the blob. This is the largest blob that can be
+// included in a square.
+func MaxBlobSizeWithMinimalPFB(maxSquareSize, subtreeRootThreshold int) int {
+	return maxBlobSize(maxSquareSize*maxSquareSize-1, subtreeRootThreshold)
+}
+
+func maxBlobSize(availableShares, subtreeRootThreshold int) int {
+	if availableShares <= 0 {
+		return 0
+	}
+	// the shares used including worst-case padding grow monotonically with
+	// the number of blob shares
+	numShares := sort.Search(availableShares+1, func(n int) bool {
+		return n+layout.MaxPadding(n, subtreeRootThreshold) > availableShares
+	}) - 1
+	return AvailableBytesFromSparseShares(numShares)
+}
+
+// AvailableBytesForBlobsGiven returns the size of the largest share version 0
+// blob that still fits in a square of maxSquareSize when txBytes of
+// transactions have been written to the transaction namespace. txBytes is the
+// length of the sequence including the length delimiters of each
+// transaction. As with MaxBlobSizeWithMinimalPFB, a single compact share
+// is reserved for the PFB paying for the blob.
+func AvailableBytesForBlobsGiven(txBytes, maxSquareSize, subtreeRootThreshold int) int {
+	compactShares := CompactSharesNeeded(uint32(max(txBytes, 0))) + 1
+	return maxBlobSize(maxSquareSize*maxSquareSize-compactShares, subtreeRootThreshold)
+}
+
+// AvailableBytesForBlobsGivenCompact behaves like AvailableBytesForBlobsGiven
+// but takes the length of the PFB sequence explicitly instead of reserving a
+// single share for it. Both lengths include the length delimiters. The PFB
+// sequence should contain the PFB paying for the blob being quoted.
+func AvailableBytesForBlobsGivenCompact(txBytes, pfbBytes, maxSquareSize, subtreeRootThreshold int) int {
+	compactShares := CompactSharesNeeded(uint32(max(txBytes, 0))) + CompactSharesNeeded(uint32(max(pfbBytes, 0)))
+	return maxBlobSize(maxSquareSize*maxSquareSize-compactShares, subtreeRootThreshold)
+}
@@ -1,10 +1,12 @@
 package share
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_zeroPadIfNecessary(t *testing.T) {
@@ -110,3 +112,60 @@ func TestAvailableBytesFromSparseShares(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxBlobSize(t *testing.T) {
+	testCases := []struct {
+		maxSquareSize, subtreeRootThreshold int
+		expected, expectedWithPFB           int
+	}{
+		{1, 64, 478, 0},
+		{2, 64, 1924, 1442},
+		{64, 64, AvailableBytesFromSparseShares(4033), AvailableBytesFromSparseShares(4032)},
+		{64, 1, AvailableBytesFromSparseShares(4033), AvailableBytesFromSparseShares(4032)},
+		{0, 64, 0, 0},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, MaxBlobSize(tc.maxSquareSize, tc.subtreeRootThreshold), tc)
+		assert.Equal(t, tc.expectedWithPFB, MaxBlobSizeWithMinimalPFB(tc.maxSquareSize, tc.subtreeRootThreshold), tc)
+	}
+}
+
+func TestAvailableBytesForBlobsGiven(t *testing.T) {
+	testCases := []struct {
+		name                          string
+		txBytes, pfbBytes             int
+		maxSquareSize, threshold      int
+		expected, expectedWithPFBSize int
+	}{
+		{"no txs", 0, 1, 64, 64, MaxBlobSizeWithMinimalPFB(64, 64), MaxBlobSizeWithMinimalPFB(64, 64)},
+		{"one tx share", FirstCompactShareContentSize, 1, 64, 64, AvailableBytesFromSparseShares(4031), AvailableBytesFromSparseShares(4031)},
+		{"two tx shares", FirstCompactShareContentSize + 1, 1, 64, 64, AvailableBytesFromSparseShares(4030), AvailableBytesFromSparseShares(4030)},
+		{"two pfb shares", 0, FirstCompactShareContentSize + 1, 64, 64, AvailableBytesFromSparseShares(4032), AvailableBytesFromSparseShares(4031)},
+		{"txs fill the square", AvailableBytesFromCompactShares(4), 1, 2, 64, 0, 0},
+		{"negative lengths", -1, -1, 2, 64, MaxBlobSizeWithMinimalPFB(2, 64), MaxBlobSize(2, 64)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, AvailableBytesForBlobsGiven(tc.txBytes, tc.maxSquareSize, tc.threshold))
+			assert.Equal(t, tc.expectedWithPFBSize, AvailableBytesForBlobsGivenCompact(tc.txBytes, tc.pfbBytes, tc.maxSquareSize, tc.threshold))
+		})
+	}
+}
+
+func TestDelimLen(t *testing.T) {
+	assert.Equal(t, 1, DelimLen(0))
+	assert.Equal(t, 1, DelimLen(127))
+	assert.Equal(t, 2, DelimLen(128))
+	assert.Equal(t, 3, DelimLen(1<<14))
+}
+
+func TestRawTxSize(t *testing.T) {
+	for _, totalLen := range []int{FirstCompactShareContentSize, FirstCompactShareContentSize + ContinuationCompactShareContentSize} {
+		size := RawTxSize(totalLen)
+		assert.Equal(t, totalLen, size+DelimLen(uint64(size)))
+
+		shares, _, err := splitTxs([][]byte{bytes.Repeat([]byte{1}, size)})
+		require.NoError(t, err)
+		assert.Len(t, shares, (totalLen-FirstCompactShareContentSize)/ContinuationCompactShareContentSize+1)
+	}
+}
@@ -0,0 +1,129 @@
+package share
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ValidateShare performs a full structural validation of a single share. It
+// verifies the size, namespace, info byte, reserved bytes and that the
+// sequence length is consistent with the payload of the share.
+func ValidateShare(s Share) error {
+	if err := validateSize(s.data); err != nil {
+		return err
+	}
+	ns := s.Namespace()
+	if err := ns.validate(); err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	if _, err := ParseInfoByte(byte(s.InfoByte())); err != nil {
+		return fmt.Errorf("invalid info byte: %w", err)
+	}
+	if err := s.CheckVersionSupported(); err != nil {
+		return err
+	}
+
+	if (ns.IsTailPadding() || ns.IsPrimaryReservedPadding()) && !s.isNamespacePadding() {
+		return fmt.Errorf("padding share in namespace %s must be a sequence start with a sequence length of 0", ns)
+	}
+
+	if s.IsCompactShare() {
+		if s.Version() != ShareVersionZero {
+			return fmt.Errorf("unsupported share version for compact shares %v", s.Version())
+		}
+		if err := validateReservedBytes(s); err != nil {
+			return err
+		}
+	}
+
+	if s.IsSequenceStart() {
+		rawData := s.RawData()
+		if s.IsPadding() {
+			if !isZero(rawData) {
+				return fmt.Errorf("padding share in namespace %s contains non-zero data", ns)
+			}
+			return nil
+		}
+		sequenceLen := int(s.SequenceLen())
+		// the sequence ends in this share so the remainder must be zero padding
+		if sequenceLen < len(rawData) && !isZero(rawData[sequenceLen:]) {
+			return fmt.Errorf("share with sequence length %d contains non-zero data after the sequence", sequenceLen)
+		}
+		switch s.Version() {
+		case ShareVersionThree:
+			if _, _, err := parseBlobMetadata(rawData[:min(sequenceLen, len(rawData))]); err != nil {
+				return err
+			}
+		case ShareVersionFour:
+			if _, _, err := parseBlobCodec(rawData[:min(sequenceLen, len(rawData))]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateReservedBytes verifies that the reserved bytes of a compact share
+// either point to the start of a unit within the raw data of the share or are
+// zero if no unit starts in the share.
+func validateReservedBytes(s Share) error {
+	reservedStart := NamespaceSize + ShareInfoBytes
+	if s.IsSequenceStart() {
+		reservedStart += SequenceLenBytes
+	}
+	byteIndex, err := ParseReservedBytes(s.data[reservedStart : reservedStart+ShareReservedBytes])
+	if err != nil {
+		return fmt.Errorf("invalid reserved bytes: %w", err)
+	}
+	rawDataStart := uint32(s.rawDataStartIndex())
+	if s.IsSequenceStart() && s.SequenceLen() > 0 && byteIndex != rawDataStart {
+		return fmt.Errorf("reserved bytes of the first share in a sequence must be %d, got %d", rawDataStart, byteIndex)
+	}
+	if byteIndex != 0 && byteIndex < rawDataStart {
+		return fmt.Errorf("reserved bytes %d point before the start of raw data %d", byteIndex, rawDataStart)
+	}
+	return nil
+}
+
+// ValidateShares validates each share using ValidateShare and additionally
+// verifies that the shares are ordered by namespace, that continuation shares
+// belong to the sequence that precedes them and that each sequence contains
+// the number of shares implied by its sequence length.
+func ValidateShares(shares []Share) error {
+	for i, s := range shares {
+		if err := ValidateShare(s); err != nil {
+			return fmt.Errorf("share %d: %w", i, err)
+		}
+		if i == 0 {
+			if !s.IsSequenceStart() {
+				return fmt.Errorf("share %d: continuation share without a sequence start share", i)
+			}
+			continue
+		}
+		prev := shares[i-1]
+		if s.Namespace().IsLessThan(prev.Namespace()) {
+			return fmt.Errorf("share %d: namespace %s is less than the namespace %s of the previous share", i, s.Namespace(), prev.Namespace())
+		}
+		if !s.IsSequenceStart() {
+			if !bytes.Equal(s.Namespace().Bytes(), prev.Namespace().Bytes()) {
+				return fmt.Errorf("share %d: continuation share has namespace %s but the sequence has namespace %s", i, s.Namespace(), prev.Namespace())
+			}
+			if s.Version() != prev.Version() {
+				return fmt.Errorf("share %d: continuation share has version %d but the sequence has version %d", i, s.Version(), prev.Version())
+			}
+		}
+	}
+	if _, err := ParseShares(shares, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
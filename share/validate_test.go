@@ -0,0 +1,130 @@
+package share
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateShares(t *testing.T) {
+	txShares, _, err := splitTxs(generateRandomTxs(3, 300))
+	require.NoError(t, err)
+	blobs := []*Blob{
+		generateRandomBlob(100),
+		generateRandomBlob(ContinuationSparseShareContentSize * 3),
+	}
+	SortBlobs(blobs)
+	blobShares, err := splitBlobs(blobs...)
+	require.NoError(t, err)
+	padding, err := NamespacePaddingShare(blobs[0].Namespace(), ShareVersionZero)
+	require.NoError(t, err)
+
+	var shares []Share
+	shares = append(shares, txShares...)
+	shares = append(shares, ReservedPaddingShares(2)...)
+	shares = append(shares, blobShares[0], padding)
+	shares = append(shares, blobShares[1:]...)
+	shares = append(shares, TailPaddingShares(3)...)
+
+	require.NoError(t, ValidateShares(shares))
+	require.NoError(t, ValidateShares(nil))
+
+	testCases := []struct {
+		name   string
+		mutate func([]Share) []Share
+	}{
+		{
+			name: "unordered namespaces",
+			mutate: func(s []Share) []Share {
+				return append(append([]Share{}, TailPaddingShare()), s...)
+			},
+		},
+		{
+			name: "starts with continuation share",
+			mutate: func(s []Share) []Share {
+				return s[1:]
+			},
+		},
+		{
+			name: "missing continuation share",
+			mutate: func(s []Share) []Share {
+				return append(append([]Share{}, s[:len(s)-4]...), s[len(s)-3:]...)
+			},
+		},
+		{
+			name: "invalid reserved bytes",
+			mutate: func(s []Share) []Share {
+				s[0] = corruptShare(s[0], NamespaceSize+ShareInfoBytes+SequenceLenBytes, 0xff)
+				return s
+			},
+		},
+		{
+			name: "non-zero tail padding",
+			mutate: func(s []Share) []Share {
+				s[len(s)-1] = corruptShare(s[len(s)-1], ShareSize-1, 1)
+				return s
+			},
+		},
+		{
+			name: "unsupported share version",
+			mutate: func(s []Share) []Share {
+				s[0] = corruptShare(s[0], NamespaceSize, byte(ShareVersionThree<<1)|1)
+				return s
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mutated := tc.mutate(cloneShares(shares))
+			require.Error(t, ValidateShares(mutated))
+		})
+	}
+}
+
+func TestValidateShare(t *testing.T) {
+	blob := generateRandomBlob(10)
+	blobShares, err := splitBlobs(blob)
+	require.NoError(t, err)
+	require.NoError(t, ValidateShare(blobShares[0]))
+
+	// data after the end of the sequence must be zero
+	require.Error(t, ValidateShare(corruptShare(blobShares[0], ShareSize-1, 1)))
+
+	// sequence length must not be shorter than the blob
+	sequenceLen := make([]byte, SequenceLenBytes)
+	binary.BigEndian.PutUint32(sequenceLen, 5)
+	corrupted := cloneShares(blobShares)[0]
+	copy(corrupted.data[NamespaceSize+ShareInfoBytes:], sequenceLen)
+	require.Error(t, ValidateShare(corrupted))
+
+	// tail padding must have a sequence length of 0
+	tailPadding := TailPaddingShare()
+	require.NoError(t, ValidateShare(tailPadding))
+	require.Error(t, ValidateShare(corruptShare(tailPadding, NamespaceSize+ShareInfoBytes+SequenceLenBytes-1, 1)))
+
+	v3blob, err := NewV3Blob(RandomBlobNamespace(), []byte("data"), []byte("meta"))
+	require.NoError(t, err)
+	v3shares, err := v3blob.ToShares()
+	require.NoError(t, err)
+	require.NoError(t, ValidateShare(v3shares[0]))
+	// metadata length larger than the max metadata size
+	require.Error(t, ValidateShare(corruptShare(v3shares[0], NamespaceSize+ShareInfoBytes+SequenceLenBytes, MaxBlobMetadataSize+1)))
+
+	require.Error(t, ValidateShare(Share{data: bytes.Repeat([]byte{0}, ShareSize-1)}))
+}
+
+func corruptShare(s Share, index int, value byte) Share {
+	data := bytes.Clone(s.data)
+	data[index] = value
+	return Share{data: data}
+}
+
+func cloneShares(shares []Share) []Share {
+	cloned := make([]Share, len(shares))
+	for i, s := range shares {
+		cloned[i] = Share{data: bytes.Clone(s.data)}
+	}
+	return cloned
+}
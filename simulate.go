@@ -0,0 +1,132 @@
+package square
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// SimulatePlacement runs the blob placement logic of the builder without
+// encoding any shares. It returns the start index of each blob, in the order
+// of blobSizes, and the size of the resulting square. Blob sizes are the
+// lengths of the blob data using share version 0. The simulation assumes the
+// blobs are the only contents of the square so the first blob starts at index
+// 0. Shares occupied by transactions shift, and may realign, the blobs. Blobs
+// are placed using the DefaultSquareVersion rules.
+func SimulatePlacement(blobSizes []int, namespaces []share.Namespace, maxSquareSize, subtreeRootThreshold int) ([]uint32, int, error) {
+	return SimulatePlacementWithVersion(DefaultSquareVersion, blobSizes, namespaces, maxSquareSize, subtreeRootThreshold)
+}
+
+// SimulatePlacementWithVersion behaves like SimulatePlacement but places the
+// blobs according to the rules of the provided square version.
+func SimulatePlacementWithVersion(version SquareVersion, blobSizes []int, namespaces []share.Namespace, maxSquareSize, subtreeRootThreshold int) ([]uint32, int, error) {
+	if err := version.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if len(blobSizes) != len(namespaces) {
+		return nil, 0, fmt.Errorf("got %d blob sizes but %d namespaces", len(blobSizes), len(namespaces))
+	}
+	if maxSquareSize <= 0 || !IsPowerOfTwo(maxSquareSize) {
+		return nil, 0, fmt.Errorf("%w: max square size must be a strictly positive power of two", ErrInvalidMaxSquareSize)
+	}
+
+	elements := make([]*Element, len(blobSizes))
+	currentSize := 0
+	for i, size := range blobSizes {
+		if size <= 0 {
+			return nil, 0, fmt.Errorf("blob size at index %d must be positive", i)
+		}
+		if err := namespaces[i].ValidateForBlob(); err != nil {
+			return nil, 0, fmt.Errorf("namespace at index %d: %w", i, err)
+		}
+		numShares := share.SparseSharesNeeded(uint32(size))
+		elements[i] = &Element{
+			BlobIndex:  i,
+			NumShares:  numShares,
+			MaxPadding: version.maxPadding(numShares, subtreeRootThreshold),
+		}
+		currentSize += elements[i].maxShareOffset()
+	}
+	if currentSize > maxSquareSize*maxSquareSize {
+		return nil, 0, &InsufficientSpaceError{Required: currentSize, Available: maxSquareSize * maxSquareSize}
+	}
+
+	// blobs are ordered by namespace while preserving the order of blobs
+	// within the same namespace, mirroring Builder.Export
+	order := make([]int, len(elements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return bytes.Compare(namespaces[order[i]].Bytes(), namespaces[order[j]].Bytes()) < 0
+	})
+
+	indexes := make([]uint32, len(elements))
+	cursor := 0
+	for _, idx := range order {
+		element := elements[idx]
+		cursor = version.nextShareIndex(cursor, element.NumShares, subtreeRootThreshold)
+		indexes[element.BlobIndex] = uint32(cursor)
+		cursor += element.NumShares
+	}
+
+	return indexes, inclusion.BlobMinSquareSize(currentSize), nil
+}
+
+// Placement describes how a blob transaction would be added to a builder.
+type Placement struct {
+	// RequiredShares is the worst-case number of shares the blob transaction
+	// adds to the square, including the compact shares of its wrapped PFB and
+	// the padding preceding its blobs.
+	RequiredShares int
+	// SquareSize is the upper bound of the size of the square the builder
+	// would export after adding the blob transaction.
+	SquareSize int
+	// ShareIndexes are the estimated start indexes of the blobs, assuming
+	// they are placed after all current contents of the builder. The final
+	// indexes depend on the namespaces of the other blobs in the square.
+	ShareIndexes []uint32
+}
+
+// DryRunBlobTx reports whether the blob transaction would fit into a builder
+// in the state described by the snapshot and where it would be placed,
+// without mutating anything. This allows RPC endpoints to answer whether a
+// candidate blob transaction would be included. If the transaction does not
+// fit, the placement is returned along with an *InsufficientSpaceError.
+func DryRunBlobTx(blobTx *tx.BlobTx, state BuilderSnapshot, maxSquareSize, subtreeRootThreshold int) (Placement, error) {
+	if maxSquareSize <= 0 || !IsPowerOfTwo(maxSquareSize) {
+		return Placement{}, fmt.Errorf("%w: max square size must be a strictly positive power of two", ErrInvalidMaxSquareSize)
+	}
+	if blobTx == nil || len(blobTx.Blobs) == 0 {
+		return Placement{}, fmt.Errorf("%w: blob tx must contain at least one blob", ErrInvalidBlobTx)
+	}
+	for i, blob := range blobTx.Blobs {
+		if err := blob.Namespace().ValidateForBlob(); err != nil {
+			return Placement{}, fmt.Errorf("%w: blob %d: %w", ErrInvalidBlobTx, i, err)
+		}
+	}
+
+	required := blobTx.WorstCaseShares(subtreeRootThreshold)
+	placement := Placement{
+		RequiredShares: required,
+		SquareSize:     inclusion.BlobMinSquareSize(state.UsedShares + required),
+		ShareIndexes:   make([]uint32, len(blobTx.Blobs)),
+	}
+	cursor := state.UsedShares + (required - blobTx.SharesNeeded(subtreeRootThreshold))
+	for i, blob := range blobTx.Blobs {
+		numShares := share.SparseSharesNeeded(blob.SequenceLen())
+		cursor = inclusion.NextShareIndex(cursor, numShares, subtreeRootThreshold)
+		placement.ShareIndexes[i] = uint32(cursor)
+		cursor += numShares
+	}
+
+	maxShares := maxSquareSize * maxSquareSize
+	if state.UsedShares+required > maxShares {
+		return placement, &InsufficientSpaceError{Required: required, Available: maxShares - state.UsedShares}
+	}
+	return placement, nil
+}
@@ -0,0 +1,94 @@
+package square_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatePlacement(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	nineShares := share.FirstSparseShareContentSize + 8*share.ContinuationSparseShareContentSize
+
+	// the 9 share blob sorts first and must be aligned to a multiple of 4
+	indexes, size, err := square.SimulatePlacement([]int{100, nineShares}, []share.Namespace{ns2, ns1}, defaultMaxSquareSize, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{9, 0}, indexes)
+	require.Equal(t, 4, size)
+
+	// blobs of the same namespace keep their order
+	indexes, _, err = square.SimulatePlacement([]int{100, 100, 100}, []share.Namespace{ns2, ns1, ns2}, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{1, 0, 2}, indexes)
+
+	_, _, err = square.SimulatePlacement([]int{100}, nil, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+	_, _, err = square.SimulatePlacement([]int{0}, []share.Namespace{ns1}, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+	_, _, err = square.SimulatePlacement([]int{100}, []share.Namespace{share.TxNamespace}, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+	_, _, err = square.SimulatePlacement([]int{100}, []share.Namespace{ns1}, 3, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+	_, _, err = square.SimulatePlacement([]int{5 * share.ShareSize}, []share.Namespace{ns1}, 2, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+}
+
+func TestSimulatePlacementWithVersion(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	nineShares := share.FirstSparseShareContentSize + 8*share.ContinuationSparseShareContentSize
+	blobSizes, namespaces := []int{100, nineShares}, []share.Namespace{ns1, ns2}
+
+	// the 9 share blob is aligned to its subtree width of 1 in version 2 but
+	// to its minimum square size of 4 in version 1
+	indexes, _, err := square.SimulatePlacementWithVersion(square.SquareVersionTwo, blobSizes, namespaces, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{0, 1}, indexes)
+	indexes, _, err = square.SimulatePlacementWithVersion(square.SquareVersionOne, blobSizes, namespaces, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{0, 4}, indexes)
+
+	_, _, err = square.SimulatePlacementWithVersion(0, blobSizes, namespaces, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+}
+
+func TestDryRunBlobTx(t *testing.T) {
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, generateOrderedTxs(10, 10, 2, 1000)...)
+	require.NoError(t, err)
+	state := builder.Snapshot()
+
+	blobTx, isBlobTx, err := tx.UnmarshalBlobTx(generateBlobTxsWithNamespaces([]share.Namespace{ns1, ns1}, [][]int{{1000, 5000}})[0])
+	require.NoError(t, err)
+	require.True(t, isBlobTx)
+
+	placement, err := square.DryRunBlobTx(blobTx, state, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, blobTx.WorstCaseShares(defaultSubtreeRootThreshold), placement.RequiredShares)
+	require.Len(t, placement.ShareIndexes, 2)
+	require.GreaterOrEqual(t, int(placement.ShareIndexes[0]), state.UsedShares)
+	require.Greater(t, placement.ShareIndexes[1], placement.ShareIndexes[0])
+	// the builder is not mutated
+	require.Equal(t, state, builder.Snapshot())
+
+	// the dry run is an upper bound of the actual usage
+	require.True(t, builder.AppendBlobTx(blobTx))
+	require.LessOrEqual(t, builder.CurrentSize(), state.UsedShares+placement.RequiredShares)
+	dataSquare, err := builder.Export()
+	require.NoError(t, err)
+	require.LessOrEqual(t, dataSquare.Size(), placement.SquareSize)
+
+	// the blob tx does not fit into a small square
+	_, err = square.DryRunBlobTx(blobTx, state, 4, defaultSubtreeRootThreshold)
+	require.ErrorIs(t, err, square.ErrNotEnoughSpace)
+
+	_, err = square.DryRunBlobTx(&tx.BlobTx{Tx: []byte("tx")}, state, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.ErrorIs(t, err, square.ErrInvalidBlobTx)
+	_, err = square.DryRunBlobTx(blobTx, state, 3, defaultSubtreeRootThreshold)
+	require.ErrorIs(t, err, square.ErrInvalidMaxSquareSize)
+}
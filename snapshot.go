@@ -0,0 +1,61 @@
+package square
+
+// BuilderSnapshot describes the share usage of a builder at a point in time.
+// Share counts are the worst-case estimates the builder uses to decide whether
+// a transaction fits, so the exported square may use fewer shares.
+type BuilderSnapshot struct {
+	// MaxShares is the number of shares in a square of the max square size.
+	MaxShares int
+	// UsedShares is the number of shares reserved by all transactions.
+	UsedShares int
+	// TxShares is the number of compact shares used by normal transactions.
+	TxShares int
+	// ISRShares is the number of compact shares used by intermediate state
+	// roots.
+	ISRShares int
+	// PFBShares is the number of compact shares used by wrapped PFBs.
+	PFBShares int
+	// BlobShares is the number of sparse shares reserved for blobs, including
+	// their worst-case padding.
+	BlobShares int
+
+	// Txs is the number of normal transactions.
+	Txs int
+	// PFBs is the number of blob transactions.
+	PFBs int
+	// Blobs is the number of blobs.
+	Blobs int
+	// BlobBytes is the total size of the data of all blobs.
+	BlobBytes int
+}
+
+// AvailableShares returns the number of shares that can still be used.
+func (s BuilderSnapshot) AvailableShares() int {
+	return s.MaxShares - s.UsedShares
+}
+
+// FillRatio returns the ratio of used shares to the max number of shares.
+func (s BuilderSnapshot) FillRatio() float64 {
+	if s.MaxShares == 0 {
+		return 0
+	}
+	return float64(s.UsedShares) / float64(s.MaxShares)
+}
+
+// Snapshot returns the current share usage of the builder. It runs in
+// constant time and can be called after every append.
+func (b *Builder) Snapshot() BuilderSnapshot {
+	compactShares := b.TxCounter.Size() + b.IsrCounter.Size() + b.PfbCounter.Size()
+	return BuilderSnapshot{
+		MaxShares:  b.maxSquareSize * b.maxSquareSize,
+		UsedShares: b.currentSize,
+		TxShares:   b.TxCounter.Size(),
+		ISRShares:  b.IsrCounter.Size(),
+		PFBShares:  b.PfbCounter.Size(),
+		BlobShares: b.currentSize - compactShares,
+		Txs:        len(b.Txs),
+		PFBs:       len(b.Pfbs),
+		Blobs:      len(b.Blobs),
+		BlobBytes:  b.blobBytes,
+	}
+}
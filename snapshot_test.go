@@ -0,0 +1,45 @@
+package square_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderSnapshot(t *testing.T) {
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	snapshot := builder.Snapshot()
+	require.Equal(t, square.BuilderSnapshot{MaxShares: defaultMaxSquareSize * defaultMaxSquareSize}, snapshot)
+	require.Equal(t, snapshot.MaxShares, snapshot.AvailableShares())
+
+	for _, txBytes := range test.GenerateTxs(200, 400, 5) {
+		require.True(t, builder.AppendTx(txBytes))
+	}
+	blobTxs := test.GenerateBlobTxs(3, 2, 1000)
+	for _, txBytes := range blobTxs {
+		blobTx, _, err := tx.UnmarshalBlobTx(txBytes)
+		require.NoError(t, err)
+		require.True(t, builder.AppendBlobTx(blobTx))
+	}
+
+	snapshot = builder.Snapshot()
+	require.Equal(t, builder.CurrentSize(), snapshot.UsedShares)
+	require.Equal(t, snapshot.UsedShares, snapshot.TxShares+snapshot.ISRShares+snapshot.PFBShares+snapshot.BlobShares)
+	require.Equal(t, builder.TxCounter.Size(), snapshot.TxShares)
+	require.Equal(t, builder.PfbCounter.Size(), snapshot.PFBShares)
+	require.Equal(t, 5, snapshot.Txs)
+	require.Equal(t, 3, snapshot.PFBs)
+	require.Equal(t, 6, snapshot.Blobs)
+	require.Equal(t, 6000, snapshot.BlobBytes)
+	require.Equal(t, square.WorstCaseSharesUsed([]int{1000, 1000, 1000, 1000, 1000, 1000}, defaultSubtreeRootThreshold), snapshot.BlobShares)
+	require.InDelta(t, float64(snapshot.UsedShares)/float64(snapshot.MaxShares), snapshot.FillRatio(), 1e-9)
+
+	require.NoError(t, builder.RemoveBlobTx(0))
+	snapshot = builder.Snapshot()
+	require.Equal(t, 2, snapshot.PFBs)
+	require.Equal(t, 4000, snapshot.BlobBytes)
+}
@@ -4,9 +4,11 @@ package square
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"math"
 
+	"github.com/celestiaorg/go-square/v2/layout"
 	"github.com/celestiaorg/go-square/v2/share"
 	"github.com/celestiaorg/go-square/v2/tx"
 	"golang.org/x/exp/constraints"
@@ -18,16 +20,60 @@ import (
 // not check the underlying validity of the transactions.
 // Errors should not occur and would reflect a violation in an invariant.
 func Build(txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Square, [][]byte, error) {
-	builder, err := NewBuilder(maxSquareSize, subtreeRootThreshold)
+	return BuildWithVersion(DefaultSquareVersion, txs, maxSquareSize, subtreeRootThreshold)
+}
+
+// BuildForAppVersion behaves like BuildWithVersion using the square version
+// returned by SquareVersionForAppVersion. As every app version uses
+// SquareVersionTwo, it currently only differs from Build in rejecting app
+// version 0.
+func BuildForAppVersion(appVersion uint64, txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Square, [][]byte, error) {
+	version, err := SquareVersionForAppVersion(appVersion)
 	if err != nil {
 		return nil, nil, err
 	}
-	normalTxs := make([][]byte, 0, len(txs))
-	blobTxs := make([][]byte, 0, len(txs))
-	for idx, txBytes := range txs {
+	return BuildWithVersion(version, txs, maxSquareSize, subtreeRootThreshold)
+}
+
+// BuildWithVersion behaves like Build but places blobs according to the rules
+// of the provided square version.
+func BuildWithVersion(version SquareVersion, txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Square, [][]byte, error) {
+	idx := 0
+	next := func() ([]byte, bool) {
+		if idx == len(txs) {
+			return nil, false
+		}
+		idx++
+		return txs[idx-1], true
+	}
+	return buildFromIterator(version, next, len(txs), maxSquareSize, subtreeRootThreshold)
+}
+
+// BuildFromIterator behaves like Build but pulls the transactions from next
+// until it returns false. This allows callers streaming transactions from a
+// mempool or disk to build a square without materializing all of them up
+// front. Only the transactions included in the square are retained.
+func BuildFromIterator(next func() ([]byte, bool), maxSquareSize, subtreeRootThreshold int) (Square, [][]byte, error) {
+	return buildFromIterator(DefaultSquareVersion, next, 0, maxSquareSize, subtreeRootThreshold)
+}
+
+// buildFromIterator implements BuildWithVersion and BuildFromIterator. The
+// size hint is used to preallocate the list of included transactions.
+func buildFromIterator(version SquareVersion, next func() ([]byte, bool), sizeHint, maxSquareSize, subtreeRootThreshold int) (Square, [][]byte, error) {
+	builder, err := NewBuilderWithVersion(version, maxSquareSize, subtreeRootThreshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	normalTxs := make([][]byte, 0, sizeHint)
+	blobTxs := make([][]byte, 0, sizeHint)
+	for idx := 0; ; idx++ {
+		txBytes, ok := next()
+		if !ok {
+			break
+		}
 		blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
 		if err != nil && isBlobTx {
-			return nil, nil, fmt.Errorf("unmarshalling blob tx at index %d: %w", idx, err)
+			return nil, nil, &TxError{Index: idx, Err: fmt.Errorf("%w: %w", ErrInvalidBlobTx, err)}
 		}
 		if isBlobTx {
 			if builder.AppendBlobTx(blobTx) {
@@ -50,48 +96,207 @@ func Build(txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Square, [][]b
 // Note that this function does not check the underlying validity of
 // the transactions.
 func Construct(txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Square, error) {
-	builder, err := NewBuilder(maxSquareSize, subtreeRootThreshold, txs...)
+	return ConstructWithVersion(DefaultSquareVersion, txs, maxSquareSize, subtreeRootThreshold)
+}
+
+// ConstructWithVersion behaves like Construct but places blobs according to
+// the rules of the provided square version.
+func ConstructWithVersion(version SquareVersion, txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Square, error) {
+	builder, err := NewBuilderWithVersion(version, maxSquareSize, subtreeRootThreshold, txs...)
+	if err != nil {
+		return nil, err
+	}
+	return builder.Export()
+}
+
+// ConstructCtx behaves like Construct but aborts and returns the context's
+// error once the context is done. This allows callers to give up on
+// constructing a square, for example when a consensus timeout fires.
+func ConstructCtx(ctx context.Context, txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Square, error) {
+	builder, err := NewBuilderWithVersion(DefaultSquareVersion, maxSquareSize, subtreeRootThreshold, txs...)
 	if err != nil {
 		return nil, err
 	}
+	return builder.ExportCtx(ctx)
+}
+
+// ConstructWithOptions behaves like Construct but configures the builder with
+// the provided options.
+func ConstructWithOptions(txs [][]byte, maxSquareSize, subtreeRootThreshold int, opts ...BuilderOption) (Square, error) {
+	builder, err := NewBuilderWithOptions(maxSquareSize, subtreeRootThreshold, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if rejection := builder.appendOrderedTxs(txs); rejection != nil {
+		return nil, rejection.Err
+	}
 	return builder.Export()
 }
 
+// CanonicalOrder reorders an arbitrary list of transactions so that all normal
+// transactions precede all blob transactions, as required by Construct. The
+// relative order of transactions within each category is preserved. Fibre
+// transactions are placed in the transaction namespace and are therefore
+// ordered amongst the normal transactions. An error is returned if a
+// transaction is a malformed blob transaction.
+func CanonicalOrder(txs [][]byte) ([][]byte, error) {
+	ordered := make([][]byte, 0, len(txs))
+	blobTxs := make([][]byte, 0)
+	for idx, txBytes := range txs {
+		_, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
+		if err != nil && isBlobTx {
+			return nil, &TxError{Index: idx, Err: fmt.Errorf("%w: %w", ErrInvalidBlobTx, err)}
+		}
+		if isBlobTx {
+			blobTxs = append(blobTxs, txBytes)
+			continue
+		}
+		ordered = append(ordered, txBytes)
+	}
+	return append(ordered, blobTxs...), nil
+}
+
 // Deconstruct takes a square and returns the ordered list of block
 // transactions that constructed that square
 //
 // This method uses the wrapped pfbs in the PFB namespace to identify and
 // decode the blobs. Data that may be included in the square but isn't
 // recognised by the square construction algorithm will be ignored
-func Deconstruct(s Square, decoder PFBDecoder) ([][]byte, error) {
+func Deconstruct(s Square, decoder PFBDecoder, opts ...ReadOption) ([][]byte, error) {
+	contents, err := deconstruct(s, decoder, newReadConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	txs := contents.Txs
+	for _, blobTx := range contents.BlobTxs {
+		txBytes, err := tx.MarshalBlobTxWithHeight(blobTx.Tx, blobTx.HeightHint, blobTx.Blobs...)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, txBytes)
+	}
+	return txs, nil
+}
+
+// BlockContents holds the decoded contents of a square.
+type BlockContents struct {
+	// Txs are the normal transactions, in order, excluding fibre transactions.
+	Txs [][]byte
+	// FibreTxs are the fibre transactions found amongst the normal
+	// transactions along with their system blobs.
+	FibreTxs []*tx.FibreTx
+	// IntermediateStateRoots are the intermediate state roots, if any.
+	IntermediateStateRoots [][]byte
+	// BlobTxs are the blob transactions, in order, with their blobs
+	// re-attached.
+	BlobTxs []*tx.BlobTx
+}
+
+// Rebuild deconstructs the square, drops the transactions at the given
+// indexes and constructs a new square from the remaining transactions. The
+// indexes refer to the list of transactions returned by Deconstruct. This is
+// useful to simulate what a block would look like without certain
+// transactions. Intermediate state roots are not carried over as they would no
+// longer match the transactions.
+func Rebuild(s Square, removeTxIndexes []int, decoder PFBDecoder, maxSquareSize, subtreeRootThreshold int, opts ...ReadOption) (Square, error) {
+	txs, err := Deconstruct(s, decoder, opts...)
+	if err != nil {
+		return nil, err
+	}
+	remove := make(map[int]struct{}, len(removeTxIndexes))
+	for _, idx := range removeTxIndexes {
+		if idx < 0 || idx >= len(txs) {
+			return nil, fmt.Errorf("tx index %d out of range [0, %d)", idx, len(txs))
+		}
+		remove[idx] = struct{}{}
+	}
+	remaining := make([][]byte, 0, len(txs)-len(remove))
+	for idx, txBytes := range txs {
+		if _, ok := remove[idx]; !ok {
+			remaining = append(remaining, txBytes)
+		}
+	}
+	cfg := newReadConfig(opts)
+	return ConstructWithOptions(remaining, maxSquareSize, subtreeRootThreshold, WithReservedNamespaces(cfg.namespaces))
+}
+
+// DeconstructTyped behaves like Deconstruct but returns the decoded contents
+// of the square rather than the raw transactions. Fibre transactions are
+// separated from the normal transactions.
+func DeconstructTyped(s Square, decoder PFBDecoder, opts ...ReadOption) (*BlockContents, error) {
+	contents, err := deconstruct(s, decoder, newReadConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	txs := make([][]byte, 0, len(contents.Txs))
+	for _, txBytes := range contents.Txs {
+		fibreTx, isFibreTx, err := tx.UnmarshalFibreTx(txBytes)
+		if isFibreTx {
+			if err != nil {
+				return nil, err
+			}
+			contents.FibreTxs = append(contents.FibreTxs, fibreTx)
+			continue
+		}
+		txs = append(txs, txBytes)
+	}
+	contents.Txs = txs
+	return contents, nil
+}
+
+// deconstruct parses the transactions, intermediate state roots and blob
+// transactions from the square. Fibre transactions are left amongst the
+// normal transactions.
+func deconstruct(s Square, decoder PFBDecoder, cfg *readConfig) (*BlockContents, error) {
+	if err := cfg.namespaces.Validate(); err != nil {
+		return nil, err
+	}
+	contents := &BlockContents{Txs: [][]byte{}}
 	if s.IsEmpty() {
-		return [][]byte{}, nil
+		return contents, nil
 	}
 
 	// Work out which range of shares are non-pfb transactions
 	// and which ones are pfb transactions
-	txShareRange := share.GetShareRangeForNamespace(s, share.TxNamespace)
+	txShareRange := share.GetShareRangeForNamespace(s, cfg.namespaces.Tx)
 	if txShareRange.Start != 0 {
 		return nil, fmt.Errorf("expected txs to start at index 0, but got %d", txShareRange.Start)
 	}
 
-	wpfbShareRange := share.GetShareRangeForNamespace(s[txShareRange.End:], share.PayForBlobNamespace)
+	// Intermediate state roots, if any, lie between the txs and the pfb
+	// transactions. They are not transactions themselves.
+	pfbSearchStart := txShareRange.End
+	isrShareRange := share.GetShareRangeForNamespace(s[pfbSearchStart:], cfg.namespaces.IntermediateStateRoots)
+	if !isrShareRange.IsEmpty() {
+		if isrShareRange.Start != 0 {
+			return nil, fmt.Errorf("expected intermediate state roots to start directly after non PFBs at index %d, but got %d", pfbSearchStart, isrShareRange.Start)
+		}
+		isrs, err := share.ParseTxs(s[pfbSearchStart : pfbSearchStart+isrShareRange.End])
+		if err != nil {
+			return nil, err
+		}
+		contents.IntermediateStateRoots = isrs
+		pfbSearchStart += isrShareRange.End
+	}
+
+	txs, err := share.ParseTxs(s[txShareRange.Start:txShareRange.End])
+	if err != nil {
+		return nil, err
+	}
+	contents.Txs = txs
+
+	wpfbShareRange := share.GetShareRangeForNamespace(s[pfbSearchStart:], cfg.namespaces.PayForBlob)
 	// If there are no pfb transactions, then we can just return the txs
 	if wpfbShareRange.IsEmpty() {
-		return share.ParseTxs(s[txShareRange.Start:txShareRange.End])
+		return contents, nil
 	}
 
 	// We expect pfb transactions to come directly after non-pfb transactions
+	// and intermediate state roots
 	if wpfbShareRange.Start != 0 {
-		return nil, fmt.Errorf("expected PFBs to start directly after non PFBs at index %d, but got %d", txShareRange.End, wpfbShareRange.Start)
-	}
-	wpfbShareRange.Add(txShareRange.End)
-
-	// Parse both txs
-	txs, err := share.ParseTxs(s[txShareRange.Start:txShareRange.End])
-	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("expected PFBs to start directly after non PFBs at index %d, but got %d", pfbSearchStart, wpfbShareRange.Start)
 	}
+	wpfbShareRange.Add(pfbSearchStart)
 
 	wpfbs, err := share.ParseTxs(s[wpfbShareRange.Start:wpfbShareRange.End])
 	if err != nil {
@@ -118,8 +323,18 @@ func Deconstruct(s Square, decoder PFBDecoder) ([][]byte, error) {
 
 		blobs := make([]*share.Blob, len(wpfb.ShareIndexes))
 		for j, shareIndex := range wpfb.ShareIndexes {
-			end := int(shareIndex) + share.SparseSharesNeeded(blobSizes[j])
-			parsedBlobs, err := share.ParseBlobs(s[shareIndex:end])
+			if int(shareIndex) >= len(s) {
+				return nil, fmt.Errorf("share index %d of wrapped PFB %d is out of range", shareIndex, i)
+			}
+			sequenceLen, err := blobSequenceLen(s[shareIndex], blobSizes[j])
+			if err != nil {
+				return nil, fmt.Errorf("blob at share index %d of wrapped PFB %d: %w", shareIndex, i, err)
+			}
+			end := int(shareIndex) + share.SparseSharesNeeded(sequenceLen)
+			if end > len(s) {
+				return nil, fmt.Errorf("blob at share index %d of wrapped PFB %d exceeds the square", shareIndex, i)
+			}
+			parsedBlobs, err := share.ParseBlobs(s[shareIndex:end], cfg.namespaceOpts...)
 			if err != nil {
 				return nil, err
 			}
@@ -130,14 +345,77 @@ func Deconstruct(s Square, decoder PFBDecoder) ([][]byte, error) {
 			blobs[j] = parsedBlobs[0]
 		}
 
-		txBytes, err := tx.MarshalBlobTx(wpfb.Tx, blobs...)
-		if err != nil {
-			return nil, err
+		contents.BlobTxs = append(contents.BlobTxs, &tx.BlobTx{Tx: wpfb.Tx, Blobs: blobs, HeightHint: wpfb.HeightHint})
+	}
+
+	return contents, nil
+}
+
+// blobSequenceLen returns the sequence length of a blob of blobSize bytes, as
+// reported by its PFB, whose first share is start. The sequence of share
+// version 3 and 4 blobs also includes the header prefixing the data, which is
+// not part of the blob size. The result is checked against the sequence length
+// in the share as the square is untrusted.
+func blobSequenceLen(start share.Share, blobSize uint32) (uint32, error) {
+	sequenceLen := blobSize
+	switch start.Version() {
+	case share.ShareVersionThree:
+		rawData := start.RawData()
+		if len(rawData) == 0 || rawData[0] > share.MaxBlobMetadataSize {
+			return 0, fmt.Errorf("invalid blob metadata header")
 		}
-		txs = append(txs, txBytes)
+		sequenceLen += share.BlobMetadataLenBytes + uint32(rawData[0])
+	case share.ShareVersionFour:
+		sequenceLen += share.BlobCodecBytes
+	}
+	if start.SequenceLen() != sequenceLen {
+		return 0, fmt.Errorf("share has sequence length %d but the PFB implies %d", start.SequenceLen(), sequenceLen)
 	}
+	return sequenceLen, nil
+}
 
-	return txs, nil
+// BlobsBySigner returns all blobs in the square that were signed by signer.
+// Only blobs of share version 1 include their signer. The blobs are found
+// through the share indexes of the wrapped PFBs, so unlike Deconstruct no
+// PFB decoder is needed and only the matching blobs are parsed.
+func BlobsBySigner(s Square, signer []byte, opts ...ReadOption) ([]*share.Blob, error) {
+	if len(signer) != share.SignerSize {
+		return nil, fmt.Errorf("signer must be %d bytes, got %d", share.SignerSize, len(signer))
+	}
+	wpfbs, err := s.WrappedPFBs(opts...)
+	if err != nil {
+		return nil, err
+	}
+	namespaceOpts := newReadConfig(opts).namespaceOpts
+	var blobs []*share.Blob
+	for i, wpfbBytes := range wpfbs {
+		wpfb, isWpfb := tx.UnmarshalIndexWrapper(wpfbBytes)
+		if !isWpfb {
+			return nil, fmt.Errorf("expected wrapped PFB at index %d", i)
+		}
+		for _, shareIndex := range wpfb.ShareIndexes {
+			if int(shareIndex) >= len(s) {
+				return nil, fmt.Errorf("share index %d of wrapped PFB %d is out of range", shareIndex, i)
+			}
+			start := s[shareIndex]
+			if !bytes.Equal(share.GetSigner(start), signer) {
+				continue
+			}
+			end := int(shareIndex) + share.SparseSharesNeeded(start.SequenceLen())
+			if end > len(s) {
+				return nil, fmt.Errorf("blob at share index %d of wrapped PFB %d exceeds the square", shareIndex, i)
+			}
+			parsedBlobs, err := share.ParseBlobs(s[shareIndex:end], namespaceOpts...)
+			if err != nil {
+				return nil, err
+			}
+			if len(parsedBlobs) != 1 {
+				return nil, fmt.Errorf("expected to parse a single blob, but got %d", len(parsedBlobs))
+			}
+			blobs = append(blobs, parsedBlobs[0])
+		}
+	}
+	return blobs, nil
 }
 
 // TxShareRange returns the range of share indexes that the tx, specified by txIndex, occupies.
@@ -181,6 +459,46 @@ func (s Square) Size() int {
 	return Size(len(s))
 }
 
+// ShareAt returns the share at the given row and column of the square.
+func (s Square) ShareAt(row, col int) (share.Share, error) {
+	index, err := s.index(row, col)
+	if err != nil {
+		return share.Share{}, err
+	}
+	return s[index], nil
+}
+
+// RangeForCoordinates returns the range of share indexes, in row-major order,
+// from the share at (startRow, startCol) up to and including the share at
+// (endRow, endCol).
+func (s Square) RangeForCoordinates(startRow, startCol, endRow, endCol int) (share.Range, error) {
+	start, err := s.index(startRow, startCol)
+	if err != nil {
+		return share.Range{}, err
+	}
+	end, err := s.index(endRow, endCol)
+	if err != nil {
+		return share.Range{}, err
+	}
+	if end < start {
+		return share.Range{}, fmt.Errorf("coordinates (%d, %d) come before (%d, %d)", endRow, endCol, startRow, startCol)
+	}
+	return share.NewRange(start, end+1), nil
+}
+
+// index translates a row and column into an index of the square.
+func (s Square) index(row, col int) (int, error) {
+	size := s.Size()
+	if row < 0 || row >= size || col < 0 || col >= size {
+		return 0, fmt.Errorf("coordinates (%d, %d) out of bounds for square of size %d", row, col, size)
+	}
+	index := row*size + col
+	if index >= len(s) {
+		return 0, fmt.Errorf("coordinates (%d, %d) out of bounds for square of %d shares", row, col, len(s))
+	}
+	return index, nil
+}
+
 // Size returns the size of the row or column in shares of a square. This
 // function is currently a wrapper around the da packages equivalent function to
 // avoid breaking the api. In future versions there will not be a copy of this
@@ -191,11 +509,7 @@ func Size(len int) int {
 
 // RoundUpPowerOfTwo returns the next power of two greater than or equal to input.
 func RoundUpPowerOfTwo[I constraints.Integer](input I) I {
-	var result I = 1
-	for result < input {
-		result <<= 1
-	}
-	return result
+	return layout.RoundUpPowerOfTwo(input)
 }
 
 // Equals returns true if two squares are equal
@@ -212,8 +526,9 @@ func (s Square) Equals(other Square) bool {
 }
 
 // WrappedPFBs returns the wrapped PFBs in a square
-func (s Square) WrappedPFBs() ([][]byte, error) {
-	wpfbShareRange := share.GetShareRangeForNamespace(s, share.PayForBlobNamespace)
+func (s Square) WrappedPFBs(opts ...ReadOption) ([][]byte, error) {
+	cfg := newReadConfig(opts)
+	wpfbShareRange := share.GetShareRangeForNamespace(s, cfg.namespaces.PayForBlob)
 	if wpfbShareRange.IsEmpty() {
 		return [][]byte{}, nil
 	}
@@ -229,38 +544,76 @@ func EmptySquare() Square {
 	return share.TailPaddingShares(share.MinShareCount)
 }
 
+// ReservedWriter is a compact share splitter for one of the primary reserved
+// namespaces written before the blobs of a square.
+type ReservedWriter struct {
+	Namespace share.Namespace
+	Writer    *share.CompactShareSplitter
+}
+
+// WriteSquare writes the shares of each reserved writer, in order, followed by
+// reserved padding up to nonReservedStart, the blobs and tail padding. The
+// reserved writers must be in strictly ascending namespace order and use
+// primary reserved namespaces other than the reserved padding namespace.
 func WriteSquare(
-	txWriter, pfbWriter *share.CompactShareSplitter,
+	reserved []ReservedWriter,
+	blobWriter *share.SparseShareSplitter,
+	nonReservedStart, squareSize int,
+) (Square, error) {
+	return writeSquare(reserved, blobWriter, nonReservedStart, squareSize, nil)
+}
+
+// writeSquare implements WriteSquare. If arena is set, all shares of the
+// square are views into it. It must hold squareSize*squareSize shares.
+func writeSquare(
+	reserved []ReservedWriter,
 	blobWriter *share.SparseShareSplitter,
 	nonReservedStart, squareSize int,
+	arena []byte,
 ) (Square, error) {
+	for i, rw := range reserved {
+		if !rw.Namespace.IsPrimaryReserved() || rw.Namespace.IsPrimaryReservedPadding() {
+			return nil, fmt.Errorf("reserved writer %d uses namespace %s which is not a primary reserved namespace", i, rw.Namespace)
+		}
+		if rw.Writer == nil {
+			return nil, fmt.Errorf("reserved writer %d for namespace %s is nil", i, rw.Namespace)
+		}
+		if !rw.Writer.Namespace().Equals(rw.Namespace) {
+			return nil, fmt.Errorf("reserved writer %d writes namespace %s, expected %s", i, rw.Writer.Namespace(), rw.Namespace)
+		}
+		if i > 0 && !reserved[i-1].Namespace.IsLessThan(rw.Namespace) {
+			return nil, fmt.Errorf("reserved namespace %s must be greater than the preceding namespace %s", rw.Namespace, reserved[i-1].Namespace)
+		}
+	}
+
 	totalShares := squareSize * squareSize
-	pfbStartIndex := txWriter.Count()
-	paddingStartIndex := pfbStartIndex + pfbWriter.Count()
+	paddingStartIndex := 0
+	for _, rw := range reserved {
+		paddingStartIndex += rw.Writer.Count()
+	}
 	if nonReservedStart < paddingStartIndex {
-		return nil, fmt.Errorf("nonReservedStart %d is too small to fit all PFBs and txs", nonReservedStart)
+		return nil, fmt.Errorf("nonReservedStart %d is too small to fit all reserved shares", nonReservedStart)
 	}
-	padding := share.ReservedPaddingShares(nonReservedStart - paddingStartIndex)
 	endOfLastBlob := nonReservedStart + blobWriter.Count()
 	if totalShares < endOfLastBlob {
 		return nil, fmt.Errorf("square size %d is too small to fit all blobs", totalShares)
 	}
 
-	txShares, err := txWriter.Export()
-	if err != nil {
-		return nil, fmt.Errorf("failed to export tx shares: %w", err)
-	}
-
-	pfbShares, err := pfbWriter.Export()
-	if err != nil {
-		return nil, fmt.Errorf("failed to export pfb shares: %w", err)
+	if arena != nil {
+		return writeArenaSquare(arena, reserved, blobWriter, paddingStartIndex, nonReservedStart, totalShares)
 	}
 
 	square := make([]share.Share, totalShares)
-	copy(square, txShares)
-	copy(square[pfbStartIndex:], pfbShares)
+	cursor := 0
+	for _, rw := range reserved {
+		shares, err := rw.Writer.Export()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export shares of namespace %s: %w", rw.Namespace, err)
+		}
+		cursor += copy(square[cursor:], shares)
+	}
 	if blobWriter.Count() > 0 {
-		copy(square[paddingStartIndex:], padding)
+		copy(square[paddingStartIndex:], share.ReservedPaddingShares(nonReservedStart-paddingStartIndex))
 		copy(square[nonReservedStart:], blobWriter.Export())
 	}
 	if totalShares > endOfLastBlob {
@@ -270,4 +623,71 @@ func WriteSquare(
 	return square, nil
 }
 
+// writeArenaSquare behaves like writeSquare but returns a square backed by the
+// arena. Shares the splitters did not already write in place, using buffers
+// obtained from arenaRegion, are copied into the arena. Padding shares are
+// written directly into the arena instead of being allocated individually.
+func writeArenaSquare(
+	arena []byte,
+	reserved []ReservedWriter,
+	blobWriter *share.SparseShareSplitter,
+	paddingStartIndex, nonReservedStart, totalShares int,
+) (Square, error) {
+	cursor := 0
+	for _, rw := range reserved {
+		shares, err := rw.Writer.Export()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export shares of namespace %s: %w", rw.Namespace, err)
+		}
+		cursor += copyShares(arena, cursor, shares)
+	}
+	endOfLastBlob := nonReservedStart + blobWriter.Count()
+	if blobWriter.Count() > 0 {
+		fillShares(arena, paddingStartIndex, nonReservedStart, share.ReservedPaddingShare())
+		copyShares(arena, nonReservedStart, blobWriter.Export())
+	}
+	fillShares(arena, endOfLastBlob, totalShares, share.TailPaddingShare())
+
+	square := make(Square, totalShares)
+	for i := range square {
+		// limit the capacity so that appending to a share never overwrites
+		// the next one
+		sh, err := share.NewShareUnsafe(arena[i*share.ShareSize : (i+1)*share.ShareSize : (i+1)*share.ShareSize])
+		if err != nil {
+			return nil, err
+		}
+		square[i] = *sh
+	}
+	return square, nil
+}
+
+// arenaRegion returns the part of the arena holding the shares in
+// [start, end), or nil if arena is nil.
+func arenaRegion(arena []byte, start, end int) []byte {
+	if arena == nil || start >= end {
+		return nil
+	}
+	return arena[start*share.ShareSize : end*share.ShareSize]
+}
+
+// copyShares copies the shares into the arena starting at the share index
+// start and returns the number of shares copied. Shares already located at
+// their index in the arena are skipped.
+func copyShares(arena []byte, start int, shares []share.Share) int {
+	for i, sh := range shares {
+		dst := arena[(start+i)*share.ShareSize:]
+		if data := sh.ToBytes(); &data[0] != &dst[0] {
+			copy(dst, data)
+		}
+	}
+	return len(shares)
+}
+
+// fillShares copies sh into the arena at every share index in [start, end).
+func fillShares(arena []byte, start, end int, sh share.Share) {
+	for i := start; i < end; i++ {
+		copy(arena[i*share.ShareSize:], sh.ToBytes())
+	}
+}
+
 type PFBDecoder func(txBytes []byte) ([]uint32, error)
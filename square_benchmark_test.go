@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/squaretest"
+	"github.com/celestiaorg/go-square/v2/tx"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,3 +49,38 @@ func BenchmarkSquareBuild(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkBuilderThroughput(b *testing.B) {
+	for _, profile := range squaretest.DefaultBenchmarkProfiles() {
+		b.Run(profile.Name(), profile.Run)
+	}
+}
+
+func BenchmarkBuilderExportShareArena(b *testing.B) {
+	txs := generateMixedTxs(100, 100, 2, 10000)
+	for _, arena := range []bool{false, true} {
+		b.Run(fmt.Sprintf("arena=%t", arena), func(b *testing.B) {
+			var opts []square.BuilderOption
+			if arena {
+				opts = append(opts, square.WithShareArena())
+			}
+			builder, err := square.NewBuilderWithOptions(defaultMaxSquareSize, defaultSubtreeRootThreshold, opts...)
+			require.NoError(b, err)
+			for _, txBytes := range txs {
+				blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
+				if isBlobTx {
+					require.NoError(b, err)
+					require.True(b, builder.AppendBlobTx(blobTx))
+				} else {
+					require.True(b, builder.AppendTx(txBytes))
+				}
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := builder.Export()
+				require.NoError(b, err)
+			}
+		})
+	}
+}
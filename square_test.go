@@ -2,12 +2,16 @@ package square_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/celestiaorg/go-square/v2"
 	"github.com/celestiaorg/go-square/v2/internal/test"
 	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/squaretest"
 	"github.com/celestiaorg/go-square/v2/tx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,17 +31,31 @@ func TestSquareConstruction(t *testing.T) {
 		txs = append(txs, append(pfbTxs, txs...)...)
 		_, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
 		require.Error(t, err)
+
+		txs = append(sendTxs[:5:5], append(pfbTxs[:5:5], sendTxs[:5]...)...)
+		_, err = square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.ErrorIs(t, err, square.ErrTxAfterBlobTx)
+		var txErr *square.TxError
+		require.ErrorAs(t, err, &txErr)
+		require.Equal(t, 10, txErr.Index)
 	})
 	t.Run("not enough space to append transactions", func(t *testing.T) {
 		_, err := square.Construct(sendTxs, 2, defaultSubtreeRootThreshold)
-		require.Error(t, err)
+		require.ErrorIs(t, err, square.ErrNotEnoughSpace)
 		_, err = square.Construct(pfbTxs, 2, defaultSubtreeRootThreshold)
-		require.Error(t, err)
+		require.ErrorIs(t, err, square.ErrNotEnoughSpace)
 	})
 	t.Run("construction should fail if a single PFB tx contains a blob that is too large to fit in the square", func(t *testing.T) {
 		pfbTxs := test.GenerateBlobTxs(1, 1, 2*mebibyte)
 		_, err := square.Construct(pfbTxs, 64, defaultSubtreeRootThreshold)
-		require.Error(t, err)
+		var spaceErr *square.InsufficientSpaceError
+		require.ErrorAs(t, err, &spaceErr)
+		require.Equal(t, 64*64, spaceErr.Available)
+		require.Greater(t, spaceErr.Required, spaceErr.Available)
+	})
+	t.Run("invalid max square size", func(t *testing.T) {
+		_, err := square.Construct(sendTxs, 3, defaultSubtreeRootThreshold)
+		require.ErrorIs(t, err, square.ErrInvalidMaxSquareSize)
 	})
 }
 
@@ -164,6 +182,26 @@ func TestSquareDeconstruct(t *testing.T) {
 			})
 		}
 	})
+	t.Run("HeightHint", func(t *testing.T) {
+		blobTx, _, err := tx.UnmarshalBlobTx(test.GenerateBlobTx([]int{1000}))
+		require.NoError(t, err)
+		withHeight, err := tx.MarshalBlobTxWithHeight(blobTx.Tx, 42, blobTx.Blobs...)
+		require.NoError(t, err)
+		txs := [][]byte{test.GenerateRandomTx(100, 200), withHeight}
+
+		dataSquare, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		pfbs, err := dataSquare.WrappedPFBs()
+		require.NoError(t, err)
+		require.Len(t, pfbs, 1)
+		iw, isIndexWrapper := tx.UnmarshalIndexWrapper(pfbs[0])
+		require.True(t, isIndexWrapper)
+		require.Equal(t, uint64(42), iw.HeightHint)
+
+		recomputedTxs, err := square.Deconstruct(dataSquare, test.DecodeMockPFB)
+		require.NoError(t, err)
+		require.Equal(t, txs, recomputedTxs)
+	})
 	t.Run("NoPFBs", func(t *testing.T) {
 		const numTxs = 10
 		txs := test.GenerateTxs(250, 250, numTxs)
@@ -181,6 +219,52 @@ func TestSquareDeconstruct(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, txs, recomputedTxs)
 	})
+	t.Run("ShareVersionThree", func(t *testing.T) {
+		blobSizes := []uint32{100, share.FirstSparseShareContentSize, 2000}
+		txs := make([][]byte, 0, len(blobSizes))
+		for _, size := range blobSizes {
+			blob, err := share.NewV3Blob(share.RandomBlobNamespace(), test.RandomBytes(int(size)), []byte("application/json"))
+			require.NoError(t, err)
+			blobTx, err := tx.MarshalBlobTx(test.MockPFB([]uint32{size}), blob)
+			require.NoError(t, err)
+			txs = append(txs, blobTx)
+		}
+		dataSquare, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		recomputedTxs, err := square.Deconstruct(dataSquare, test.DecodeMockPFB)
+		require.NoError(t, err)
+		require.Equal(t, txs, recomputedTxs)
+	})
+	t.Run("MalformedSequenceLen", func(t *testing.T) {
+		v3Blob, err := share.NewV3Blob(share.RandomBlobNamespace(), test.RandomBytes(1000), []byte("application/json"))
+		require.NoError(t, err)
+		v4Blob, err := share.NewCompressedBlob(share.RandomBlobNamespace(), test.RandomBytes(1000), share.CodecFlate)
+		require.NoError(t, err)
+		for _, blob := range []*share.Blob{v3Blob, v4Blob} {
+			blobTx, err := tx.MarshalBlobTx(test.MockPFB([]uint32{uint32(blob.DataLen())}), blob)
+			require.NoError(t, err)
+			dataSquare, err := square.Construct([][]byte{blobTx}, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+			require.NoError(t, err)
+			wpfbs, err := dataSquare.WrappedPFBs()
+			require.NoError(t, err)
+			wpfb, isWpfb := tx.UnmarshalIndexWrapper(wpfbs[0])
+			require.True(t, isWpfb)
+			deconstructed, err := square.Deconstruct(dataSquare, test.DecodeMockPFB)
+			require.NoError(t, err)
+			require.Equal(t, [][]byte{blobTx}, deconstructed)
+
+			// a proposer may write any sequence length into the first share
+			sequenceLen := dataSquare[wpfb.ShareIndexes[0]].ToBytes()[share.NamespaceSize+share.ShareInfoBytes:]
+			binary.BigEndian.PutUint32(sequenceLen, 1<<30)
+			_, err = square.Deconstruct(dataSquare, test.DecodeMockPFB)
+			require.Error(t, err, blob.ShareVersion())
+
+			// a sequence length matching a blob size beyond the square
+			binary.BigEndian.PutUint32(sequenceLen, 1<<30+blob.SequenceLen()-uint32(blob.DataLen()))
+			_, err = square.Deconstruct(dataSquare, func([]byte) ([]uint32, error) { return []uint32{1 << 30}, nil })
+			require.Error(t, err, blob.ShareVersion())
+		}
+	})
 	t.Run("EmptySquare", func(t *testing.T) {
 		tx, err := square.Deconstruct(square.EmptySquare(), test.DecodeMockPFB)
 		require.NoError(t, err)
@@ -208,3 +292,267 @@ func TestSize(t *testing.T) {
 		assert.True(t, square.IsPowerOfTwo(res))
 	}
 }
+
+func TestSquareCoordinates(t *testing.T) {
+	txs := test.GenerateTxs(250, 250, 40)
+	s, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	size := s.Size()
+	require.Greater(t, size, 2)
+
+	sh, err := s.ShareAt(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, s[size+2], sh)
+
+	for _, coords := range [][2]int{{-1, 0}, {0, -1}, {size, 0}, {0, size}} {
+		_, err := s.ShareAt(coords[0], coords[1])
+		require.Error(t, err)
+	}
+
+	r, err := s.RangeForCoordinates(0, 1, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, share.NewRange(1, size+1), r)
+
+	r, err = s.RangeForCoordinates(0, 0, size-1, size-1)
+	require.NoError(t, err)
+	require.Equal(t, share.NewRange(0, len(s)), r)
+
+	_, err = s.RangeForCoordinates(1, 0, 0, 1)
+	require.Error(t, err)
+	_, err = s.RangeForCoordinates(0, 0, size, 0)
+	require.Error(t, err)
+}
+
+func TestWriteSquareReservedWriters(t *testing.T) {
+	newWriter := func(ns share.Namespace, txs ...[]byte) *share.CompactShareSplitter {
+		w := share.NewCompactShareSplitter(ns, share.ShareVersionZero)
+		for _, txBytes := range txs {
+			require.NoError(t, w.WriteTx(txBytes))
+		}
+		return w
+	}
+	txs := test.GenerateTxs(100, 200, 4)
+	isrs := [][]byte{test.RandomBytes(32)}
+
+	t.Run("writes reserved namespaces in order", func(t *testing.T) {
+		txWriter := newWriter(share.TxNamespace, txs...)
+		isrWriter := newWriter(share.IntermediateStateRootsNamespace, isrs...)
+		dataSquare, err := square.WriteSquare([]square.ReservedWriter{
+			{Namespace: share.TxNamespace, Writer: txWriter},
+			{Namespace: share.IntermediateStateRootsNamespace, Writer: isrWriter},
+		}, share.NewSparseShareSplitter(), txWriter.Count()+isrWriter.Count(), 2)
+		require.NoError(t, err)
+
+		txRange := share.GetShareRangeForNamespace(dataSquare, share.TxNamespace)
+		parsedTxs, err := share.ParseTxs(dataSquare[txRange.Start:txRange.End])
+		require.NoError(t, err)
+		require.Equal(t, txs, parsedTxs)
+		isrRange := share.GetShareRangeForNamespace(dataSquare, share.IntermediateStateRootsNamespace)
+		require.Equal(t, txRange.End, isrRange.Start)
+		parsedRoots, err := share.ParseTxs(dataSquare[isrRange.Start:isrRange.End])
+		require.NoError(t, err)
+		require.Equal(t, isrs, parsedRoots)
+		require.True(t, dataSquare[len(dataSquare)-1].Namespace().IsTailPadding())
+	})
+
+	invalid := map[string][]square.ReservedWriter{
+		"out of order": {
+			{Namespace: share.PayForBlobNamespace, Writer: newWriter(share.PayForBlobNamespace)},
+			{Namespace: share.TxNamespace, Writer: newWriter(share.TxNamespace)},
+		},
+		"duplicate namespace": {
+			{Namespace: share.TxNamespace, Writer: newWriter(share.TxNamespace)},
+			{Namespace: share.TxNamespace, Writer: newWriter(share.TxNamespace)},
+		},
+		"mismatched writer": {
+			{Namespace: share.TxNamespace, Writer: newWriter(share.PayForBlobNamespace)},
+		},
+		"nil writer": {
+			{Namespace: share.TxNamespace},
+		},
+		"reserved padding": {
+			{Namespace: share.PrimaryReservedPaddingNamespace, Writer: newWriter(share.PrimaryReservedPaddingNamespace)},
+		},
+		"not reserved": {
+			{Namespace: test.DefaultTestNamespace, Writer: newWriter(test.DefaultTestNamespace)},
+		},
+	}
+	for name, reserved := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := square.WriteSquare(reserved, share.NewSparseShareSplitter(), 0, 1)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDeconstructTyped(t *testing.T) {
+	gen := squaretest.NewGenerator(1)
+	normalTxs := gen.Txs(100, 200, 3)
+	fibreTx := gen.FibreTx(gen.Namespace())
+	blobTxs := gen.BlobTxs(3, 2, 1000)
+	isrs := gen.Txs(32, 32, 2)
+
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, append(append(normalTxs, fibreTx), blobTxs...)...)
+	require.NoError(t, err)
+	require.True(t, builder.SetIntermediateStateRoots(isrs))
+	dataSquare, err := builder.Export()
+	require.NoError(t, err)
+
+	contents, err := square.DeconstructTyped(dataSquare, squaretest.DecodeMockPFB)
+	require.NoError(t, err)
+	require.Equal(t, normalTxs, contents.Txs)
+	require.Equal(t, isrs, contents.IntermediateStateRoots)
+
+	require.Len(t, contents.FibreTxs, 1)
+	expectedFibreTx, _, err := tx.UnmarshalFibreTx(fibreTx)
+	require.NoError(t, err)
+	require.Equal(t, expectedFibreTx.Tx, contents.FibreTxs[0].Tx)
+	require.Equal(t, expectedFibreTx.SystemBlob.Data(), contents.FibreTxs[0].SystemBlob.Data())
+
+	require.Len(t, contents.BlobTxs, len(blobTxs))
+	for i, blobTx := range contents.BlobTxs {
+		marshalled, err := tx.MarshalBlobTx(blobTx.Tx, blobTx.Blobs...)
+		require.NoError(t, err)
+		require.Equal(t, blobTxs[i], marshalled)
+	}
+
+	// the raw transactions match those returned by Deconstruct
+	txs, err := square.Deconstruct(dataSquare, squaretest.DecodeMockPFB)
+	require.NoError(t, err)
+	require.Equal(t, append(append(normalTxs, fibreTx), blobTxs...), txs)
+
+	empty, err := square.DeconstructTyped(square.EmptySquare(), squaretest.DecodeMockPFB)
+	require.NoError(t, err)
+	require.Empty(t, empty.Txs)
+	require.Empty(t, empty.BlobTxs)
+}
+
+func TestBlobsBySigner(t *testing.T) {
+	gen := squaretest.NewGenerator(2)
+	first := gen.BlobTxWithNamespace([]share.Namespace{gen.Namespace(), gen.Namespace()}, []int{100, 2000}, share.ShareVersionOne)
+	second := gen.BlobTxWithNamespace([]share.Namespace{gen.Namespace()}, []int{500}, share.ShareVersionOne)
+	unsigned := gen.BlobTxs(2, 1, 300)
+	txs := append([][]byte{first, second}, unsigned...)
+	dataSquare, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	firstTx, _, err := tx.UnmarshalBlobTx(first)
+	require.NoError(t, err)
+	secondTx, _, err := tx.UnmarshalBlobTx(second)
+	require.NoError(t, err)
+
+	for _, blobTx := range []*tx.BlobTx{firstTx, secondTx} {
+		blobs, err := square.BlobsBySigner(dataSquare, blobTx.Blobs[0].Signer())
+		require.NoError(t, err)
+		require.Len(t, blobs, len(blobTx.Blobs))
+		for _, expected := range blobTx.Blobs {
+			require.True(t, slices.ContainsFunc(blobs, func(b *share.Blob) bool {
+				return b.Namespace().Equals(expected.Namespace()) && bytes.Equal(b.Data(), expected.Data())
+			}))
+		}
+	}
+
+	blobs, err := square.BlobsBySigner(dataSquare, bytes.Repeat([]byte{1}, share.SignerSize))
+	require.NoError(t, err)
+	require.Empty(t, blobs)
+
+	_, err = square.BlobsBySigner(dataSquare, []byte{1})
+	require.Error(t, err)
+}
+
+func TestBuildFromIterator(t *testing.T) {
+	txs := generateMixedTxs(20, 20, 2, 2000)
+	expectedSquare, expectedTxs, err := square.Build(txs, 8, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	idx := 0
+	next := func() ([]byte, bool) {
+		if idx == len(txs) {
+			return nil, false
+		}
+		idx++
+		return txs[idx-1], true
+	}
+	dataSquare, includedTxs, err := square.BuildFromIterator(next, 8, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expectedSquare, dataSquare)
+	require.Equal(t, expectedTxs, includedTxs)
+	require.Equal(t, len(txs), idx)
+
+	empty, includedTxs, err := square.BuildFromIterator(func() ([]byte, bool) { return nil, false }, 8, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, square.EmptySquare(), empty)
+	require.Empty(t, includedTxs)
+}
+
+func TestConstructCtx(t *testing.T) {
+	txs := generateOrderedTxs(10, 100, 2, 1000)
+	expected, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	dataSquare, err := square.ConstructCtx(context.Background(), txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = square.ConstructCtx(ctx, txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// the builder can still be exported after an aborted export
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, txs...)
+	require.NoError(t, err)
+	_, err = builder.ExportCtx(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	dataSquare, err = builder.Export()
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+}
+
+func TestCanonicalOrder(t *testing.T) {
+	ordered := generateOrderedTxs(10, 20, 2, 1000)
+	mixed := shuffle(append([][]byte{}, ordered...))
+
+	canonical, err := square.CanonicalOrder(mixed)
+	require.NoError(t, err)
+	require.Len(t, canonical, len(ordered))
+
+	// each category keeps the relative order it had in the mixed list
+	var normalTxs, blobTxs [][]byte
+	for _, txBytes := range mixed {
+		if _, isBlobTx, _ := tx.UnmarshalBlobTx(txBytes); isBlobTx {
+			blobTxs = append(blobTxs, txBytes)
+		} else {
+			normalTxs = append(normalTxs, txBytes)
+		}
+	}
+	require.Equal(t, append(normalTxs, blobTxs...), canonical)
+
+	_, err = square.Construct(canonical, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	again, err := square.CanonicalOrder(canonical)
+	require.NoError(t, err)
+	require.Equal(t, canonical, again)
+}
+
+func TestRebuild(t *testing.T) {
+	txs := generateOrderedTxs(5, 5, 1, 800)
+	dataSquare, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	// remove a normal tx and a blob tx
+	rebuilt, err := square.Rebuild(dataSquare, []int{1, 7, 7}, test.DecodeMockPFB, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	remaining := append(append(append([][]byte{}, txs[:1]...), txs[2:7]...), txs[8:]...)
+	expected, err := square.Construct(remaining, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, expected, rebuilt)
+
+	unchanged, err := square.Rebuild(dataSquare, nil, test.DecodeMockPFB, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.Equal(t, dataSquare, unchanged)
+
+	_, err = square.Rebuild(dataSquare, []int{len(txs)}, test.DecodeMockPFB, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+}
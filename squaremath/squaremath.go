@@ -0,0 +1,71 @@
+// Package squaremath contains the generic integer helpers used to compute the
+// layout of data squares and share commitments. It only depends on the
+// standard library and reports overflows as errors instead of wrapping
+// around.
+package squaremath
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOverflow is returned when the result of an operation does not fit in the
+// input type.
+var ErrOverflow = errors.New("integer overflow")
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// IsPowerOfTwo returns true if input is a power of two.
+func IsPowerOfTwo[I Integer](input I) bool {
+	return input&(input-1) == 0 && input > 0
+}
+
+// RoundUpPowerOfTwo returns the next power of two greater than or equal to
+// input. Inputs less than one are rounded up to one. ErrOverflow is returned
+// if the result does not fit in I.
+func RoundUpPowerOfTwo[I Integer](input I) (I, error) {
+	var result I = 1
+	for result < input {
+		next := result << 1
+		if next <= result {
+			return 0, fmt.Errorf("%w: rounding %v up to a power of two", ErrOverflow, input)
+		}
+		result = next
+	}
+	return result, nil
+}
+
+// RoundDownPowerOfTwo returns the next power of two less than or equal to
+// input. input must be positive.
+func RoundDownPowerOfTwo[I Integer](input I) (I, error) {
+	if input <= 0 {
+		return 0, fmt.Errorf("input %v must be positive", input)
+	}
+	var result I = 1
+	for result <= input>>1 {
+		result <<= 1
+	}
+	return result, nil
+}
+
+// RoundUpByMultipleOf rounds cursor up to the next multiple of v. If cursor is
+// divisible by v, then it returns cursor. v must be positive and ErrOverflow is
+// returned if the result does not fit in I.
+func RoundUpByMultipleOf[I Integer](cursor, v I) (I, error) {
+	if v <= 0 {
+		return 0, fmt.Errorf("multiple %v must be positive", v)
+	}
+	if cursor%v == 0 {
+		return cursor, nil
+	}
+	quotient := cursor/v + 1
+	result := quotient * v
+	if result/v != quotient || result < cursor {
+		return 0, fmt.Errorf("%w: rounding %v up to a multiple of %v", ErrOverflow, cursor, v)
+	}
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package squaremath_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/squaremath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPowerOfTwo(t *testing.T) {
+	assert.True(t, squaremath.IsPowerOfTwo(1))
+	assert.True(t, squaremath.IsPowerOfTwo(uint64(1)<<63))
+	assert.False(t, squaremath.IsPowerOfTwo(0))
+	assert.False(t, squaremath.IsPowerOfTwo(6))
+	assert.False(t, squaremath.IsPowerOfTwo(int64(math.MinInt64)))
+}
+
+func TestRoundUpPowerOfTwo(t *testing.T) {
+	testCases := []struct {
+		input int
+		want  int
+	}{
+		{input: -1, want: 1},
+		{input: 0, want: 1},
+		{input: 1, want: 1},
+		{input: 5, want: 8},
+		{input: 511, want: 512},
+		{input: math.MaxInt64 / 2, want: 1 << 62},
+	}
+	for _, tc := range testCases {
+		got, err := squaremath.RoundUpPowerOfTwo(tc.input)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got)
+	}
+
+	got, err := squaremath.RoundUpPowerOfTwo(uint8(128))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(128), got)
+
+	_, err = squaremath.RoundUpPowerOfTwo(uint8(129))
+	require.ErrorIs(t, err, squaremath.ErrOverflow)
+	_, err = squaremath.RoundUpPowerOfTwo(int8(65))
+	require.ErrorIs(t, err, squaremath.ErrOverflow)
+	_, err = squaremath.RoundUpPowerOfTwo(math.MaxInt64)
+	require.ErrorIs(t, err, squaremath.ErrOverflow)
+}
+
+func TestRoundDownPowerOfTwo(t *testing.T) {
+	testCases := []struct {
+		input int64
+		want  int64
+	}{
+		{input: 1, want: 1},
+		{input: 3, want: 2},
+		{input: 8, want: 8},
+		{input: 11, want: 8},
+		{input: math.MaxInt64, want: 1 << 62},
+	}
+	for _, tc := range testCases {
+		got, err := squaremath.RoundDownPowerOfTwo(tc.input)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got)
+	}
+
+	got, err := squaremath.RoundDownPowerOfTwo(uint8(255))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(128), got)
+
+	_, err = squaremath.RoundDownPowerOfTwo(0)
+	require.Error(t, err)
+	_, err = squaremath.RoundDownPowerOfTwo(-4)
+	require.Error(t, err)
+}
+
+func TestRoundUpByMultipleOf(t *testing.T) {
+	testCases := []struct {
+		cursor, v, want int
+	}{
+		{cursor: 0, v: 2, want: 0},
+		{cursor: 1, v: 2, want: 2},
+		{cursor: 5, v: 2, want: 6},
+		{cursor: 33, v: 1, want: 33},
+		{cursor: 33, v: 16, want: 48},
+	}
+	for _, tc := range testCases {
+		got, err := squaremath.RoundUpByMultipleOf(tc.cursor, tc.v)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got)
+	}
+
+	_, err := squaremath.RoundUpByMultipleOf(5, 0)
+	require.Error(t, err)
+	_, err = squaremath.RoundUpByMultipleOf(5, -2)
+	require.Error(t, err)
+	_, err = squaremath.RoundUpByMultipleOf(uint8(250), uint8(16))
+	require.ErrorIs(t, err, squaremath.ErrOverflow)
+	_, err = squaremath.RoundUpByMultipleOf(math.MaxInt64-1, 4)
+	require.ErrorIs(t, err, squaremath.ErrOverflow)
+}
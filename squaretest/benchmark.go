@@ -0,0 +1,79 @@
+package squaretest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// BenchmarkProfile describes a workload for benchmarking the throughput of
+// the square builder.
+type BenchmarkProfile struct {
+	// PFBs is the number of blob transactions appended to the builder.
+	PFBs int
+	// BlobSizes are the sizes of the blobs of the blob transactions. Each
+	// blob transaction pays for a single blob and the sizes are used in turn.
+	BlobSizes            []int
+	MaxSquareSize        int
+	SubtreeRootThreshold int
+}
+
+// DefaultBenchmarkProfiles returns profiles of 100, 1000 and 10000 PFBs with
+// varied blob sizes.
+func DefaultBenchmarkProfiles() []BenchmarkProfile {
+	blobSizes := []int{100, 1_000, 2_000, 10_000}
+	profiles := make([]BenchmarkProfile, 0, 3)
+	for _, pfbs := range []int{100, 1_000, 10_000} {
+		profiles = append(profiles, BenchmarkProfile{
+			PFBs:                 pfbs,
+			BlobSizes:            blobSizes,
+			MaxSquareSize:        512,
+			SubtreeRootThreshold: 64,
+		})
+	}
+	return profiles
+}
+
+// Name returns a name for the profile suitable for b.Run.
+func (p BenchmarkProfile) Name() string {
+	return fmt.Sprintf("pfbs=%d", p.PFBs)
+}
+
+// Run benchmarks appending the blob transactions of the profile to a builder
+// and exporting the square. Besides allocations, it reports the number of
+// shares exported per second.
+func (p BenchmarkProfile) Run(b *testing.B) {
+	g := NewGenerator(int64(p.PFBs))
+	blobTxs := make([]*tx.BlobTx, p.PFBs)
+	for i := range blobTxs {
+		rawTx := g.BlobTx([]int{p.BlobSizes[i%len(p.BlobSizes)]})
+		blobTx, _, err := tx.UnmarshalBlobTx(rawTx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		blobTxs[i] = blobTx
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	shares := 0
+	for i := 0; i < b.N; i++ {
+		builder, err := square.NewBuilder(p.MaxSquareSize, p.SubtreeRootThreshold)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, blobTx := range blobTxs {
+			if !builder.AppendBlobTx(blobTx) {
+				b.Fatalf("profile %s does not fit in a square of size %d", p.Name(), p.MaxSquareSize)
+			}
+		}
+		dataSquare, err := builder.Export()
+		if err != nil {
+			b.Fatal(err)
+		}
+		shares += len(dataSquare)
+	}
+	b.ReportMetric(float64(shares)/b.Elapsed().Seconds(), "shares/s")
+}
@@ -0,0 +1,222 @@
+// Package squaretest contains deterministic generators of transactions and
+// blobs for use in tests and fuzzers of packages building on top of go-square.
+package squaretest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+
+	"github.com/celestiaorg/go-square/v2/fibre"
+	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultNamespace is the namespace used for blobs when none is provided.
+var DefaultNamespace = share.MustNewV0Namespace([]byte("test"))
+
+// MockPFBExtraBytes is the number of random bytes prefixed to the blob sizes
+// in a mock PFB to approximate the size of a real PFB.
+const MockPFBExtraBytes = 329
+
+// Generator generates random transactions and blobs. Generators created with
+// the same seed produce the same output. A Generator is not safe for
+// concurrent use.
+type Generator struct {
+	rand *rand.Rand
+}
+
+// NewGenerator returns a generator seeded with the provided seed.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Bytes returns size random bytes.
+func (g *Generator) Bytes(size int) []byte {
+	b := make([]byte, size)
+	// reading from a math/rand source never fails
+	_, _ = g.rand.Read(b)
+	return b
+}
+
+// Tx returns a random transaction with a size in [minSize, maxSize).
+func (g *Generator) Tx(minSize, maxSize int) []byte {
+	size := minSize
+	if maxSize > minSize {
+		size = g.rand.Intn(maxSize-minSize) + minSize
+	}
+	return g.Bytes(size)
+}
+
+// Txs returns numTxs random transactions with sizes in [minSize, maxSize).
+func (g *Generator) Txs(minSize, maxSize, numTxs int) [][]byte {
+	txs := make([][]byte, numTxs)
+	for i := range txs {
+		txs[i] = g.Tx(minSize, maxSize)
+	}
+	return txs
+}
+
+// Namespace returns a random version 0 namespace that is valid for blobs.
+func (g *Generator) Namespace() share.Namespace {
+	for {
+		ns := share.MustNewV0Namespace(g.Bytes(share.NamespaceVersionZeroIDSize))
+		if err := ns.ValidateForBlob(); err == nil {
+			return ns
+		}
+	}
+}
+
+// Blobs returns a blob of random data in a random namespace for each size.
+func (g *Generator) Blobs(blobSizes ...int) []*share.Blob {
+	blobs := make([]*share.Blob, len(blobSizes))
+	for i, size := range blobSizes {
+		blob, err := share.NewV0Blob(g.Namespace(), g.Bytes(size))
+		if err != nil {
+			panic(err)
+		}
+		blobs[i] = blob
+	}
+	return blobs
+}
+
+// BlobTxWithNamespace returns a blob tx paying for one blob of random data
+// per namespace using the provided share version. The tx is a mock PFB that
+// can be decoded using DecodeMockPFB.
+func (g *Generator) BlobTxWithNamespace(namespaces []share.Namespace, blobSizes []int, version uint8) []byte {
+	if len(namespaces) != len(blobSizes) {
+		panic("number of namespaces should match number of blob sizes")
+	}
+	var signer []byte
+	if version == share.ShareVersionOne {
+		signer = g.Bytes(share.SignerSize)
+	}
+	blobs := make([]*share.Blob, len(blobSizes))
+	for i, size := range blobSizes {
+		blob, err := share.NewBlob(namespaces[i], g.Bytes(size), version, signer)
+		if err != nil {
+			panic(err)
+		}
+		blobs[i] = blob
+	}
+	blobTx, err := tx.MarshalBlobTx(g.MockPFB(toUint32(blobSizes)), blobs...)
+	if err != nil {
+		panic(err)
+	}
+	return blobTx
+}
+
+// BlobTx returns a blob tx paying for blobs in DefaultNamespace.
+func (g *Generator) BlobTx(blobSizes []int) []byte {
+	namespaces := make([]share.Namespace, len(blobSizes))
+	for i := range namespaces {
+		namespaces[i] = DefaultNamespace
+	}
+	return g.BlobTxWithNamespace(namespaces, blobSizes, share.DefaultShareVersion)
+}
+
+// BlobTxs returns numTxs blob txs each paying for blobsPerPfb blobs of
+// blobSize bytes in DefaultNamespace.
+func (g *Generator) BlobTxs(numTxs, blobsPerPfb, blobSize int) [][]byte {
+	blobSizes := make([]int, blobsPerPfb)
+	for i := range blobSizes {
+		blobSizes[i] = blobSize
+	}
+	txs := make([][]byte, numTxs)
+	for i := range txs {
+		txs[i] = g.BlobTx(blobSizes)
+	}
+	return txs
+}
+
+// FibreTx returns a fibre tx whose system blob commits to random fibre data
+// in the provided namespace.
+func (g *Generator) FibreTx(ns share.Namespace) []byte {
+	systemBlob, err := fibre.SystemBlobFromCommitment(ns, 0, g.Bytes(fibre.CommitmentSize), g.Bytes(share.SignerSize))
+	if err != nil {
+		panic(err)
+	}
+	fibreTx, err := tx.MarshalFibreTx(g.Tx(100, 300), systemBlob)
+	if err != nil {
+		panic(err)
+	}
+	return fibreTx
+}
+
+// MalformedBlobTxs returns blob txs that are recognised as blob txs but fail
+// to unmarshal, one for each kind of malformation.
+func (g *Generator) MalformedBlobTxs() [][]byte {
+	valid := func() *v1.BlobProto {
+		ns := g.Namespace()
+		return &v1.BlobProto{
+			NamespaceId:      ns.ID(),
+			NamespaceVersion: uint32(ns.Version()),
+			Data:             g.Bytes(100),
+		}
+	}
+	noData := valid()
+	noData.Data = nil
+	badNamespaceVersion := valid()
+	badNamespaceVersion.NamespaceVersion = uint32(share.NamespaceVersionMax)
+	shortNamespace := valid()
+	shortNamespace.NamespaceId = shortNamespace.NamespaceId[1:]
+	badShareVersion := valid()
+	badShareVersion.ShareVersion = 2
+	signerWithV0 := valid()
+	signerWithV0.Signer = g.Bytes(share.SignerSize)
+
+	malformed := []*v1.BlobTx{
+		{Tx: g.MockPFB([]uint32{100})},
+		{Tx: g.MockPFB([]uint32{0}), Blobs: []*v1.BlobProto{noData}},
+		{Tx: g.MockPFB([]uint32{100}), Blobs: []*v1.BlobProto{badNamespaceVersion}},
+		{Tx: g.MockPFB([]uint32{100}), Blobs: []*v1.BlobProto{shortNamespace}},
+		{Tx: g.MockPFB([]uint32{100}), Blobs: []*v1.BlobProto{badShareVersion}},
+		{Tx: g.MockPFB([]uint32{100}), Blobs: []*v1.BlobProto{signerWithV0}},
+	}
+	txs := make([][]byte, len(malformed))
+	for i, bTx := range malformed {
+		bTx.TypeId = tx.ProtoBlobTxTypeID
+		txBytes, err := proto.Marshal(bTx)
+		if err != nil {
+			panic(err)
+		}
+		txs[i] = txBytes
+	}
+	return txs
+}
+
+// MockPFB returns a mock PFB encoding the provided blob sizes.
+func (g *Generator) MockPFB(blobSizes []uint32) []byte {
+	if len(blobSizes) == 0 {
+		panic("must have at least one blob")
+	}
+	tx := make([]byte, len(blobSizes)*4)
+	for i, size := range blobSizes {
+		binary.BigEndian.PutUint32(tx[i*4:], size)
+	}
+	return append(g.Bytes(MockPFBExtraBytes), tx...)
+}
+
+// DecodeMockPFB decodes the blob sizes of a mock PFB created by MockPFB. It
+// can be used as the PFBDecoder of square.Deconstruct.
+func DecodeMockPFB(pfb []byte) ([]uint32, error) {
+	if len(pfb) < MockPFBExtraBytes+4 {
+		return nil, fmt.Errorf("must have a length of at least %d bytes, got %d", MockPFBExtraBytes+4, len(pfb))
+	}
+	pfb = pfb[MockPFBExtraBytes:]
+	blobSizes := make([]uint32, len(pfb)/4)
+	for i := range blobSizes {
+		blobSizes[i] = binary.BigEndian.Uint32(pfb[i*4 : (i+1)*4])
+	}
+	return blobSizes, nil
+}
+
+func toUint32(arr []int) []uint32 {
+	output := make([]uint32, len(arr))
+	for i, value := range arr {
+		output[i] = uint32(value)
+	}
+	return output
+}
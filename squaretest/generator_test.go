@@ -0,0 +1,64 @@
+package squaretest_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/fibre"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/squaretest"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorIsDeterministic(t *testing.T) {
+	g1, g2 := squaretest.NewGenerator(1), squaretest.NewGenerator(1)
+	require.Equal(t, g1.Txs(10, 100, 5), g2.Txs(10, 100, 5))
+	require.Equal(t, g1.BlobTxs(3, 2, 100), g2.BlobTxs(3, 2, 100))
+	require.Equal(t, g1.Blobs(10, 20), g2.Blobs(10, 20))
+
+	require.NotEqual(t, g1.Bytes(32), squaretest.NewGenerator(2).Bytes(32))
+}
+
+func TestGeneratedTxsConstructSquare(t *testing.T) {
+	g := squaretest.NewGenerator(42)
+	txs := append(g.Txs(100, 300, 10), g.BlobTxs(5, 2, 1000)...)
+	s, err := square.Construct(txs, 64, 64)
+	require.NoError(t, err)
+
+	deconstructed, err := square.Deconstruct(s, squaretest.DecodeMockPFB)
+	require.NoError(t, err)
+	require.Equal(t, txs, deconstructed)
+}
+
+func TestFibreTx(t *testing.T) {
+	g := squaretest.NewGenerator(1)
+	ns := g.Namespace()
+	fibreTx, isFibreTx, err := tx.UnmarshalFibreTx(g.FibreTx(ns))
+	require.NoError(t, err)
+	require.True(t, isFibreTx)
+
+	info, err := fibre.ParseSystemBlob(fibreTx.SystemBlob)
+	require.NoError(t, err)
+	require.Equal(t, ns, info.Namespace)
+}
+
+func TestMalformedBlobTxs(t *testing.T) {
+	g := squaretest.NewGenerator(1)
+	for i, malformed := range g.MalformedBlobTxs() {
+		_, isBlobTx, err := tx.UnmarshalBlobTx(malformed)
+		require.True(t, isBlobTx, i)
+		require.Error(t, err, i)
+
+		_, err = square.Construct([][]byte{malformed}, 64, 64)
+		require.Error(t, err, i)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	g := squaretest.NewGenerator(1)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, g.Namespace().ValidateForBlob())
+	}
+	require.Equal(t, share.NamespaceVersionZero, g.Namespace().Version())
+}
@@ -0,0 +1,64 @@
+package squaretest
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2"
+)
+
+// RoundTripOption configures CheckRoundTrip.
+type RoundTripOption func(*roundTripConfig)
+
+type roundTripConfig struct {
+	decoder square.PFBDecoder
+}
+
+// WithPFBDecoder sets the decoder used to extract the blob sizes from PFBs
+// when deconstructing the square. It defaults to DecodeMockPFB which only
+// understands the PFBs generated by this package.
+func WithPFBDecoder(decoder square.PFBDecoder) RoundTripOption {
+	return func(cfg *roundTripConfig) {
+		cfg.decoder = decoder
+	}
+}
+
+// CheckRoundTrip constructs a square from the ordered txs, validates it and
+// deconstructs it again. It returns an error if any step fails or if the
+// deconstructed txs differ from the original ones. It is intended to be
+// called from property tests and fuzzers.
+func CheckRoundTrip(txs [][]byte, maxSquareSize, subtreeRootThreshold int, opts ...RoundTripOption) error {
+	cfg := &roundTripConfig{decoder: DecodeMockPFB}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	s, err := square.Construct(txs, maxSquareSize, subtreeRootThreshold)
+	if err != nil {
+		return fmt.Errorf("constructing square: %w", err)
+	}
+	if err := square.Validate(s, maxSquareSize, subtreeRootThreshold); err != nil {
+		return fmt.Errorf("validating square: %w", err)
+	}
+	deconstructed, err := square.Deconstruct(s, cfg.decoder)
+	if err != nil {
+		return fmt.Errorf("deconstructing square: %w", err)
+	}
+	if len(deconstructed) != len(txs) {
+		return fmt.Errorf("deconstructed %d txs but constructed the square from %d", len(deconstructed), len(txs))
+	}
+	for i := range txs {
+		if !bytes.Equal(txs[i], deconstructed[i]) {
+			return fmt.Errorf("deconstructed tx at index %d differs from the original", i)
+		}
+	}
+
+	reconstructed, err := square.Construct(deconstructed, maxSquareSize, subtreeRootThreshold)
+	if err != nil {
+		return fmt.Errorf("reconstructing square: %w", err)
+	}
+	if !reconstructed.Equals(s) {
+		return fmt.Errorf("reconstructed square differs from the original")
+	}
+	return nil
+}
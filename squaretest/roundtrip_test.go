@@ -0,0 +1,27 @@
+package squaretest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/squaretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRoundTrip(t *testing.T) {
+	g := squaretest.NewGenerator(7)
+	for i := 0; i < 10; i++ {
+		txs := append(g.Txs(50, 500, 1+i*5), g.BlobTxs(1+i*3, 1+i%3, 100+i*500)...)
+		require.NoError(t, squaretest.CheckRoundTrip(txs, 64, 64))
+	}
+	require.NoError(t, squaretest.CheckRoundTrip(nil, 64, 64))
+
+	// blob txs must come after normal txs
+	txs := append(g.BlobTxs(1, 1, 100), g.Txs(50, 500, 1)...)
+	require.Error(t, squaretest.CheckRoundTrip(txs, 64, 64))
+
+	failingDecoder := func([]byte) ([]uint32, error) { return nil, errors.New("decoder failed") }
+	err := squaretest.CheckRoundTrip(g.BlobTxs(1, 1, 100), 64, 64, squaretest.WithPFBDecoder(failingDecoder))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "decoder failed")
+}
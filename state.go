@@ -0,0 +1,118 @@
+package square
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// builderState is the JSON representation of the state of a builder:
+//
+//	{
+//	  "version":              square version,
+//	  "maxSquareSize":        max square size,
+//	  "subtreeRootThreshold": subtree root threshold,
+//	  "namespaces":           reserved namespaces,
+//	  "txs":                  base64url (unpadded) encoded normal transactions,
+//	  "isrs":                 base64url (unpadded) encoded intermediate state roots,
+//	  "blobTxs":              blob transactions in their canonical JSON representation,
+//	  "currentSize":          worst-case number of shares used by the builder
+//	}
+type builderState struct {
+	Version              SquareVersion      `json:"version"`
+	MaxSquareSize        int                `json:"maxSquareSize"`
+	SubtreeRootThreshold int                `json:"subtreeRootThreshold"`
+	Namespaces           ReservedNamespaces `json:"namespaces"`
+	Txs                  []string           `json:"txs"`
+	Isrs                 []string           `json:"isrs"`
+	BlobTxs              []*tx.BlobTx       `json:"blobTxs"`
+	CurrentSize          int                `json:"currentSize"`
+}
+
+// MarshalState serializes the transactions, intermediate state roots and blob
+// transactions added to the builder along with its configuration, so that a
+// proposer restarting mid-round can resume building its candidate square
+// using RestoreBuilder. Options that can not be serialized, such as limits and
+// the namespace policy, are not included. Fibre txs are restored as normal
+// txs and FibreTxShareRanges derives their ranges from the txs.
+func (b *Builder) MarshalState() ([]byte, error) {
+	state := builderState{
+		Version:              b.version,
+		MaxSquareSize:        b.maxSquareSize,
+		SubtreeRootThreshold: b.subtreeRootThreshold,
+		Namespaces:           b.namespaces,
+		Txs:                  make([]string, len(b.Txs)),
+		Isrs:                 make([]string, len(b.Isrs)),
+		BlobTxs:              make([]*tx.BlobTx, len(b.Pfbs)),
+		CurrentSize:          b.currentSize,
+	}
+	for i, txBytes := range b.Txs {
+		state.Txs[i] = share.EncodeJSONBytes(txBytes)
+	}
+	for i, isr := range b.Isrs {
+		state.Isrs[i] = share.EncodeJSONBytes(isr)
+	}
+	for i, iw := range b.Pfbs {
+		state.BlobTxs[i] = &tx.BlobTx{Tx: iw.Tx, Blobs: make([]*share.Blob, len(iw.ShareIndexes)), HeightHint: iw.HeightHint}
+	}
+	// the blobs may have been reordered by Export so they are mapped back to
+	// the blob txs using their indexes
+	for _, element := range b.Blobs {
+		state.BlobTxs[element.PfbIndex].Blobs[element.BlobIndex] = element.Blob
+	}
+	return json.Marshal(state)
+}
+
+// RestoreBuilder returns a builder in the state serialized by MarshalState.
+// The provided options are applied before the serialized configuration and
+// are used to restore options that are not serialized, such as limits and the
+// namespace policy.
+func RestoreBuilder(data []byte, opts ...BuilderOption) (*Builder, error) {
+	var state builderState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("unmarshaling builder state: %w", err)
+	}
+	opts = append(opts, WithSquareVersion(state.Version), WithReservedNamespaces(state.Namespaces))
+	builder, err := NewBuilderWithOptions(state.MaxSquareSize, state.SubtreeRootThreshold, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// normal txs, intermediate state roots and blob txs are tracked
+	// independently so adding them in this order restores the same state
+	for i, encoded := range state.Txs {
+		txBytes, err := share.DecodeJSONBytes(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decoding tx %d: %w", i, err)
+		}
+		if err := builder.TryAppendTx(txBytes); err != nil {
+			return nil, fmt.Errorf("restoring tx %d: %w", i, err)
+		}
+	}
+	isrs := make([][]byte, len(state.Isrs))
+	for i, encoded := range state.Isrs {
+		if isrs[i], err = share.DecodeJSONBytes(encoded); err != nil {
+			return nil, fmt.Errorf("decoding intermediate state root %d: %w", i, err)
+		}
+	}
+	if len(isrs) > 0 && !builder.SetIntermediateStateRoots(isrs) {
+		return nil, errors.New("restoring intermediate state roots: not enough space")
+	}
+	for i, blobTx := range state.BlobTxs {
+		if blobTx == nil || len(blobTx.Blobs) == 0 || slices.Contains(blobTx.Blobs, nil) {
+			return nil, fmt.Errorf("%w: blob tx %d has no or missing blobs", ErrInvalidBlobTx, i)
+		}
+		if err := builder.TryAppendBlobTx(blobTx); err != nil {
+			return nil, fmt.Errorf("restoring blob tx %d: %w", i, err)
+		}
+	}
+
+	if builder.currentSize != state.CurrentSize {
+		return nil, fmt.Errorf("restored builder uses %d shares but the state recorded %d", builder.currentSize, state.CurrentSize)
+	}
+	return builder, nil
+}
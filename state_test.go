@@ -0,0 +1,94 @@
+package square_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderMarshalState(t *testing.T) {
+	txs := generateOrderedTxs(10, 10, 3, 1000)
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, txs...)
+	require.NoError(t, err)
+	require.True(t, builder.SetIntermediateStateRoots([][]byte{bytes.Repeat([]byte{1}, 32)}))
+
+	state, err := builder.MarshalState()
+	require.NoError(t, err)
+	restored, err := square.RestoreBuilder(state)
+	require.NoError(t, err)
+	require.Equal(t, builder.Snapshot(), restored.Snapshot())
+
+	expected, err := builder.Export()
+	require.NoError(t, err)
+	dataSquare, err := restored.Export()
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+
+	// the state can be marshaled after the blobs have been reordered by Export
+	state, err = builder.MarshalState()
+	require.NoError(t, err)
+	restored, err = square.RestoreBuilder(state)
+	require.NoError(t, err)
+	dataSquare, err = restored.Export()
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+}
+
+func TestBuilderMarshalStateHeightHint(t *testing.T) {
+	blobTx, _, err := tx.UnmarshalBlobTx(test.GenerateBlobTx([]int{1000}))
+	require.NoError(t, err)
+	blobTx.HeightHint = 42
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.True(t, builder.AppendBlobTx(blobTx))
+
+	state, err := builder.MarshalState()
+	require.NoError(t, err)
+	restored, err := square.RestoreBuilder(state)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), restored.Pfbs[0].HeightHint)
+
+	expected, err := builder.Export()
+	require.NoError(t, err)
+	dataSquare, err := restored.Export()
+	require.NoError(t, err)
+	require.Equal(t, expected, dataSquare)
+}
+
+func TestRestoreBuilderInvalidState(t *testing.T) {
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, generateOrderedTxs(2, 2, 1, 100)...)
+	require.NoError(t, err)
+	state, err := builder.MarshalState()
+	require.NoError(t, err)
+
+	modify := func(fn func(state map[string]any)) []byte {
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(state, &decoded))
+		fn(decoded)
+		encoded, err := json.Marshal(decoded)
+		require.NoError(t, err)
+		return encoded
+	}
+
+	testCases := map[string][]byte{
+		"invalid json":        []byte("{"),
+		"invalid square size": modify(func(s map[string]any) { s["maxSquareSize"] = 3 }),
+		"inconsistent size":   modify(func(s map[string]any) { s["currentSize"] = 1 }),
+		"invalid tx":          modify(func(s map[string]any) { s["txs"] = []any{"!"} }),
+		"blob tx without blobs": modify(func(s map[string]any) {
+			s["blobTxs"] = []any{map[string]any{"tx": "", "blobs": []any{}}}
+		}),
+		"square too small": modify(func(s map[string]any) { s["maxSquareSize"] = 1 }),
+	}
+	for name, state := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := square.RestoreBuilder(state)
+			require.Error(t, err)
+		})
+	}
+}
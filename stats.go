@@ -0,0 +1,82 @@
+package square
+
+import (
+	"bytes"
+
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+// SquareStats contains share counts of a square grouped by their use.
+type SquareStats struct {
+	// TotalShares is the number of shares in the square.
+	TotalShares int
+	// TxShares is the number of shares in the transaction namespace.
+	TxShares int
+	// ISRShares is the number of shares in the intermediate state roots
+	// namespace.
+	ISRShares int
+	// PFBShares is the number of shares in the PFB namespace.
+	PFBShares int
+	// BlobShares is the number of blob shares, excluding padding.
+	BlobShares int
+	// BlobSharesPerNamespace contains the number of blob shares, excluding
+	// padding, for each namespace ordered by namespace.
+	BlobSharesPerNamespace []NamespaceShares
+	// ReservedPaddingShares is the number of padding shares between the
+	// reserved namespaces and the first blob.
+	ReservedPaddingShares int
+	// NamespacePaddingShares is the number of padding shares between blobs.
+	NamespacePaddingShares int
+	// TailPaddingShares is the number of padding shares after the last blob.
+	TailPaddingShares int
+	// FillRatio is the ratio of non-padding shares to the total number of
+	// shares.
+	FillRatio float64
+}
+
+// NamespaceShares is the number of shares of a namespace.
+type NamespaceShares struct {
+	Namespace share.Namespace
+	Shares    int
+}
+
+// PaddingShares returns the total number of padding shares in the square.
+func (s SquareStats) PaddingShares() int {
+	return s.ReservedPaddingShares + s.NamespacePaddingShares + s.TailPaddingShares
+}
+
+// Stats counts the shares of the square by their use. It relies on the
+// namespace of each share and does not validate the square.
+func Stats(s Square, opts ...ReadOption) SquareStats {
+	cfg := newReadConfig(opts)
+	stats := SquareStats{TotalShares: len(s)}
+	for _, sh := range s {
+		ns := sh.Namespace()
+		switch {
+		case ns.Equals(cfg.namespaces.Tx):
+			stats.TxShares++
+		case ns.Equals(cfg.namespaces.IntermediateStateRoots):
+			stats.ISRShares++
+		case ns.Equals(cfg.namespaces.PayForBlob):
+			stats.PFBShares++
+		case ns.IsPrimaryReservedPadding():
+			stats.ReservedPaddingShares++
+		case ns.IsTailPadding():
+			stats.TailPaddingShares++
+		case sh.IsPadding():
+			stats.NamespacePaddingShares++
+		default:
+			stats.BlobShares++
+			last := len(stats.BlobSharesPerNamespace) - 1
+			if last < 0 || !bytes.Equal(stats.BlobSharesPerNamespace[last].Namespace.Bytes(), ns.Bytes()) {
+				stats.BlobSharesPerNamespace = append(stats.BlobSharesPerNamespace, NamespaceShares{Namespace: ns})
+				last++
+			}
+			stats.BlobSharesPerNamespace[last].Shares++
+		}
+	}
+	if stats.TotalShares > 0 {
+		stats.FillRatio = float64(stats.TotalShares-stats.PaddingShares()) / float64(stats.TotalShares)
+	}
+	return stats
+}
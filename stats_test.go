@@ -0,0 +1,41 @@
+package square_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	stats := square.Stats(square.EmptySquare())
+	require.Equal(t, square.SquareStats{TotalShares: 1, TailPaddingShares: 1}, stats)
+
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	txs := test.GenerateTxs(250, 250, 4)
+	txs = append(txs, generateBlobTxsWithNamespaces(
+		[]share.Namespace{ns1, ns2, ns2},
+		[][]int{{100}, {1000, 2000}},
+	)...)
+	s, report, err := square.ConstructWithReport(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	stats = square.Stats(s)
+	require.Equal(t, len(s), stats.TotalShares)
+	require.Equal(t, report.Namespaces[0].Shares, stats.TxShares)
+	require.Equal(t, report.Namespaces[1].Shares, stats.PFBShares)
+	require.Equal(t, []square.NamespaceShares{
+		{Namespace: ns1, Shares: 1},
+		{Namespace: ns2, Shares: share.SparseSharesNeeded(1000) + share.SparseSharesNeeded(2000)},
+	}, stats.BlobSharesPerNamespace)
+	require.Equal(t, 1+share.SparseSharesNeeded(1000)+share.SparseSharesNeeded(2000), stats.BlobShares)
+	require.Equal(t, report.ReservedPaddingShares, stats.ReservedPaddingShares)
+	require.Equal(t, report.NamespacePaddingShares, stats.NamespacePaddingShares)
+	require.Equal(t, report.TailPaddingShares, stats.TailPaddingShares)
+	require.Equal(t, stats.TotalShares, stats.TxShares+stats.PFBShares+stats.BlobShares+stats.PaddingShares())
+	require.InDelta(t, float64(stats.TotalShares-stats.PaddingShares())/float64(stats.TotalShares), stats.FillRatio, 1e-9)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/celestiaorg/go-square/v2/layout"
 	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
 	"github.com/celestiaorg/go-square/v2/share"
 	"google.golang.org/protobuf/proto"
@@ -18,11 +19,73 @@ const (
 type BlobTx struct {
 	Tx    []byte
 	Blobs []*share.Blob
+	// HeightHint is an optional block height that is copied to the
+	// IndexWrapper of the PFB when the BlobTx is included in a square. See
+	// NewIndexWrapperWithHeight.
+	HeightHint uint64
+}
+
+// TotalBlobSize returns the sum of the data lengths of all blobs in the BlobTx.
+func (b *BlobTx) TotalBlobSize() int {
+	size := 0
+	for _, blob := range b.Blobs {
+		size += blob.DataLen()
+	}
+	return size
+}
+
+// MaxPaddingFunc returns the worst-case number of padding shares that may
+// precede a blob of numShares shares in the square.
+type MaxPaddingFunc func(numShares int) int
+
+// SharesNeeded returns the number of sparse shares the blobs of this BlobTx
+// may occupy in the square. This includes the worst case padding that may
+// precede each blob in order to comply with the blob share commitment rules
+// of ADR-013 and matches the amount the square builder reserves for the blobs
+// when using these rules. Use SharesNeededWithPadding for other rules.
+func (b *BlobTx) SharesNeeded(subtreeRootThreshold int) int {
+	return b.SharesNeededWithPadding(func(numShares int) int {
+		return layout.MaxPadding(numShares, subtreeRootThreshold)
+	})
+}
+
+// SharesNeededWithPadding behaves like SharesNeeded but uses maxPadding to
+// determine the worst case padding preceding each blob. See
+// square.SquareVersion.MaxPaddingFunc.
+func (b *BlobTx) SharesNeededWithPadding(maxPadding MaxPaddingFunc) int {
+	shares := 0
+	for _, blob := range b.Blobs {
+		numShares := share.SparseSharesNeeded(blob.SequenceLen())
+		shares += numShares + maxPadding(numShares)
+	}
+	return shares
+}
+
+// WorstCaseShares returns an upper bound on the number of shares that this
+// BlobTx can add to a square. On top of SharesNeeded, it accounts for the
+// compact shares used by the wrapped PFB assuming the worst case share indexes.
+func (b *BlobTx) WorstCaseShares(subtreeRootThreshold int) int {
+	return b.pfbShares() + b.SharesNeeded(subtreeRootThreshold)
+}
+
+// WorstCaseSharesWithPadding behaves like WorstCaseShares but uses maxPadding
+// to determine the worst case padding preceding each blob.
+func (b *BlobTx) WorstCaseSharesWithPadding(maxPadding MaxPaddingFunc) int {
+	return b.pfbShares() + b.SharesNeededWithPadding(maxPadding)
+}
+
+// pfbShares returns the compact shares used by the wrapped PFB. A wrapped PFB
+// can never add more shares to the PFB namespace than it would occupy if it
+// were the only one.
+func (b *BlobTx) pfbShares() int {
+	return share.NewCompactShareCounter().Add(WorstCaseIndexWrapperSizeWithHeight(len(b.Tx), len(b.Blobs), b.HeightHint))
 }
 
 // UnmarshalBlobTx attempts to unmarshal a transaction into blob transaction. It returns a boolean
-// If the bytes are of type BlobTx and an error if there is a problem with decoding
-func UnmarshalBlobTx(tx []byte) (*BlobTx, bool, error) {
+// If the bytes are of type BlobTx and an error if there is a problem with decoding.
+// The options control which namespace versions the blobs may use, see
+// share.AllowedNamespaceVersions.
+func UnmarshalBlobTx(tx []byte, opts ...share.NamespaceOption) (*BlobTx, bool, error) {
 	bTx := v1.BlobTx{}
 	err := proto.Unmarshal(tx, &bTx)
 	if err != nil {
@@ -37,14 +100,15 @@ func UnmarshalBlobTx(tx []byte) (*BlobTx, bool, error) {
 	}
 	blobs := make([]*share.Blob, len(bTx.Blobs))
 	for i, b := range bTx.Blobs {
-		blobs[i], err = share.NewBlobFromProto(b)
+		blobs[i], err = share.NewBlobFromProto(b, opts...)
 		if err != nil {
 			return nil, true, err
 		}
 	}
 	return &BlobTx{
-		Tx:    bTx.Tx,
-		Blobs: blobs,
+		Tx:         bTx.Tx,
+		Blobs:      blobs,
+		HeightHint: bTx.HeightHint,
 	}, true, nil
 }
 
@@ -54,6 +118,41 @@ func UnmarshalBlobTx(tx []byte) (*BlobTx, bool, error) {
 // NOTE: Any checks on the blobs or the transaction must be performed in the
 // application
 func MarshalBlobTx(tx []byte, blobs ...*share.Blob) ([]byte, error) {
+	return MarshalBlobTxWithHeight(tx, 0, blobs...)
+}
+
+// MarshalBlobTxWithHeight behaves like MarshalBlobTx but attaches a height
+// hint that is carried over to the IndexWrapper of the PFB. A height of zero
+// means no hint and produces the same encoding as MarshalBlobTx.
+func MarshalBlobTxWithHeight(tx []byte, height uint64, blobs ...*share.Blob) ([]byte, error) {
+	bTx, err := newBlobTxProto(tx, blobs)
+	if err != nil {
+		return nil, err
+	}
+	bTx.HeightHint = height
+	return proto.Marshal(bTx)
+}
+
+// MarshalBlobTxTo behaves like MarshalBlobTx but appends the encoded BlobTx to
+// dst, returning the extended buffer. This allows callers that encode many
+// transactions to reuse a single buffer. An error is returned if the encoded
+// BlobTx would exceed maxSize bytes, in which case dst is returned
+// unmodified. A maxSize of zero or less disables the check.
+func MarshalBlobTxTo(dst []byte, maxSize int, tx []byte, blobs ...*share.Blob) ([]byte, error) {
+	bTx, err := newBlobTxProto(tx, blobs)
+	if err != nil {
+		return dst, err
+	}
+	size := proto.Size(bTx)
+	if maxSize > 0 && size > maxSize {
+		return dst, fmt.Errorf("encoded blob tx size %d exceeds max size %d", size, maxSize)
+	}
+	// the size has already been computed above so there is no need to walk the
+	// message a second time
+	return proto.MarshalOptions{UseCachedSize: true}.MarshalAppend(dst, bTx)
+}
+
+func newBlobTxProto(tx []byte, blobs []*share.Blob) (*v1.BlobTx, error) {
 	if len(blobs) == 0 {
 		return nil, errors.New("at least one blob must be provided")
 	}
@@ -63,12 +162,11 @@ func MarshalBlobTx(tx []byte, blobs ...*share.Blob) ([]byte, error) {
 			return nil, fmt.Errorf("blob %d is nil", i)
 		}
 	}
-	bTx := &v1.BlobTx{
+	return &v1.BlobTx{
 		Tx:     tx,
 		Blobs:  blobsToProto(blobs),
 		TypeId: ProtoBlobTxTypeID,
-	}
-	return proto.Marshal(bTx)
+	}, nil
 }
 
 func blobsToProto(blobs []*share.Blob) []*v1.BlobProto {
@@ -80,7 +178,31 @@ func blobsToProto(blobs []*share.Blob) []*v1.BlobProto {
 			ShareVersion:     uint32(b.ShareVersion()),
 			Signer:           b.Signer(),
 			Data:             b.Data(),
+			Metadata:         b.Metadata(),
+			Codec:            uint32(b.Codec()),
 		}
 	}
 	return pb
 }
+
+// WorstCaseShareIndexes returns the largest possible share indexes for a set of
+// blobs. Largest possible is "worst" in that protobuf uses varints to encode
+// integers, so larger integers can require more bytes to encode.
+//
+// Note: the implementation of this function assumes that the worst case share
+// index is always 128 * 128 to preserve backwards compatibility with
+// celestia-app v1.x.
+func WorstCaseShareIndexes(blobs int) []uint32 {
+	shareIndexes := make([]uint32, blobs)
+	for i := range shareIndexes {
+		shareIndexes[i] = worstCaseShareIndex
+	}
+	return shareIndexes
+}
+
+// worstCaseShareIndex is the largest share index in a square of the square
+// size upper bound of celestia-app v1.x.
+//
+// TODO: de-duplicate this constant with celestia-app SquareSizeUpperBound constant.
+// https://github.com/celestiaorg/celestia-app/blob/a93bb625c6dc0ae6c7c357e9991815a68ab33c79/pkg/appconsts/v1/app_consts.go#L5
+const worstCaseShareIndex = uint32(128 * 128)
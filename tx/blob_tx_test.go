@@ -0,0 +1,119 @@
+package tx_test
+
+import (
+	"testing"
+
+	square "github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalBlobTxTo(t *testing.T) {
+	blobs := test.GenerateBlobs(100, 1000)
+	sdkTx := test.RandomBytes(200)
+
+	expected, err := tx.MarshalBlobTx(sdkTx, blobs...)
+	require.NoError(t, err)
+
+	t.Run("appends to the provided buffer", func(t *testing.T) {
+		prefix := []byte("prefix")
+		dst := make([]byte, len(prefix), len(prefix)+len(expected))
+		copy(dst, prefix)
+
+		out, err := tx.MarshalBlobTxTo(dst, 0, sdkTx, blobs...)
+		require.NoError(t, err)
+		require.Equal(t, prefix, out[:len(prefix)])
+		require.Equal(t, expected, out[len(prefix):])
+
+		blobTx, isBlobTx, err := tx.UnmarshalBlobTx(out[len(prefix):])
+		require.NoError(t, err)
+		require.True(t, isBlobTx)
+		require.Equal(t, sdkTx, blobTx.Tx)
+		require.Len(t, blobTx.Blobs, len(blobs))
+	})
+
+	t.Run("enforces the max blob tx size", func(t *testing.T) {
+		out, err := tx.MarshalBlobTxTo(nil, len(expected), sdkTx, blobs...)
+		require.NoError(t, err)
+		require.Equal(t, expected, out)
+
+		dst := []byte("prefix")
+		out, err = tx.MarshalBlobTxTo(dst, len(expected)-1, sdkTx, blobs...)
+		require.Error(t, err)
+		require.Equal(t, dst, out)
+	})
+
+	t.Run("rejects missing blobs", func(t *testing.T) {
+		_, err := tx.MarshalBlobTxTo(nil, 0, sdkTx)
+		require.Error(t, err)
+		_, err = tx.MarshalBlobTxTo(nil, 0, sdkTx, &share.Blob{})
+		require.Error(t, err)
+	})
+}
+
+func TestMarshalBlobTxWithHeight(t *testing.T) {
+	blobs := test.GenerateBlobs(100)
+	sdkTx := test.RandomBytes(200)
+
+	raw, err := tx.MarshalBlobTxWithHeight(sdkTx, 1000, blobs...)
+	require.NoError(t, err)
+	blobTx, isBlobTx, err := tx.UnmarshalBlobTx(raw)
+	require.NoError(t, err)
+	require.True(t, isBlobTx)
+	require.Equal(t, uint64(1000), blobTx.HeightHint)
+
+	// the builder copies the hint into the IndexWrapper and reserves the
+	// space needed to encode it
+	builder, err := square.NewBuilder(128, 64)
+	require.NoError(t, err)
+	require.True(t, builder.AppendBlobTx(blobTx))
+	require.Equal(t, builder.CurrentSize(), blobTx.WorstCaseShares(64))
+	require.Equal(t, uint64(1000), builder.Pfbs[0].HeightHint)
+
+	// a zero height hint is not encoded
+	withoutHeight, err := tx.MarshalBlobTxWithHeight(sdkTx, 0, blobs...)
+	require.NoError(t, err)
+	legacy, err := tx.MarshalBlobTx(sdkTx, blobs...)
+	require.NoError(t, err)
+	require.Equal(t, legacy, withoutHeight)
+}
+
+func TestBlobTxShareAccounting(t *testing.T) {
+	const subtreeRootThreshold = 64
+	testCases := []struct {
+		name              string
+		blobSizes         []int
+		expectedBlobShare int
+	}{
+		{"single small blob", []int{100}, 1},
+		{"two small blobs", []int{100, 100}, 2},
+		{"one share blob", []int{share.FirstSparseShareContentSize}, 1},
+		{"two share blob", []int{share.FirstSparseShareContentSize + 1}, 2},
+		{"large blob", []int{1_000_000}, 2032},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			blobTx, isBlobTx, err := tx.UnmarshalBlobTx(test.GenerateBlobTx(tc.blobSizes))
+			require.NoError(t, err)
+			require.True(t, isBlobTx)
+
+			totalSize := 0
+			for _, size := range tc.blobSizes {
+				totalSize += size
+			}
+			require.Equal(t, totalSize, blobTx.TotalBlobSize())
+
+			sharesNeeded := blobTx.SharesNeeded(subtreeRootThreshold)
+			require.GreaterOrEqual(t, sharesNeeded, tc.expectedBlobShare)
+
+			// the worst case shares must match what the builder reserves
+			builder, err := square.NewBuilder(128, subtreeRootThreshold)
+			require.NoError(t, err)
+			require.True(t, builder.AppendBlobTx(blobTx))
+			require.Equal(t, builder.CurrentSize(), blobTx.WorstCaseShares(subtreeRootThreshold))
+			require.Greater(t, blobTx.WorstCaseShares(subtreeRootThreshold), sharesNeeded)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package tx
+
+import (
+	"errors"
+
+	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
+	"github.com/celestiaorg/go-square/v2/share"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ProtoFibreTxTypeID is included in each encoded FibreTx to help prevent
+	// decoding binaries that are not actually FibreTxs.
+	ProtoFibreTxTypeID = "FIBR"
+)
+
+// FibreTx is a transaction that pays for fibre data. The data itself is not
+// included in the square, only the system blob which commits to it.
+type FibreTx struct {
+	Tx         []byte
+	SystemBlob *share.Blob
+}
+
+// UnmarshalFibreTx attempts to unmarshal a transaction into a fibre transaction. It returns a boolean
+// If the bytes are of type FibreTx and an error if there is a problem with decoding.
+// The options control which namespace versions the system blob may use, see
+// share.AllowedNamespaceVersions.
+func UnmarshalFibreTx(tx []byte, opts ...share.NamespaceOption) (*FibreTx, bool, error) {
+	fTx := v1.FibreTx{}
+	err := proto.Unmarshal(tx, &fTx)
+	if err != nil {
+		return nil, false, err
+	}
+	// perform some quick basic checks to prevent false positives
+	if fTx.TypeId != ProtoFibreTxTypeID {
+		return nil, false, errors.New("invalid type id")
+	}
+	if fTx.SystemBlob == nil {
+		return nil, true, errors.New("no system blob provided")
+	}
+	systemBlob, err := share.NewBlobFromProto(fTx.SystemBlob, opts...)
+	if err != nil {
+		return nil, true, err
+	}
+	return &FibreTx{
+		Tx:         fTx.Tx,
+		SystemBlob: systemBlob,
+	}, true, nil
+}
+
+// MarshalFibreTx creates a FibreTx using a normal transaction and the system
+// blob it pays for.
+//
+// NOTE: Any checks on the system blob or the transaction must be performed in
+// the application
+func MarshalFibreTx(tx []byte, systemBlob *share.Blob) ([]byte, error) {
+	if systemBlob == nil || systemBlob.IsEmpty() {
+		return nil, errors.New("system blob is nil")
+	}
+	fTx := &v1.FibreTx{
+		Tx:         tx,
+		SystemBlob: blobsToProto([]*share.Blob{systemBlob})[0],
+		TypeId:     ProtoFibreTxTypeID,
+	}
+	return proto.Marshal(fTx)
+}
@@ -0,0 +1,46 @@
+package tx_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFibreTxRoundTrip(t *testing.T) {
+	systemBlob := test.GenerateBlobs(36)[0]
+	sdkTx := test.RandomBytes(200)
+
+	raw, err := tx.MarshalFibreTx(sdkTx, systemBlob)
+	require.NoError(t, err)
+
+	fibreTx, isFibreTx, err := tx.UnmarshalFibreTx(raw)
+	require.NoError(t, err)
+	require.True(t, isFibreTx)
+	require.Equal(t, sdkTx, fibreTx.Tx)
+	require.Equal(t, systemBlob, fibreTx.SystemBlob)
+
+	_, err = tx.MarshalFibreTx(sdkTx, nil)
+	require.Error(t, err)
+	_, err = tx.MarshalFibreTx(sdkTx, &share.Blob{})
+	require.Error(t, err)
+}
+
+func TestFibreTxTypeIDs(t *testing.T) {
+	blobs := test.GenerateBlobs(100)
+	sdkTx := test.RandomBytes(200)
+
+	blobTx, err := tx.MarshalBlobTx(sdkTx, blobs...)
+	require.NoError(t, err)
+	_, isFibreTx, err := tx.UnmarshalFibreTx(blobTx)
+	require.Error(t, err)
+	require.False(t, isFibreTx)
+
+	fibreTx, err := tx.MarshalFibreTx(sdkTx, blobs[0])
+	require.NoError(t, err)
+	_, isBlobTx, err := tx.UnmarshalBlobTx(fibreTx)
+	require.Error(t, err)
+	require.False(t, isBlobTx)
+}
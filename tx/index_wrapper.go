@@ -1,6 +1,10 @@
 package tx
 
 import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/celestiaorg/go-square/v2/proto/blob/v1"
@@ -51,3 +55,81 @@ func NewIndexWrapper(tx []byte, shareIndexes ...uint32) *v1.IndexWrapper {
 		TypeId:       ProtoIndexWrapperTypeID,
 	}
 }
+
+// IndexWrapperSize returns the size of an encoded IndexWrapper of a
+// transaction of txLen bytes with numBlobs share indexes, none of which are
+// larger than maxIndex. If all share indexes equal maxIndex, the size is
+// exact. It is equivalent to, but much cheaper than, calling proto.Size on
+// the IndexWrapper.
+func IndexWrapperSize(txLen, numBlobs int, maxIndex uint32) int {
+	size := 0
+	if txLen > 0 {
+		size += protowire.SizeTag(1) + protowire.SizeBytes(txLen)
+	}
+	if numBlobs > 0 {
+		size += protowire.SizeTag(2) + protowire.SizeBytes(numBlobs*protowire.SizeVarint(uint64(maxIndex)))
+	}
+	return size + protowire.SizeTag(3) + protowire.SizeBytes(len(ProtoIndexWrapperTypeID))
+}
+
+// IndexWrapperSizeWithHeight behaves like IndexWrapperSize for an
+// IndexWrapper carrying the provided height hint. A height of zero is not
+// encoded.
+func IndexWrapperSizeWithHeight(txLen, numBlobs int, maxIndex uint32, height uint64) int {
+	size := IndexWrapperSize(txLen, numBlobs, maxIndex)
+	if height > 0 {
+		size += protowire.SizeTag(4) + protowire.SizeVarint(height)
+	}
+	return size
+}
+
+// WorstCaseIndexWrapperSize returns the size of the IndexWrapper of a
+// transaction of txLen bytes using WorstCaseShareIndexes for numBlobs blobs.
+func WorstCaseIndexWrapperSize(txLen, numBlobs int) int {
+	return WorstCaseIndexWrapperSizeWithHeight(txLen, numBlobs, 0)
+}
+
+// WorstCaseIndexWrapperSizeWithHeight behaves like WorstCaseIndexWrapperSize
+// for an IndexWrapper carrying the provided height hint.
+func WorstCaseIndexWrapperSizeWithHeight(txLen, numBlobs int, height uint64) int {
+	return IndexWrapperSizeWithHeight(txLen, numBlobs, worstCaseShareIndex, height)
+}
+
+// NewIndexWrapperWithHeight creates a new IndexWrapper transaction carrying a
+// height hint, for example the height after which the blobs it pays for may
+// be pruned. A height of zero means no hint and produces the same encoding as
+// NewIndexWrapper.
+func NewIndexWrapperWithHeight(tx []byte, height uint64, shareIndexes ...uint32) *v1.IndexWrapper {
+	indexWrapper := NewIndexWrapper(tx, shareIndexes...)
+	indexWrapper.HeightHint = height
+	return indexWrapper
+}
+
+// RewriteIndexWrapper replaces the share indexes of an encoded IndexWrapper
+// transaction with newIndexes and returns the re-encoded transaction. This is
+// useful for re-anchoring the share indexes of a wrapped PFB after the square
+// it belongs to has been rearranged. The number of new indexes must match the
+// number of existing indexes as each index corresponds to a blob. All other
+// fields, such as the height hint, are preserved.
+func RewriteIndexWrapper(raw []byte, newIndexes []uint32) ([]byte, error) {
+	indexWrapper, isIndexWrapper := UnmarshalIndexWrapper(raw)
+	if !isIndexWrapper {
+		return nil, errors.New("tx is not an index wrapper")
+	}
+	if len(newIndexes) != len(indexWrapper.ShareIndexes) {
+		return nil, fmt.Errorf("expected %d share indexes, got %d", len(indexWrapper.ShareIndexes), len(newIndexes))
+	}
+	indexWrapper.ShareIndexes = newIndexes
+	return proto.Marshal(indexWrapper)
+}
+
+// StripIndexWrapper returns the original transaction wrapped by an encoded
+// IndexWrapper transaction. If raw is not an IndexWrapper transaction, raw is
+// returned unmodified along with false.
+func StripIndexWrapper(raw []byte) ([]byte, bool) {
+	indexWrapper, isIndexWrapper := UnmarshalIndexWrapper(raw)
+	if !isIndexWrapper {
+		return raw, false
+	}
+	return indexWrapper.Tx, true
+}
@@ -0,0 +1,92 @@
+package tx_test
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRewriteIndexWrapper(t *testing.T) {
+	sdkTx := test.RandomBytes(200)
+	raw, err := tx.MarshalIndexWrapper(sdkTx, 4, 8)
+	require.NoError(t, err)
+
+	rewritten, err := tx.RewriteIndexWrapper(raw, []uint32{16, 32})
+	require.NoError(t, err)
+
+	indexWrapper, isIndexWrapper := tx.UnmarshalIndexWrapper(rewritten)
+	require.True(t, isIndexWrapper)
+	require.Equal(t, sdkTx, indexWrapper.Tx)
+	require.Equal(t, []uint32{16, 32}, indexWrapper.ShareIndexes)
+
+	_, err = tx.RewriteIndexWrapper(raw, []uint32{16})
+	require.Error(t, err)
+
+	_, err = tx.RewriteIndexWrapper(sdkTx, []uint32{16, 32})
+	require.Error(t, err)
+}
+
+func TestStripIndexWrapper(t *testing.T) {
+	sdkTx := test.RandomBytes(200)
+	raw, err := tx.MarshalIndexWrapper(sdkTx, 4)
+	require.NoError(t, err)
+
+	stripped, isIndexWrapper := tx.StripIndexWrapper(raw)
+	require.True(t, isIndexWrapper)
+	require.Equal(t, sdkTx, stripped)
+
+	stripped, isIndexWrapper = tx.StripIndexWrapper(sdkTx)
+	require.False(t, isIndexWrapper)
+	require.Equal(t, sdkTx, stripped)
+}
+
+func TestIndexWrapperHeightHint(t *testing.T) {
+	sdkTx := test.RandomBytes(200)
+	withHeight, err := proto.Marshal(tx.NewIndexWrapperWithHeight(sdkTx, 1000, 4, 8))
+	require.NoError(t, err)
+
+	indexWrapper, isIndexWrapper := tx.UnmarshalIndexWrapper(withHeight)
+	require.True(t, isIndexWrapper)
+	require.Equal(t, uint64(1000), indexWrapper.HeightHint)
+	require.Equal(t, []uint32{4, 8}, indexWrapper.ShareIndexes)
+
+	// rewriting the share indexes keeps the height hint
+	rewritten, err := tx.RewriteIndexWrapper(withHeight, []uint32{16, 32})
+	require.NoError(t, err)
+	indexWrapper, _ = tx.UnmarshalIndexWrapper(rewritten)
+	require.Equal(t, uint64(1000), indexWrapper.HeightHint)
+
+	// wrappers without a height hint are encoded as before
+	withoutHeight, err := proto.Marshal(tx.NewIndexWrapperWithHeight(sdkTx, 0, 4, 8))
+	require.NoError(t, err)
+	legacy, err := tx.MarshalIndexWrapper(sdkTx, 4, 8)
+	require.NoError(t, err)
+	require.Equal(t, legacy, withoutHeight)
+	indexWrapper, isIndexWrapper = tx.UnmarshalIndexWrapper(legacy)
+	require.True(t, isIndexWrapper)
+	require.Zero(t, indexWrapper.HeightHint)
+}
+
+func TestIndexWrapperSize(t *testing.T) {
+	for _, txLen := range []int{0, 1, 127, 128, 16383, 16384} {
+		for _, numBlobs := range []int{0, 1, 10, 100} {
+			for _, maxIndex := range []uint32{0, 127, 128, 16384, 1 << 21} {
+				shareIndexes := make([]uint32, numBlobs)
+				for i := range shareIndexes {
+					shareIndexes[i] = maxIndex
+				}
+				iw := tx.NewIndexWrapper(make([]byte, txLen), shareIndexes...)
+				require.Equal(t, proto.Size(iw), tx.IndexWrapperSize(txLen, numBlobs, maxIndex), "txLen %d numBlobs %d maxIndex %d", txLen, numBlobs, maxIndex)
+			}
+		}
+		worstCase := tx.NewIndexWrapper(make([]byte, txLen), tx.WorstCaseShareIndexes(3)...)
+		require.Equal(t, proto.Size(worstCase), tx.WorstCaseIndexWrapperSize(txLen, 3))
+		for _, height := range []uint64{0, 1, 127, 128, 1 << 40} {
+			withHeight := tx.NewIndexWrapperWithHeight(make([]byte, txLen), height, tx.WorstCaseShareIndexes(3)...)
+			require.Equal(t, proto.Size(withHeight), tx.WorstCaseIndexWrapperSizeWithHeight(txLen, 3, height), "txLen %d height %d", txLen, height)
+		}
+	}
+}
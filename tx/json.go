@@ -0,0 +1,151 @@
+package tx
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/celestiaorg/go-square/v2/share"
+)
+
+// blobTxJSON is the canonical JSON representation of a BlobTx:
+//
+//	{
+//	  "tx":         base64url (unpadded) encoded transaction,
+//	  "blobs":      blobs in their canonical JSON representation,
+//	  "heightHint": height hint, omitted if zero
+//	}
+type blobTxJSON struct {
+	Tx         string        `json:"tx"`
+	Blobs      []*share.Blob `json:"blobs"`
+	HeightHint uint64        `json:"heightHint,omitempty"`
+}
+
+// MarshalJSON encodes the blob tx using its canonical JSON representation.
+// The schema is independent of the protobuf definitions and must not change.
+func (b *BlobTx) MarshalJSON() ([]byte, error) {
+	blobs := b.Blobs
+	if blobs == nil {
+		blobs = []*share.Blob{}
+	}
+	return json.Marshal(blobTxJSON{Tx: share.EncodeJSONBytes(b.Tx), Blobs: blobs, HeightHint: b.HeightHint})
+}
+
+// legacyBlobTxJSON is the representation used before the canonical one,
+// which was the default encoding of the struct.
+type legacyBlobTxJSON struct {
+	Tx    []byte
+	Blobs []*share.Blob
+}
+
+// UnmarshalJSON decodes a blob tx from its canonical JSON representation. The
+// legacy representation, with "Tx" and "Blobs" fields and standard base64
+// encoding, is also accepted.
+func (b *BlobTx) UnmarshalJSON(data []byte) error {
+	legacy, err := isLegacyJSON(data)
+	if err != nil {
+		return err
+	}
+	var (
+		tx         []byte
+		blobs      []*share.Blob
+		heightHint uint64
+	)
+	if legacy {
+		var lj legacyBlobTxJSON
+		if err := json.Unmarshal(data, &lj); err != nil {
+			return err
+		}
+		tx, blobs = lj.Tx, lj.Blobs
+	} else {
+		var bj blobTxJSON
+		if err := json.Unmarshal(data, &bj); err != nil {
+			return err
+		}
+		if tx, err = share.DecodeJSONBytes(bj.Tx); err != nil {
+			return err
+		}
+		blobs, heightHint = bj.Blobs, bj.HeightHint
+	}
+	for _, blob := range blobs {
+		if blob == nil {
+			return errors.New("blob can not be null")
+		}
+	}
+	b.Tx, b.Blobs, b.HeightHint = tx, blobs, heightHint
+	return nil
+}
+
+// fibreTxJSON is the canonical JSON representation of a FibreTx:
+//
+//	{
+//	  "tx":         base64url (unpadded) encoded transaction,
+//	  "systemBlob": system blob in its canonical JSON representation
+//	}
+type fibreTxJSON struct {
+	Tx         string      `json:"tx"`
+	SystemBlob *share.Blob `json:"systemBlob"`
+}
+
+// MarshalJSON encodes the fibre tx using its canonical JSON representation.
+// The schema is independent of the protobuf definitions and must not change.
+func (f *FibreTx) MarshalJSON() ([]byte, error) {
+	if f.SystemBlob == nil {
+		return nil, errors.New("system blob is nil")
+	}
+	return json.Marshal(fibreTxJSON{Tx: share.EncodeJSONBytes(f.Tx), SystemBlob: f.SystemBlob})
+}
+
+// legacyFibreTxJSON is the representation used before the canonical one,
+// which was the default encoding of the struct.
+type legacyFibreTxJSON struct {
+	Tx         []byte
+	SystemBlob *share.Blob
+}
+
+// UnmarshalJSON decodes a fibre tx from its canonical JSON representation.
+// The legacy representation, with "Tx" and "SystemBlob" fields and standard
+// base64 encoding, is also accepted.
+func (f *FibreTx) UnmarshalJSON(data []byte) error {
+	legacy, err := isLegacyJSON(data)
+	if err != nil {
+		return err
+	}
+	var (
+		tx         []byte
+		systemBlob *share.Blob
+	)
+	if legacy {
+		var lj legacyFibreTxJSON
+		if err := json.Unmarshal(data, &lj); err != nil {
+			return err
+		}
+		tx, systemBlob = lj.Tx, lj.SystemBlob
+	} else {
+		var fj fibreTxJSON
+		if err := json.Unmarshal(data, &fj); err != nil {
+			return err
+		}
+		if tx, err = share.DecodeJSONBytes(fj.Tx); err != nil {
+			return err
+		}
+		systemBlob = fj.SystemBlob
+	}
+	if systemBlob == nil {
+		return errors.New("no system blob provided")
+	}
+	f.Tx, f.SystemBlob = tx, systemBlob
+	return nil
+}
+
+// isLegacyJSON reports whether data is a JSON object using the exported Go
+// field names of BlobTx or FibreTx, as encoded before the canonical
+// representations were introduced. Field names are compared exactly since
+// encoding/json matches them case insensitively.
+func isLegacyJSON(data []byte) (bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false, err
+	}
+	_, ok := fields["Tx"]
+	return ok, nil
+}
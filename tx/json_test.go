@@ -0,0 +1,103 @@
+package tx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	ns := share.MustNewV0Namespace(bytes.Repeat([]byte{0xab}, share.NamespaceVersionZeroIDSize))
+	nsHex := "00000000000000000000000000000000000000abababababababababab"
+	blob, err := share.NewV1Blob(ns, []byte{0xfb, 0xff}, bytes.Repeat([]byte{1}, share.SignerSize))
+	require.NoError(t, err)
+
+	t.Run("blob tx", func(t *testing.T) {
+		blobTx := &tx.BlobTx{Tx: []byte{0xfe}, Blobs: []*share.Blob{blob}}
+		data, err := json.Marshal(blobTx)
+		require.NoError(t, err)
+		require.Equal(t, `{"tx":"_g","blobs":[{"namespace":"`+nsHex+`","data":"-_8","shareVersion":1,"signer":"AQEBAQEBAQEBAQEBAQEBAQEBAQE"}]}`, string(data))
+
+		decoded := &tx.BlobTx{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		require.Equal(t, blobTx, decoded)
+
+		blobTx.HeightHint = 7
+		data, err = json.Marshal(blobTx)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"heightHint":7`)
+		decoded = &tx.BlobTx{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		require.Equal(t, blobTx, decoded)
+
+		require.Error(t, json.Unmarshal([]byte(`{"tx":"_g","blobs":[null]}`), decoded))
+		require.Error(t, json.Unmarshal([]byte(`{"tx":"/g","blobs":[]}`), decoded))
+	})
+
+	t.Run("fibre tx", func(t *testing.T) {
+		fibreTx := &tx.FibreTx{Tx: []byte{0xfe}, SystemBlob: blob}
+		data, err := json.Marshal(fibreTx)
+		require.NoError(t, err)
+		require.Equal(t, `{"tx":"_g","systemBlob":{"namespace":"`+nsHex+`","data":"-_8","shareVersion":1,"signer":"AQEBAQEBAQEBAQEBAQEBAQEBAQE"}}`, string(data))
+
+		decoded := &tx.FibreTx{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		require.Equal(t, fibreTx, decoded)
+
+		require.Error(t, json.Unmarshal([]byte(`{"tx":"_g"}`), decoded))
+	})
+
+	t.Run("index wrapper", func(t *testing.T) {
+		iw := tx.NewIndexWrapper([]byte{0xfe}, 4, 8)
+		data, err := json.Marshal(iw)
+		require.NoError(t, err)
+		require.Equal(t, `{"tx":"_g","shareIndexes":[4,8],"typeId":"INDX"}`, string(data))
+
+		decoded := &v1.IndexWrapper{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		require.Equal(t, iw.Tx, decoded.Tx)
+		require.Equal(t, iw.ShareIndexes, decoded.ShareIndexes)
+		require.Equal(t, iw.TypeId, decoded.TypeId)
+
+		withHeight := tx.NewIndexWrapperWithHeight([]byte{0xfe}, 7, 4)
+		data, err = json.Marshal(withHeight)
+		require.NoError(t, err)
+		require.Equal(t, `{"tx":"_g","shareIndexes":[4],"typeId":"INDX","heightHint":7}`, string(data))
+		require.NoError(t, json.Unmarshal(data, decoded))
+		require.Equal(t, uint64(7), decoded.HeightHint)
+	})
+}
+
+func TestLegacyJSON(t *testing.T) {
+	ns := share.MustNewV0Namespace(bytes.Repeat([]byte{0xab}, share.NamespaceVersionZeroIDSize))
+	blob, err := share.NewV1Blob(ns, []byte{0xfb, 0xff}, bytes.Repeat([]byte{1}, share.SignerSize))
+	require.NoError(t, err)
+	legacyBlob := `{"namespace_id":"AAAAAAAAAAAAAAAAAAAAAAAAq6urq6urq6urqw==","data":"+/8=","share_version":1,"signer":"AQEBAQEBAQEBAQEBAQEBAQEBAQE="}`
+
+	t.Run("blob tx", func(t *testing.T) {
+		decoded := &tx.BlobTx{}
+		require.NoError(t, json.Unmarshal([]byte(`{"Tx":"/g==","Blobs":[`+legacyBlob+`]}`), decoded))
+		require.Equal(t, &tx.BlobTx{Tx: []byte{0xfe}, Blobs: []*share.Blob{blob}}, decoded)
+	})
+
+	t.Run("fibre tx", func(t *testing.T) {
+		decoded := &tx.FibreTx{}
+		require.NoError(t, json.Unmarshal([]byte(`{"Tx":"/g==","SystemBlob":`+legacyBlob+`}`), decoded))
+		require.Equal(t, &tx.FibreTx{Tx: []byte{0xfe}, SystemBlob: blob}, decoded)
+
+		require.Error(t, json.Unmarshal([]byte(`{"Tx":"/g=="}`), decoded))
+	})
+
+	t.Run("index wrapper", func(t *testing.T) {
+		decoded := &v1.IndexWrapper{}
+		require.NoError(t, json.Unmarshal([]byte(`{"tx":"/g==","share_indexes":[4,8],"type_id":"INDX"}`), decoded))
+		require.Equal(t, []byte{0xfe}, decoded.Tx)
+		require.Equal(t, []uint32{4, 8}, decoded.ShareIndexes)
+		require.Equal(t, "INDX", decoded.TypeId)
+	})
+}
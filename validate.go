@@ -0,0 +1,196 @@
+package square
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// Validate verifies that the square upholds the invariants of square
+// construction. It is the verification counterpart to Construct and checks:
+//   - the square is a power of two and not larger than maxSquareSize
+//   - every share is structurally valid and shares are ordered by namespace
+//   - transactions are followed by PFBs and reserved padding, and only tail
+//     padding follows the last blob
+//   - every blob starts at an index conforming to the blob share commitment
+//     rules
+//   - the share indexes of the wrapped PFBs point to exactly the blobs in the
+//     square
+func Validate(s Square, maxSquareSize, subtreeRootThreshold int, opts ...ReadOption) error {
+	cfg := newReadConfig(opts)
+	if err := cfg.namespaces.Validate(); err != nil {
+		return err
+	}
+	size := s.Size()
+	if len(s) != size*size {
+		return fmt.Errorf("square of %d shares is not a square with a power of two size", len(s))
+	}
+	if size > maxSquareSize {
+		return fmt.Errorf("square size %d exceeds max square size %d", size, maxSquareSize)
+	}
+	if err := share.ValidateShares(s); err != nil {
+		return err
+	}
+
+	blobStart, blobEnd, err := blobRegion(s, cfg.namespaces)
+	if err != nil {
+		return err
+	}
+
+	blobs, ranges, err := share.ParseBlobsWithRanges(s[blobStart:blobEnd])
+	if err != nil {
+		return fmt.Errorf("parsing blobs: %w", err)
+	}
+	blobStarts := make(map[uint32]struct{}, len(blobs))
+	for _, r := range ranges {
+		r.Add(blobStart)
+		width := inclusion.SubTreeWidth(r.End-r.Start, subtreeRootThreshold)
+		if r.Start%width != 0 {
+			return fmt.Errorf("blob at index %d is not aligned to its subtree width %d", r.Start, width)
+		}
+		blobStarts[uint32(r.Start)] = struct{}{}
+	}
+
+	wpfbs, err := s.WrappedPFBs(UseReservedNamespaces(cfg.namespaces))
+	if err != nil {
+		return fmt.Errorf("parsing wrapped PFBs: %w", err)
+	}
+	referenced := 0
+	for i, wpfbBytes := range wpfbs {
+		wpfb, isWpfb := tx.UnmarshalIndexWrapper(wpfbBytes)
+		if !isWpfb {
+			return fmt.Errorf("expected wrapped PFB at index %d", i)
+		}
+		if len(wpfb.ShareIndexes) == 0 {
+			return fmt.Errorf("wrapped PFB %d has no blobs attached", i)
+		}
+		for _, shareIndex := range wpfb.ShareIndexes {
+			if _, ok := blobStarts[shareIndex]; !ok {
+				return fmt.Errorf("share index %d of wrapped PFB %d does not point to the start of a blob", shareIndex, i)
+			}
+			delete(blobStarts, shareIndex)
+			referenced++
+		}
+	}
+	if referenced != len(blobs) {
+		return fmt.Errorf("square contains %d blobs but wrapped PFBs reference %d", len(blobs), referenced)
+	}
+	return nil
+}
+
+// blobRegion returns the start and end, exclusive, of the shares between the
+// reserved namespaces and the tail padding. Shares are ordered by namespace so
+// all blobs lie within this region.
+func blobRegion(s Square, namespaces ReservedNamespaces) (start, end int, err error) {
+	start, end = len(s), len(s)
+	for i := len(s) - 1; i >= 0; i-- {
+		ns := s[i].Namespace()
+		switch {
+		case namespaces.isReserved(ns):
+		case ns.IsTailPadding():
+			end = i
+		case ns.IsReserved():
+			return 0, 0, fmt.Errorf("share %d: unexpected reserved namespace %s", i, ns)
+		default:
+			start = i
+		}
+	}
+	if start > end {
+		start = end
+	}
+	return start, end, nil
+}
+
+// VerifyPFBIndexes recomputes where each blob of the square must start, given
+// the contents of the square and the placement rules of the
+// DefaultSquareVersion, and verifies that the share indexes of the wrapped
+// PFBs agree. Use VerifyPFBIndexesWithVersion for squares built with another
+// square version. Specifically it checks that:
+//   - every blob starts at the first index permitted by the blob share
+//     commitment rules after the end of the previous blob, where the first blob
+//     follows the worst-case number of shares reserved for the PFBs
+//   - every blob is referenced by exactly one share index
+//   - blobs of the same namespace are referenced in the priority order of the
+//     PFBs paying for them
+//
+// This catches a proposer malleating the share indexes of the wrapped PFBs
+// without reconstructing the square. As the PFBs are not decoded, share
+// indexes swapped between blobs of different namespaces are not detected. All
+// errors match ErrInvalidPFBIndexes.
+func VerifyPFBIndexes(s Square, subtreeRootThreshold int, opts ...ReadOption) error {
+	return VerifyPFBIndexesWithVersion(DefaultSquareVersion, s, subtreeRootThreshold, opts...)
+}
+
+// VerifyPFBIndexesWithVersion behaves like VerifyPFBIndexes but recomputes the
+// start of each blob using the placement rules of the provided square version.
+func VerifyPFBIndexesWithVersion(version SquareVersion, s Square, subtreeRootThreshold int, opts ...ReadOption) error {
+	if err := version.Validate(); err != nil {
+		return err
+	}
+	cfg := newReadConfig(opts)
+	if err := cfg.namespaces.Validate(); err != nil {
+		return err
+	}
+	blobStart, blobEnd, err := blobRegion(s, cfg.namespaces)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPFBIndexes, err)
+	}
+	blobs, ranges, err := share.ParseBlobsWithRanges(s[blobStart:blobEnd])
+	if err != nil {
+		return fmt.Errorf("%w: parsing blobs: %w", ErrInvalidPFBIndexes, err)
+	}
+	wpfbs, err := s.WrappedPFBs(opts...)
+	if err != nil {
+		return fmt.Errorf("%w: parsing wrapped PFBs: %w", ErrInvalidPFBIndexes, err)
+	}
+
+	// the builder reserves the worst-case number of shares for the PFBs
+	// before placing the first blob
+	pfbCounter := share.NewCompactShareCounter()
+	// blobRefs maps the start of each blob to the PFB and blob index
+	// referencing it
+	type blobRef struct{ pfbIndex, blobIndex int }
+	blobRefs := make(map[int]blobRef, len(blobs))
+	for i, wpfbBytes := range wpfbs {
+		wpfb, isWpfb := tx.UnmarshalIndexWrapper(wpfbBytes)
+		if !isWpfb {
+			return fmt.Errorf("%w: expected wrapped PFB at index %d", ErrInvalidPFBIndexes, i)
+		}
+		pfbCounter.Add(tx.WorstCaseIndexWrapperSizeWithHeight(len(wpfb.Tx), len(wpfb.ShareIndexes), wpfb.HeightHint))
+		for j, shareIndex := range wpfb.ShareIndexes {
+			if _, ok := blobRefs[int(shareIndex)]; ok {
+				return fmt.Errorf("%w: share index %d is referenced more than once", ErrInvalidPFBIndexes, shareIndex)
+			}
+			blobRefs[int(shareIndex)] = blobRef{pfbIndex: i, blobIndex: j}
+		}
+	}
+	if len(blobRefs) != len(blobs) {
+		return fmt.Errorf("%w: square contains %d blobs but wrapped PFBs reference %d", ErrInvalidPFBIndexes, len(blobs), len(blobRefs))
+	}
+
+	txShares := share.GetShareRangeForNamespace(s, cfg.namespaces.Tx)
+	isrShares := share.GetShareRangeForNamespace(s, cfg.namespaces.IntermediateStateRoots)
+	cursor := (txShares.End - txShares.Start) + (isrShares.End - isrShares.Start) + pfbCounter.Size()
+	var last blobRef
+	for i, r := range ranges {
+		r.Add(blobStart)
+		cursor = version.nextShareIndex(cursor, r.End-r.Start, subtreeRootThreshold)
+		if r.Start != cursor {
+			return fmt.Errorf("%w: blob %d starts at index %d but should start at %d", ErrInvalidPFBIndexes, i, r.Start, cursor)
+		}
+		cursor = r.End
+
+		ref, ok := blobRefs[r.Start]
+		if !ok {
+			return fmt.Errorf("%w: blob at index %d is not referenced by a wrapped PFB", ErrInvalidPFBIndexes, r.Start)
+		}
+		if i > 0 && blobs[i].Namespace().Equals(blobs[i-1].Namespace()) &&
+			(ref.pfbIndex < last.pfbIndex || (ref.pfbIndex == last.pfbIndex && ref.blobIndex < last.blobIndex)) {
+			return fmt.Errorf("%w: blob at index %d is referenced out of priority order by wrapped PFB %d", ErrInvalidPFBIndexes, r.Start, ref.pfbIndex)
+		}
+		last = ref
+	}
+	return nil
+}
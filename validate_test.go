@@ -0,0 +1,158 @@
+package square_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	txs := test.GenerateTxs(250, 250, 10)
+	txs = append(txs, test.GenerateBlobTxs(10, 2, 2000)...)
+	s, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+
+	require.NoError(t, square.Validate(s, defaultMaxSquareSize, defaultSubtreeRootThreshold))
+	require.NoError(t, square.Validate(square.EmptySquare(), defaultMaxSquareSize, defaultSubtreeRootThreshold))
+
+	t.Run("exceeds max square size", func(t *testing.T) {
+		require.Error(t, square.Validate(s, s.Size()/2, defaultSubtreeRootThreshold))
+	})
+
+	t.Run("not a square", func(t *testing.T) {
+		require.Error(t, square.Validate(s[:len(s)-1], defaultMaxSquareSize, defaultSubtreeRootThreshold))
+	})
+
+	t.Run("blob after tail padding", func(t *testing.T) {
+		tailPadding := share.GetShareRangeForNamespace(s, share.TailPaddingNamespace)
+		require.False(t, tailPadding.IsEmpty())
+		mutated := append(square.Square{}, s...)
+		mutated[len(mutated)-1] = mutated[tailPadding.Start-1]
+		require.Error(t, square.Validate(mutated, defaultMaxSquareSize, defaultSubtreeRootThreshold))
+	})
+
+	t.Run("wrapped PFB points to the wrong share", func(t *testing.T) {
+		wpfbs, err := s.WrappedPFBs()
+		require.NoError(t, err)
+		pfbRange := share.GetShareRangeForNamespace(s, share.PayForBlobNamespace)
+
+		pfbWriter := share.NewCompactShareSplitter(share.PayForBlobNamespace, share.ShareVersionZero)
+		for i, wpfbBytes := range wpfbs {
+			if i == 0 {
+				wpfb, _ := tx.UnmarshalIndexWrapper(wpfbBytes)
+				indexes := append([]uint32{}, wpfb.ShareIndexes...)
+				indexes[0]++
+				wpfbBytes, err = tx.RewriteIndexWrapper(wpfbBytes, indexes)
+				require.NoError(t, err)
+			}
+			require.NoError(t, pfbWriter.WriteTx(wpfbBytes))
+		}
+		pfbShares, err := pfbWriter.Export()
+		require.NoError(t, err)
+		require.Len(t, pfbShares, pfbRange.End-pfbRange.Start)
+
+		mutated := append(square.Square{}, s...)
+		copy(mutated[pfbRange.Start:], pfbShares)
+		require.Error(t, square.Validate(mutated, defaultMaxSquareSize, defaultSubtreeRootThreshold))
+	})
+}
+
+// rewritePFBIndexes returns a copy of the square in which the share indexes
+// of the wrapped PFBs are replaced by those returned by rewrite.
+func rewritePFBIndexes(t *testing.T, s square.Square, rewrite func(indexes [][]uint32)) square.Square {
+	wpfbs, err := s.WrappedPFBs()
+	require.NoError(t, err)
+	indexes := make([][]uint32, len(wpfbs))
+	for i, wpfbBytes := range wpfbs {
+		wpfb, isWpfb := tx.UnmarshalIndexWrapper(wpfbBytes)
+		require.True(t, isWpfb)
+		indexes[i] = append([]uint32{}, wpfb.ShareIndexes...)
+	}
+	rewrite(indexes)
+
+	pfbWriter := share.NewCompactShareSplitter(share.PayForBlobNamespace, share.ShareVersionZero)
+	for i, wpfbBytes := range wpfbs {
+		wpfbBytes, err = tx.RewriteIndexWrapper(wpfbBytes, indexes[i])
+		require.NoError(t, err)
+		require.NoError(t, pfbWriter.WriteTx(wpfbBytes))
+	}
+	pfbShares, err := pfbWriter.Export()
+	require.NoError(t, err)
+	pfbRange := share.GetShareRangeForNamespace(s, share.PayForBlobNamespace)
+	require.Len(t, pfbShares, pfbRange.End-pfbRange.Start)
+
+	mutated := append(square.Square{}, s...)
+	copy(mutated[pfbRange.Start:], pfbShares)
+	return mutated
+}
+
+func TestVerifyPFBIndexes(t *testing.T) {
+	require.NoError(t, square.VerifyPFBIndexes(square.EmptySquare(), defaultSubtreeRootThreshold))
+
+	txs := test.GenerateTxs(250, 250, 10)
+	txs = append(txs, test.GenerateBlobTxs(10, 2, 2000)...)
+	s, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.NoError(t, square.VerifyPFBIndexes(s, defaultSubtreeRootThreshold))
+
+	builder, err := square.NewBuilder(defaultMaxSquareSize, defaultSubtreeRootThreshold, generateOrderedTxs(20, 30, 3, 700)...)
+	require.NoError(t, err)
+	require.True(t, builder.SetIntermediateStateRoots(test.GenerateTxs(32, 32, 5)))
+	withISRs, err := builder.Export()
+	require.NoError(t, err)
+	require.NoError(t, square.VerifyPFBIndexes(withISRs, defaultSubtreeRootThreshold))
+
+	ns1 := share.MustNewV0Namespace(bytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(bytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	txs = generateBlobTxsWithNamespaces([]share.Namespace{ns1, ns2, ns1}, [][]int{{1000, 3000}, {500}})
+	s, err = square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.NoError(t, square.VerifyPFBIndexes(s, defaultSubtreeRootThreshold))
+
+	t.Run("square version one", func(t *testing.T) {
+		// blobs of 7 shares are aligned to 4 shares in version 1 but to 1
+		// share in version 2
+		txs := generateOrderedTxs(5, 5, 1, 3000)
+		v1Square, err := square.ConstructWithVersion(square.SquareVersionOne, txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		require.NoError(t, square.VerifyPFBIndexesWithVersion(square.SquareVersionOne, v1Square, defaultSubtreeRootThreshold))
+		require.ErrorIs(t, square.VerifyPFBIndexes(v1Square, defaultSubtreeRootThreshold), square.ErrInvalidPFBIndexes)
+
+		v2Square, err := square.Construct(txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		require.ErrorIs(t, square.VerifyPFBIndexesWithVersion(square.SquareVersionOne, v2Square, defaultSubtreeRootThreshold), square.ErrInvalidPFBIndexes)
+
+		require.Error(t, square.VerifyPFBIndexesWithVersion(0, v1Square, defaultSubtreeRootThreshold))
+	})
+
+	t.Run("swapped within a namespace", func(t *testing.T) {
+		mutated := rewritePFBIndexes(t, s, func(indexes [][]uint32) {
+			indexes[0][0], indexes[1][0] = indexes[1][0], indexes[0][0]
+		})
+		require.NoError(t, square.Validate(mutated, defaultMaxSquareSize, defaultSubtreeRootThreshold))
+		require.ErrorIs(t, square.VerifyPFBIndexes(mutated, defaultSubtreeRootThreshold), square.ErrInvalidPFBIndexes)
+	})
+
+	t.Run("duplicate index", func(t *testing.T) {
+		mutated := rewritePFBIndexes(t, s, func(indexes [][]uint32) {
+			indexes[1][0] = indexes[0][0]
+		})
+		require.ErrorIs(t, square.VerifyPFBIndexes(mutated, defaultSubtreeRootThreshold), square.ErrInvalidPFBIndexes)
+	})
+
+	t.Run("index not at a blob start", func(t *testing.T) {
+		mutated := rewritePFBIndexes(t, s, func(indexes [][]uint32) {
+			indexes[0][1]++
+		})
+		require.ErrorIs(t, square.VerifyPFBIndexes(mutated, defaultSubtreeRootThreshold), square.ErrInvalidPFBIndexes)
+	})
+
+	t.Run("wrong threshold", func(t *testing.T) {
+		require.ErrorIs(t, square.VerifyPFBIndexes(s, 1), square.ErrInvalidPFBIndexes)
+	})
+}
@@ -0,0 +1,186 @@
+// Package vectors generates and checks canonical test vectors for square
+// construction. A vector is a list of transactions along with the square that
+// go-square constructs from them, allowing implementations in other languages
+// to verify that they construct identical squares.
+package vectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// Vector is a single test vector. Binary values are base64 encoded in JSON.
+type Vector struct {
+	Name                 string `json:"name"`
+	MaxSquareSize        int    `json:"maxSquareSize"`
+	SubtreeRootThreshold int    `json:"subtreeRootThreshold"`
+	// Txs are the ordered transactions the square is constructed from.
+	Txs [][]byte `json:"txs,omitempty"`
+	// TxsFile, if set, is the path of a JSON file of the form {"txs": [...]}
+	// containing the transactions, relative to the file the vector is
+	// stored in. It is used for large datasets instead of Txs.
+	TxsFile  string   `json:"txsFile,omitempty"`
+	Expected Expected `json:"expected"`
+}
+
+// Expected is the outcome of constructing the square of a vector.
+type Expected struct {
+	// SquareSize is the width of the square.
+	SquareSize int `json:"squareSize"`
+	// SquareHash is the sha256 hash of the concatenated shares of the square.
+	SquareHash []byte `json:"squareHash"`
+	// ShareIndexes are the share indexes of the wrapped PFBs in the square,
+	// in order.
+	ShareIndexes [][]uint32 `json:"shareIndexes"`
+	// Commitments are the share commitments of the blobs of each blob
+	// transaction, in order.
+	Commitments [][][]byte `json:"commitments"`
+}
+
+// Generate constructs the square of the transactions and returns the
+// resulting vector.
+func Generate(name string, txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Vector, error) {
+	expected, err := compute(txs, maxSquareSize, subtreeRootThreshold)
+	if err != nil {
+		return Vector{}, fmt.Errorf("vector %s: %w", name, err)
+	}
+	return Vector{
+		Name:                 name,
+		MaxSquareSize:        maxSquareSize,
+		SubtreeRootThreshold: subtreeRootThreshold,
+		Txs:                  txs,
+		Expected:             expected,
+	}, nil
+}
+
+// Check constructs the square of the vector and returns an error describing
+// the first difference from the expected outcome.
+func (v Vector) Check() error {
+	actual, err := compute(v.Txs, v.MaxSquareSize, v.SubtreeRootThreshold)
+	if err != nil {
+		return fmt.Errorf("vector %s: %w", v.Name, err)
+	}
+	switch {
+	case actual.SquareSize != v.Expected.SquareSize:
+		return fmt.Errorf("vector %s: expected square size %d, got %d", v.Name, v.Expected.SquareSize, actual.SquareSize)
+	case !bytes.Equal(actual.SquareHash, v.Expected.SquareHash):
+		return fmt.Errorf("vector %s: expected square hash %X, got %X", v.Name, v.Expected.SquareHash, actual.SquareHash)
+	case !reflect.DeepEqual(actual.ShareIndexes, v.Expected.ShareIndexes):
+		return fmt.Errorf("vector %s: expected share indexes %v, got %v", v.Name, v.Expected.ShareIndexes, actual.ShareIndexes)
+	case !reflect.DeepEqual(actual.Commitments, v.Expected.Commitments):
+		return fmt.Errorf("vector %s: commitments differ", v.Name)
+	}
+	return nil
+}
+
+// SquareHash returns the sha256 hash of the concatenated shares of the
+// square.
+func SquareHash(s square.Square) []byte {
+	hash := s.Hash()
+	return hash[:]
+}
+
+func compute(txs [][]byte, maxSquareSize, subtreeRootThreshold int) (Expected, error) {
+	s, err := square.Construct(txs, maxSquareSize, subtreeRootThreshold)
+	if err != nil {
+		return Expected{}, err
+	}
+	wpfbs, err := s.WrappedPFBs()
+	if err != nil {
+		return Expected{}, err
+	}
+	shareIndexes := make([][]uint32, len(wpfbs))
+	for i, wpfbBytes := range wpfbs {
+		wpfb, isWpfb := tx.UnmarshalIndexWrapper(wpfbBytes)
+		if !isWpfb {
+			return Expected{}, fmt.Errorf("expected wrapped PFB at index %d", i)
+		}
+		shareIndexes[i] = wpfb.ShareIndexes
+	}
+	commitments := [][][]byte{}
+	for _, txBytes := range txs {
+		blobTx, isBlobTx, err := tx.UnmarshalBlobTx(txBytes)
+		if !isBlobTx {
+			continue
+		}
+		if err != nil {
+			return Expected{}, err
+		}
+		blobCommitments, err := inclusion.CreateCommitments(blobTx.Blobs, inclusion.MerkleRoot, subtreeRootThreshold)
+		if err != nil {
+			return Expected{}, err
+		}
+		commitments = append(commitments, blobCommitments)
+	}
+	return Expected{
+		SquareSize:   s.Size(),
+		SquareHash:   SquareHash(s),
+		ShareIndexes: shareIndexes,
+		Commitments:  commitments,
+	}, nil
+}
+
+// Load reads the vectors stored at path. The transactions of vectors
+// referring to a TxsFile are loaded as well.
+func Load(path string) ([]Vector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var vectors []Vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		return nil, err
+	}
+	for i, v := range vectors {
+		if v.TxsFile == "" {
+			continue
+		}
+		txs, err := LoadTxs(filepath.Join(filepath.Dir(path), v.TxsFile))
+		if err != nil {
+			return nil, fmt.Errorf("vector %s: %w", v.Name, err)
+		}
+		vectors[i].Txs = txs
+	}
+	return vectors, nil
+}
+
+// Save writes the vectors to path. The transactions of vectors with a
+// TxsFile are not written.
+func Save(path string, vectors []Vector) error {
+	stored := make([]Vector, len(vectors))
+	for i, v := range vectors {
+		if v.TxsFile != "" {
+			v.Txs = nil
+		}
+		stored[i] = v
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadTxs reads a JSON file of the form {"txs": [...]} containing base64
+// encoded transactions.
+func LoadTxs(path string) ([][]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Txs [][]byte `json:"txs"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return file.Txs, nil
+}
@@ -0,0 +1,89 @@
+package vectors_test
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/squaretest"
+	"github.com/celestiaorg/go-square/v2/vectors"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "regenerate testdata/vectors.json")
+
+const (
+	vectorsFile          = "testdata/vectors.json"
+	maxSquareSize        = 128
+	subtreeRootThreshold = 64
+)
+
+// canonicalVectors returns the vectors stored in testdata/vectors.json.
+func canonicalVectors(t *testing.T) []vectors.Vector {
+	gen := squaretest.NewGenerator(42)
+	cases := []struct {
+		name    string
+		txs     [][]byte
+		txsFile string
+	}{
+		{name: "empty"},
+		{name: "txs only", txs: gen.Txs(100, 500, 10)},
+		{name: "blobs", txs: append(gen.Txs(100, 500, 5), gen.BlobTxs(10, 2, 2000)...)},
+		{name: "signed blobs", txs: [][]byte{
+			gen.BlobTxWithNamespace([]share.Namespace{gen.Namespace(), gen.Namespace()}, []int{100, 5000}, share.ShareVersionOne),
+			gen.BlobTxWithNamespace([]share.Namespace{gen.Namespace()}, []int{share.FirstSparseShareContentSize}, share.ShareVersionOne),
+		}},
+		{name: "fibre", txs: append([][]byte{gen.FibreTx(gen.Namespace()), gen.FibreTx(gen.Namespace())}, gen.BlobTxs(3, 1, 700)...)},
+		{name: "big block", txsFile: "../../internal/testdata/big_block.json"},
+	}
+	vs := make([]vectors.Vector, len(cases))
+	for i, tc := range cases {
+		txs := tc.txs
+		if tc.txsFile != "" {
+			var err error
+			txs, err = vectors.LoadTxs(filepath.Join(filepath.Dir(vectorsFile), tc.txsFile))
+			require.NoError(t, err)
+		}
+		v, err := vectors.Generate(tc.name, txs, maxSquareSize, subtreeRootThreshold)
+		require.NoError(t, err)
+		v.TxsFile = tc.txsFile
+		vs[i] = v
+	}
+	return vs
+}
+
+func TestVectors(t *testing.T) {
+	if *update {
+		require.NoError(t, vectors.Save(vectorsFile, canonicalVectors(t)))
+	}
+
+	stored, err := vectors.Load(vectorsFile)
+	require.NoError(t, err)
+	require.NotEmpty(t, stored)
+	for _, v := range stored {
+		require.NoError(t, v.Check())
+	}
+
+	// the stored vectors match those produced by the generators
+	generated := canonicalVectors(t)
+	require.Len(t, stored, len(generated))
+	for i := range generated {
+		require.Equal(t, generated[i].Name, stored[i].Name)
+		require.Equal(t, generated[i].Expected, stored[i].Expected)
+	}
+}
+
+func TestCheckDetectsMismatch(t *testing.T) {
+	gen := squaretest.NewGenerator(1)
+	v, err := vectors.Generate("mismatch", gen.BlobTxs(2, 1, 1000), maxSquareSize, subtreeRootThreshold)
+	require.NoError(t, err)
+	require.NoError(t, v.Check())
+
+	v.Expected.SquareHash[0]++
+	require.Error(t, v.Check())
+	v.Expected.SquareHash[0]--
+
+	v.Expected.ShareIndexes[0][0]++
+	require.Error(t, v.Check())
+}
@@ -0,0 +1,109 @@
+package square
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/go-square/v2/layout"
+	"github.com/celestiaorg/go-square/v2/tx"
+)
+
+// SquareVersion identifies the set of rules used to place blobs in a square.
+// Squares built with different versions from the same transactions may
+// differ, so all parties must agree on the version used at a given height.
+type SquareVersion uint8
+
+const (
+	// SquareVersionOne is the legacy layout in which every blob starts at a
+	// multiple of its minimum square size. These are the original
+	// non-interactive default rules that ADR-013 replaced, used by
+	// celestia-app releases prior to v1.0.0 and therefore by no app version.
+	// It is kept to process squares built under those rules.
+	SquareVersionOne SquareVersion = 1
+	// SquareVersionTwo is the layout of ADR-013 in which every blob starts
+	// at a multiple of its subtree width, which is bounded by the subtree
+	// root threshold. It is used since app version 1.
+	SquareVersionTwo SquareVersion = 2
+
+	// DefaultSquareVersion is the version used by NewBuilder, Build and
+	// Construct.
+	DefaultSquareVersion = SquareVersionTwo
+)
+
+// SupportedSquareVersions returns the square versions supported by this
+// module in ascending order.
+func SupportedSquareVersions() []SquareVersion {
+	return []SquareVersion{SquareVersionOne, SquareVersionTwo}
+}
+
+// NegotiateSquareVersion returns the highest square version that is supported
+// both by this module and by the peer advertising the provided versions.
+func NegotiateSquareVersion(peerVersions ...SquareVersion) (SquareVersion, error) {
+	supported := SupportedSquareVersions()
+	for i := len(supported) - 1; i >= 0; i-- {
+		for _, v := range peerVersions {
+			if v == supported[i] {
+				return v, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no common square version: supported %v, peer supports %v", supported, peerVersions)
+}
+
+// SquareVersionForAppVersion returns the square version used by the given
+// app version. It is a thin alias: no app version has changed the placement
+// rules so far, all of them, starting with app version 1, place blobs
+// following ADR-013 and therefore use SquareVersionTwo. App version 0 does not
+// exist and is rejected.
+func SquareVersionForAppVersion(appVersion uint64) (SquareVersion, error) {
+	if appVersion == 0 {
+		return 0, fmt.Errorf("unsupported app version %d", appVersion)
+	}
+	return SquareVersionTwo, nil
+}
+
+// Validate returns an error if the square version is not supported.
+func (v SquareVersion) Validate() error {
+	switch v {
+	case SquareVersionOne, SquareVersionTwo:
+		return nil
+	default:
+		return fmt.Errorf("unsupported square version %d", uint8(v))
+	}
+}
+
+func (v SquareVersion) String() string {
+	return fmt.Sprintf("v%d", uint8(v))
+}
+
+// alignment returns the multiple of which a blob of shareCount shares must
+// start at.
+func (v SquareVersion) alignment(shareCount, subtreeRootThreshold int) int {
+	if v == SquareVersionOne {
+		return layout.BlobMinSquareSize(shareCount)
+	}
+	return layout.SubTreeWidth(shareCount, subtreeRootThreshold)
+}
+
+// nextShareIndex returns the first index at or after cursor at which a blob
+// of blobShareLen shares may start.
+func (v SquareVersion) nextShareIndex(cursor, blobShareLen, subtreeRootThreshold int) int {
+	return layout.RoundUpByMultipleOf(cursor, v.alignment(blobShareLen, subtreeRootThreshold))
+}
+
+// maxPadding returns the worst-case number of padding shares preceding a blob
+// of numShares shares.
+func (v SquareVersion) maxPadding(numShares, subtreeRootThreshold int) int {
+	if v == SquareVersionOne {
+		return layout.BlobMinSquareSize(numShares) - 1
+	}
+	return layout.MaxPadding(numShares, subtreeRootThreshold)
+}
+
+// MaxPaddingFunc returns the worst-case padding preceding a blob under the
+// rules of this version. It can be passed to tx.BlobTx.SharesNeededWithPadding
+// and tx.BlobTx.WorstCaseSharesWithPadding.
+func (v SquareVersion) MaxPaddingFunc(subtreeRootThreshold int) tx.MaxPaddingFunc {
+	return func(numShares int) int {
+		return v.maxPadding(numShares, subtreeRootThreshold)
+	}
+}
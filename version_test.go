@@ -0,0 +1,124 @@
+package square_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/inclusion"
+	"github.com/celestiaorg/go-square/v2/internal/test"
+	"github.com/celestiaorg/go-square/v2/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstructWithVersion(t *testing.T) {
+	const threshold = defaultSubtreeRootThreshold
+	txs := generateOrderedTxs(2, 4, 2, 5000)
+
+	v1Square, err := square.ConstructWithVersion(square.SquareVersionOne, txs, defaultMaxSquareSize, threshold)
+	require.NoError(t, err)
+	v2Square, err := square.ConstructWithVersion(square.SquareVersionTwo, txs, defaultMaxSquareSize, threshold)
+	require.NoError(t, err)
+	defaultSquare, err := square.Construct(txs, defaultMaxSquareSize, threshold)
+	require.NoError(t, err)
+	require.Equal(t, v2Square, defaultSquare)
+	require.NotEqual(t, v1Square, v2Square)
+
+	// both versions satisfy the blob share commitment rules and round trip
+	for _, s := range []square.Square{v1Square, v2Square} {
+		require.NoError(t, square.Validate(s, defaultMaxSquareSize, threshold))
+		deconstructed, err := square.Deconstruct(s, test.DecodeMockPFB)
+		require.NoError(t, err)
+		require.Equal(t, txs, deconstructed)
+	}
+
+	// version one aligns blobs to their minimum square size
+	builder, err := square.NewBuilderWithVersion(square.SquareVersionOne, defaultMaxSquareSize, threshold, txs...)
+	require.NoError(t, err)
+	require.Equal(t, square.SquareVersionOne, builder.Version())
+	_, err = builder.Export()
+	require.NoError(t, err)
+	for _, element := range builder.Blobs {
+		start := builder.Pfbs[element.PfbIndex].ShareIndexes[element.BlobIndex]
+		require.Zero(t, int(start)%inclusion.BlobMinSquareSize(element.NumShares))
+		require.Equal(t, inclusion.BlobMinSquareSize(element.NumShares)-1, element.MaxPadding)
+	}
+
+	_, err = square.ConstructWithVersion(square.SquareVersion(0), txs, defaultMaxSquareSize, threshold)
+	require.Error(t, err)
+	_, err = square.NewBuilderWithVersion(square.SquareVersion(3), defaultMaxSquareSize, threshold)
+	require.Error(t, err)
+}
+
+func TestBlobTxSharesWithVersion(t *testing.T) {
+	const threshold = defaultSubtreeRootThreshold
+	blobTx, isBlobTx, err := tx.UnmarshalBlobTx(test.GenerateBlobTx([]int{5000, 20000}))
+	require.NoError(t, err)
+	require.True(t, isBlobTx)
+
+	// the shares reserved by the builder match the accounting of its version
+	for _, version := range square.SupportedSquareVersions() {
+		builder, err := square.NewBuilderWithVersion(version, defaultMaxSquareSize, threshold)
+		require.NoError(t, err)
+		require.True(t, builder.AppendBlobTx(blobTx))
+		require.Equal(t, builder.CurrentSize(), blobTx.WorstCaseSharesWithPadding(version.MaxPaddingFunc(threshold)), version)
+	}
+	require.Equal(t, blobTx.SharesNeeded(threshold), blobTx.SharesNeededWithPadding(square.SquareVersionTwo.MaxPaddingFunc(threshold)))
+	require.NotEqual(t, blobTx.SharesNeeded(threshold), blobTx.SharesNeededWithPadding(square.SquareVersionOne.MaxPaddingFunc(threshold)))
+}
+
+func TestNegotiateSquareVersion(t *testing.T) {
+	version, err := square.NegotiateSquareVersion(square.SquareVersionOne, square.SquareVersionTwo, square.SquareVersion(9))
+	require.NoError(t, err)
+	require.Equal(t, square.SquareVersionTwo, version)
+
+	version, err = square.NegotiateSquareVersion(square.SquareVersionOne)
+	require.NoError(t, err)
+	require.Equal(t, square.SquareVersionOne, version)
+
+	_, err = square.NegotiateSquareVersion(square.SquareVersion(9))
+	require.Error(t, err)
+	_, err = square.NegotiateSquareVersion()
+	require.Error(t, err)
+
+	for _, v := range square.SupportedSquareVersions() {
+		require.NoError(t, v.Validate())
+	}
+	require.Equal(t, "v2", square.SquareVersionTwo.String())
+}
+
+// TestBuildForAppVersion checks the square version of each app version against
+// big_block.json, a block produced by celestia-app v1.x. See TestBigBlock.
+func TestBuildForAppVersion(t *testing.T) {
+	bigBlock := block{}
+	require.NoError(t, json.Unmarshal([]byte(bigBlockJSON), &bigBlock))
+
+	// firstBlobIndex returns the share index of the first blob of the first
+	// PFB in the square
+	firstBlobIndex := func(s square.Square) uint32 {
+		wpfbs, err := s.WrappedPFBs()
+		require.NoError(t, err)
+		wpfb, isWpfb := tx.UnmarshalIndexWrapper(wpfbs[0])
+		require.True(t, isWpfb)
+		return wpfb.ShareIndexes[0]
+	}
+
+	for _, appVersion := range []uint64{1, 2, 3} {
+		version, err := square.SquareVersionForAppVersion(appVersion)
+		require.NoError(t, err)
+		require.Equal(t, square.SquareVersionTwo, version, appVersion)
+
+		s, included, err := square.BuildForAppVersion(appVersion, bigBlock.Txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+		require.NoError(t, err)
+		require.Len(t, included, len(bigBlock.Txs))
+		require.Equal(t, uint32(2234), firstBlobIndex(s), appVersion)
+	}
+
+	// the legacy layout places the blobs of the block differently
+	s, err := square.ConstructWithVersion(square.SquareVersionOne, bigBlock.Txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.NoError(t, err)
+	require.NotEqual(t, uint32(2234), firstBlobIndex(s))
+
+	_, _, err = square.BuildForAppVersion(0, bigBlock.Txs, defaultMaxSquareSize, defaultSubtreeRootThreshold)
+	require.Error(t, err)
+}
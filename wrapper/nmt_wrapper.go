@@ -0,0 +1,84 @@
+// Package wrapper contains the erasured namespaced merkle tree used to compute
+// the row and column roots of an extended data square and the subtree roots of
+// blob share commitments. It matches pkg/wrapper of celestia-app so that all
+// users of go-square share one canonical tree configuration.
+package wrapper
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/nmt"
+)
+
+// ErasuredNamespacedMerkleTree wraps a namespaced merkle tree to compute the
+// row and column roots of an extended data square. Shares in the original
+// data square are pushed prefixed with their own namespace while parity shares
+// are prefixed with the share.ParitySharesNamespace.
+type ErasuredNamespacedMerkleTree struct {
+	squareSize uint64
+	axisIndex  uint64
+	shareIndex uint64
+	tree       *nmt.NamespacedMerkleTree
+}
+
+// NewErasuredNamespacedMerkleTree returns a tree for the row or column at
+// axisIndex of the extended data square of an original square of squareSize.
+// The tree always uses sha256, a namespace size of share.NamespaceSize and
+// ignores the max namespace. Additional options are applied before these.
+func NewErasuredNamespacedMerkleTree(squareSize uint64, axisIndex uint, options ...nmt.Option) ErasuredNamespacedMerkleTree {
+	return NewErasuredNamespacedMerkleTreeWithHasher(sha256.New(), squareSize, axisIndex, options...)
+}
+
+// NewErasuredNamespacedMerkleTreeWithHasher is like
+// NewErasuredNamespacedMerkleTree but uses h as the base hash function of the
+// tree instead of sha256. Roots computed with a different hash function are not
+// compatible with Celestia.
+func NewErasuredNamespacedMerkleTreeWithHasher(h hash.Hash, squareSize uint64, axisIndex uint, options ...nmt.Option) ErasuredNamespacedMerkleTree {
+	if squareSize == 0 {
+		panic("cannot create an ErasuredNamespacedMerkleTree of squareSize == 0")
+	}
+	options = append(options, nmt.NamespaceIDSize(share.NamespaceSize), nmt.IgnoreMaxNamespace(true))
+	return ErasuredNamespacedMerkleTree{
+		squareSize: squareSize,
+		axisIndex:  uint64(axisIndex),
+		tree:       nmt.New(h, options...),
+	}
+}
+
+// Push adds the share to the tree. Shares must be pushed in order and a share
+// must be at least share.NamespaceSize bytes.
+func (w *ErasuredNamespacedMerkleTree) Push(data []byte) error {
+	if w.axisIndex+1 > 2*w.squareSize || w.shareIndex+1 > 2*w.squareSize {
+		return fmt.Errorf("pushed past predetermined square size: boundary at %d index at %d %d", 2*w.squareSize, w.axisIndex, w.shareIndex)
+	}
+	if len(data) < share.NamespaceSize {
+		return errors.New("data is too short to contain namespace ID")
+	}
+	namespace := share.ParitySharesNamespace.Bytes()
+	if w.isQuadrantZero() {
+		namespace = data[:share.NamespaceSize]
+	}
+	leaf := make([]byte, 0, share.NamespaceSize+len(data))
+	leaf = append(leaf, namespace...)
+	leaf = append(leaf, data...)
+	if err := w.tree.Push(leaf); err != nil {
+		return err
+	}
+	w.shareIndex++
+	return nil
+}
+
+// Root returns the root of the tree.
+func (w *ErasuredNamespacedMerkleTree) Root() ([]byte, error) {
+	return w.tree.Root()
+}
+
+// isQuadrantZero returns true if the next share belongs to the original data
+// square.
+func (w *ErasuredNamespacedMerkleTree) isQuadrantZero() bool {
+	return w.shareIndex < w.squareSize && w.axisIndex < w.squareSize
+}
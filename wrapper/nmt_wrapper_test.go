@@ -0,0 +1,35 @@
+package wrapper_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/go-square/v2/wrapper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErasuredNamespacedMerkleTree(t *testing.T) {
+	sh := bytes.Repeat([]byte{1}, share.ShareSize)
+	copy(sh, share.TxNamespace.Bytes())
+
+	// in the original quadrant the share's namespace is used
+	tree := wrapper.NewErasuredNamespacedMerkleTree(1, 0)
+	require.NoError(t, tree.Push(sh))
+	require.NoError(t, tree.Push(sh))
+	root, err := tree.Root()
+	require.NoError(t, err)
+	assert.Equal(t, share.TxNamespace.Bytes(), root[:share.NamespaceSize])
+	require.Error(t, tree.Push(sh))
+
+	// in the parity quadrants the parity namespace is used
+	tree = wrapper.NewErasuredNamespacedMerkleTree(1, 1)
+	require.NoError(t, tree.Push(sh))
+	root, err = tree.Root()
+	require.NoError(t, err)
+	assert.Equal(t, share.ParitySharesNamespace.Bytes(), root[:share.NamespaceSize])
+
+	assert.Error(t, tree.Push(sh[:share.NamespaceSize-1]))
+	assert.Panics(t, func() { wrapper.NewErasuredNamespacedMerkleTree(0, 0) })
+}